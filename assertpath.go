@@ -0,0 +1,213 @@
+package bodyguard
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// AssertPath checks that the value found at a JSONPath expression in the body (as a string, []byte or io.Reader)
+// matches expected, so one deep value of a large payload can be spot-checked without building the whole
+// matcher tree:
+//
+//	bodyguard.AssertPath(t, "$.data[0].id", bodyguard.UUID(), body)
+//
+// The path supports dot-notation keys, bracketed keys such as ['content-type'], array indices and the "*"
+// wildcard, e.g. "$.data[*].id". Every value selected by a wildcard must match, and at least one value must
+// be selected. Options adjust the behaviour of this assertion only. It fails the test if there is a mismatch.
+func AssertPath(t *testing.T, path string, expected interface{}, body interface{}, opts ...Option) {
+	t.Helper()
+	defaultEngine.AssertPath(t, path, expected, body, opts...)
+}
+
+// AssertPath checks that the value found at a JSONPath expression in the body matches expected,
+// decoding it with the engine's Unmarshal function. See the package-level AssertPath.
+func (e *Engine) AssertPath(t *testing.T, path string, expected interface{}, body interface{}, opts ...Option) {
+	t.Helper()
+	e = e.with(opts)
+	e.report(t, "AssertPath "+path, expected, e.isPathMatch(path, expected, body))
+}
+
+func (e *Engine) isPathMatch(path string, expected interface{}, body interface{}) error {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return err
+	}
+	return e.isMatch(body, &pathMatcher{selector: path, segments: segments, expected: expected})
+}
+
+// AssertFlat checks the body (as a string, []byte or io.Reader) against expectations keyed by path,
+// which is more ergonomic than nested objects for spot checks:
+//
+//	bodyguard.AssertFlat(t, map[string]any{"meta.page": 1, "data[0].id": bodyguard.UUID()}, body)
+//
+// Keys are paths relative to the document root in the syntax of AssertPath, with the leading "$" optional.
+// Every path must select at least one value, and every mismatch is reported.
+// Options adjust the behaviour of this assertion only. It fails the test if there is a mismatch.
+func AssertFlat(t *testing.T, expected map[string]interface{}, body interface{}, opts ...Option) {
+	t.Helper()
+	defaultEngine.AssertFlat(t, expected, body, opts...)
+}
+
+// AssertFlat checks the body against expectations keyed by path, decoding it with the engine's
+// Unmarshal function. See the package-level AssertFlat.
+func (e *Engine) AssertFlat(t *testing.T, expected map[string]interface{}, body interface{}, opts ...Option) {
+	t.Helper()
+	e = e.with(opts)
+	e.report(t, "AssertFlat", expected, e.isFlatMatch(expected, body))
+}
+
+func (e *Engine) isFlatMatch(expected map[string]interface{}, body interface{}) error {
+	var matchers flatMatcher
+	for _, key := range slices.Sorted(maps.Keys(expected)) {
+		path := key
+		switch {
+		case strings.HasPrefix(key, "["):
+			path = "$" + key
+		case !strings.HasPrefix(key, "$"):
+			path = "$." + key
+		}
+		segments, err := parseJSONPath(path)
+		if err != nil {
+			return err
+		}
+		matchers = append(matchers, &pathMatcher{selector: path, segments: segments, expected: expected[key]})
+	}
+	return e.isMatch(body, matchers)
+}
+
+// flatMatcher matches a document against every path matcher, reporting every mismatch.
+type flatMatcher []*pathMatcher
+
+func (m flatMatcher) Match(path string, value interface{}) error {
+	return matchRoot(newMatchState(), m, path, value)
+}
+
+func (m flatMatcher) matchState(st *matchState, path string, value interface{}) error {
+	var errs []error
+	for _, pm := range m {
+		if err := pm.matchState(st, path, value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pathSegment is one step of a JSONPath expression: an object key, an array index or a wildcard.
+type pathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseJSONPath parses a JSONPath expression made of dot-notation keys, bracketed keys, indices and wildcards.
+func parseJSONPath(path string) ([]pathSegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("invalid path %q: must start with $", path)
+	}
+
+	var segments []pathSegment
+	rest := path[1:]
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, ".*"):
+			segments = append(segments, pathSegment{wildcard: true})
+			rest = rest[2:]
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid path %q: empty key", path)
+			}
+			segments = append(segments, pathSegment{key: rest[1 : end+1]})
+			rest = rest[end+1:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: missing ]", path)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+
+			switch {
+			case inner == "*":
+				segments = append(segments, pathSegment{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				segments = append(segments, pathSegment{key: inner[1 : len(inner)-1]})
+			default:
+				i, err := strconv.Atoi(inner)
+				if err != nil || i < 0 {
+					return nil, fmt.Errorf("invalid path %q: expected index, key or *, got [%s]", path, inner)
+				}
+				segments = append(segments, pathSegment{index: i, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("invalid path %q: unexpected %q", path, rest[:1])
+		}
+	}
+	return segments, nil
+}
+
+// pathMatcher matches the values selected by a JSONPath expression against expected.
+type pathMatcher struct {
+	selector string
+	segments []pathSegment
+	expected interface{}
+}
+
+func (m *pathMatcher) Match(path string, value interface{}) error {
+	return matchRoot(newMatchState(), m, path, value)
+}
+
+func (m *pathMatcher) matchState(st *matchState, path string, value interface{}) error {
+	var errs []error
+	selected := 0
+	selectPath(path, value, m.segments, func(childPath string, child interface{}) {
+		selected++
+		if err := match(st, m.expected, childPath, child); err != nil {
+			errs = append(errs, err)
+		}
+	})
+	if selected == 0 {
+		return fmt.Errorf("at %s: expected a value at %s, got none", path, m.selector)
+	}
+	return errors.Join(errs...)
+}
+
+// selectPath calls fn with every value selected by segments, in document order.
+func selectPath(path string, value interface{}, segments []pathSegment, fn func(path string, value interface{})) {
+	if _, ok := value.(ignoredValue); ok {
+		return
+	}
+	if len(segments) == 0 {
+		fn(path, value)
+		return
+	}
+
+	segment, rest := segments[0], segments[1:]
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if segment.wildcard {
+			for _, key := range slices.Sorted(maps.Keys(v)) {
+				selectPath(fmt.Sprintf("%s.%s", path, key), v[key], rest, fn)
+			}
+		} else if child, ok := v[segment.key]; ok && !segment.isIndex {
+			selectPath(fmt.Sprintf("%s.%s", path, segment.key), child, rest, fn)
+		}
+	case []interface{}:
+		if segment.wildcard {
+			for i, element := range v {
+				selectPath(elementPath(path, i, element), element, rest, fn)
+			}
+		} else if segment.isIndex && segment.index < len(v) {
+			selectPath(elementPath(path, segment.index, v[segment.index]), v[segment.index], rest, fn)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package bodyguard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssertPath(t *testing.T) {
+	body := `{"data": [{"id": "550e8400-e29b-41d4-a716-446655440000", "tags": ["a"]}, {"id": 2, "tags": []}], "meta": {"content-type": "json"}}`
+
+	tests := map[string]struct {
+		path     string
+		expected interface{}
+		wantErr  string
+	}{
+		"Index Pass": {
+			path:     "$.data[0].id",
+			expected: UUID(),
+			wantErr:  "",
+		},
+		"Bracketed Key Pass": {
+			path:     "$.meta['content-type']",
+			expected: "json",
+			wantErr:  "",
+		},
+		"Root Pass": {
+			path:     "$",
+			expected: Object(map[string]any{"meta": NotNull()}),
+			wantErr:  "",
+		},
+		"Wildcard Pass": {
+			path:     "$.data[*].tags",
+			expected: Each(String()),
+			wantErr:  "",
+		},
+		"Mismatch": {
+			path:     "$.data[1].id",
+			expected: UUID(),
+			wantErr:  "at $.data[1](id=2).id: expected string, got float64",
+		},
+		"Wildcard Mismatch": {
+			path:     "$.data.*.id",
+			expected: String(),
+			wantErr:  "at $.data[1](id=2).id: expected string, got float64",
+		},
+		"Missing Value": {
+			path:     "$.data[5].id",
+			expected: Anything(),
+			wantErr:  "at $: expected a value at $.data[5].id, got none",
+		},
+		"Invalid Path": {
+			path:     "$.data[x]",
+			expected: Anything(),
+			wantErr:  "invalid path \"$.data[x]\": expected index, key or *, got [x]",
+		},
+		"Missing Root": {
+			path:     "data",
+			expected: Anything(),
+			wantErr:  "invalid path \"data\": must start with $",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := defaultEngine.isPathMatch(tt.path, tt.expected, body)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestAssertFlat(t *testing.T) {
+	body := `{"meta": {"page": 1}, "data": [{"id": "550e8400-e29b-41d4-a716-446655440000", "name": "jdoe"}]}`
+
+	tests := map[string]struct {
+		expected map[string]any
+		wantErr  string
+	}{
+		"Pass": {
+			expected: map[string]any{"meta.page": 1, "data[0].id": UUID(), "$.data[0].name": "jdoe"},
+			wantErr:  "",
+		},
+		"Every Mismatch": {
+			expected: map[string]any{"meta.page": 2, "data[0].name": "other"},
+			wantErr: "at $.data[0](id=550e8400-e29b-41d4-a716-446655440000).name: expected other (string), got jdoe (string)\n" +
+				"at $.meta.page: expected 2 (int), got 1 (float64)",
+		},
+		"Missing Path": {
+			expected: map[string]any{"meta.total": Integer()},
+			wantErr:  "at $: expected a value at $.meta.total, got none",
+		},
+		"Invalid Path": {
+			expected: map[string]any{"data[first]": Anything()},
+			wantErr:  "invalid path \"$.data[first]\": expected index, key or *, got [first]",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := defaultEngine.isFlatMatch(tt.expected, body)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package bodyguard
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidUTF8 asserts the body is valid UTF-8. JSON decoding silently replaces invalid bytes,
+// so encoding regressions would otherwise go unnoticed.
+func ValidUTF8() BodyMatcher {
+	return describeBody(BodyMatcherFunc(func(body []byte) error {
+		for offset := 0; offset < len(body); {
+			r, size := utf8.DecodeRune(body[offset:])
+			if r == utf8.RuneError && size == 1 {
+				return fmt.Errorf("expected valid UTF-8 body, got invalid byte 0x%02x at offset %d", body[offset], offset)
+			}
+			offset += size
+		}
+		return nil
+	}), "valid UTF-8 body")
+}
+
+// NoBOM asserts the body does not start with a UTF-8 byte order mark.
+func NoBOM() BodyMatcher {
+	return describeBody(BodyMatcherFunc(func(body []byte) error {
+		if bytes.HasPrefix(body, utf8BOM) {
+			return fmt.Errorf("expected body without byte order mark")
+		}
+		return nil
+	}), "body without byte order mark")
+}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// MaxBodyBytes asserts the body is at most n bytes long.
+func MaxBodyBytes(n int) BodyMatcher {
+	return describeBody(BodyMatcherFunc(func(body []byte) error {
+		if len(body) > n {
+			return fmt.Errorf("expected body of at most %d bytes, got %d", n, len(body))
+		}
+		return nil
+	}), fmt.Sprintf("body of at most %d bytes", n))
+}
+
+// PlainNumberFormat asserts every number in the body is serialized without an exponent,
+// failing on tokens such as 1e+21 that some downstream parsers reject.
+// It works on the raw tokens, so it can only be used as the top-level expectation.
+func PlainNumberFormat() BodyMatcher {
+	return describeBody(BodyMatcherFunc(func(body []byte) error {
+		return walkTokens(body, func(path string, tok json.Token) error {
+			n, ok := tok.(json.Number)
+			if ok && strings.ContainsAny(n.String(), "eE") {
+				return fmt.Errorf("at %s: expected number without exponent, got %s", path, n)
+			}
+			return nil
+		})
+	}), "body with numbers without exponent")
+}
+
+type tokenFrame struct {
+	path    string
+	array   bool
+	index   int
+	key     string
+	wantKey bool
+}
+
+// walkTokens calls fn with the path of every scalar token in the JSON document.
+// Numbers are passed as json.Number so their original spelling is preserved.
+func walkTokens(data []byte, fn func(path string, tok json.Token) error) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var stack []*tokenFrame
+	valuePath := func() string {
+		if len(stack) == 0 {
+			return "$"
+		}
+		top := stack[len(stack)-1]
+		if top.array {
+			top.index++
+			return fmt.Sprintf("%s[%d]", top.path, top.index-1)
+		}
+		top.wantKey = true
+		return fmt.Sprintf("%s.%s", top.path, top.key)
+	}
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid json: %w", err)
+		}
+
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			stack = append(stack, &tokenFrame{path: valuePath(), array: tok == json.Delim('['), wantKey: true})
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		if len(stack) > 0 {
+			if top := stack[len(stack)-1]; !top.array && top.wantKey {
+				top.key, top.wantKey = tok.(string), false
+				continue
+			}
+		}
+
+		if err := fn(valuePath(), tok); err != nil {
+			return err
+		}
+	}
+}
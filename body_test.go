@@ -0,0 +1,111 @@
+package bodyguard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBodyMatchers(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected interface{}
+		wantErr  string
+	}{
+		// --- ValidUTF8 ---
+		"ValidUTF8 Pass": {
+			body:     `{"name": "Zoë"}`,
+			expected: ValidUTF8(),
+			wantErr:  "",
+		},
+		"ValidUTF8 Fail": {
+			body:     "{\"name\": \"Zo\xeb\"}",
+			expected: ValidUTF8(),
+			wantErr:  "expected valid UTF-8 body, got invalid byte 0xeb at offset 12",
+		},
+
+		// --- NoBOM ---
+		"NoBOM Pass": {
+			body:     `{}`,
+			expected: NoBOM(),
+			wantErr:  "",
+		},
+		"NoBOM Fail": {
+			body:     "\xef\xbb\xbf{}",
+			expected: NoBOM(),
+			wantErr:  "expected body without byte order mark",
+		},
+
+		// --- MaxBodyBytes ---
+		"MaxBodyBytes Pass": {
+			body:     `{"a": 1}`,
+			expected: MaxBodyBytes(8),
+			wantErr:  "",
+		},
+		"MaxBodyBytes Fail": {
+			body:     `{"a": 10}`,
+			expected: MaxBodyBytes(8),
+			wantErr:  "expected body of at most 8 bytes, got 9",
+		},
+
+		// --- Combined With JSON Assertions ---
+		"All Body And JSON Pass": {
+			body:     `{"name": "Zoë"}`,
+			expected: All(ValidUTF8(), NoBOM(), MaxBodyBytes(100), Object(map[string]any{"name": "Zoë"})),
+			wantErr:  "",
+		},
+		"All Body Check Fails First": {
+			body:     "\xef\xbb\xbf{}",
+			expected: All(NoBOM(), Object(map[string]any{})),
+			wantErr:  "expected body without byte order mark",
+		},
+		"All JSON Fail": {
+			body:     `{"name": "Zoe"}`,
+			expected: All(ValidUTF8(), Object(map[string]any{"name": "Zoë"})),
+			wantErr:  "at $.name: expected Zoë (string), got Zoe (string)",
+		},
+		"Nested Body Matcher": {
+			body:     `{"name": "Zoe"}`,
+			expected: Object(map[string]any{"name": ValidUTF8()}),
+			wantErr:  "at $.name: body matchers can only be used as the top-level expectation",
+		},
+
+		// --- PlainNumberFormat ---
+		"PlainNumberFormat Pass": {
+			body:     `{"a": 1, "b": [1.5, -2, 1000000000000000000000], "c": "1e+21"}`,
+			expected: PlainNumberFormat(),
+			wantErr:  "",
+		},
+		"PlainNumberFormat Scalar Fail": {
+			body:     `1e+21`,
+			expected: PlainNumberFormat(),
+			wantErr:  "at $: expected number without exponent, got 1e+21",
+		},
+		"PlainNumberFormat Nested Fail": {
+			body:     `{"data": [{"id": 1, "amount": 2.5E-7}]}`,
+			expected: PlainNumberFormat(),
+			wantErr:  "at $.data[0].amount: expected number without exponent, got 2.5E-7",
+		},
+		"PlainNumberFormat Invalid JSON": {
+			body:     `{"a": }`,
+			expected: PlainNumberFormat(),
+			wantErr:  "invalid json",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := isMatch(tt.body, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
@@ -1,13 +1,29 @@
 package bodyguard
 
 import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"math"
+	"math/big"
+	"net/http"
+	"os"
 	"reflect"
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
+	_ "time/tzdata" // embedded zone database for TZName
+	"unicode/utf8"
 )
 
 // Matcher is the core interface for all assertions.
@@ -16,8 +32,15 @@ type Matcher interface {
 	Match(path string, value interface{}) error
 }
 
+// BodyMatcher is implemented by matchers that assert on the raw serialized body
+// rather than on its decoded JSON value. They can only be used as the top-level expectation.
+type BodyMatcher interface {
+	MatchBody(body []byte) error
+}
+
 var (
-	_ Matcher = MatcherFunc(nil)
+	_ Matcher     = MatcherFunc(nil)
+	_ BodyMatcher = BodyMatcherFunc(nil)
 )
 
 // MatcherFunc is a helper for simple function-based matchers
@@ -27,56 +50,555 @@ func (m MatcherFunc) Match(path string, value interface{}) error {
 	return m(path, value)
 }
 
-// Assert checks that the given body (as a string or []byte) matches the expected structure.
+// BodyMatcherFunc is a helper for simple function-based body matchers
+type BodyMatcherFunc func(body []byte) error
+
+func (m BodyMatcherFunc) MatchBody(body []byte) error {
+	return m(body)
+}
+
+// engineBodyMatcher is implemented by body matchers that decode and match the body with the options of
+// the engine running the assertion.
+type engineBodyMatcher interface {
+	matchBodyEngine(e *Engine, body []byte) error
+}
+
+var (
+	_ BodyMatcher       = engineBodyMatcherFunc(nil)
+	_ engineBodyMatcher = engineBodyMatcherFunc(nil)
+)
+
+// engineBodyMatcherFunc is the engine-aware counterpart of BodyMatcherFunc.
+// When called through MatchBody it runs with the default engine.
+type engineBodyMatcherFunc func(e *Engine, body []byte) error
+
+func (m engineBodyMatcherFunc) MatchBody(body []byte) error {
+	return m(defaultEngine, body)
+}
+
+func (m engineBodyMatcherFunc) matchBodyEngine(e *Engine, body []byte) error {
+	return m(e, body)
+}
+
+// Engine decodes bodies and matches them against expected structures.
+// The zero value decodes with encoding/json and is ready to use.
+type Engine struct {
+	// Unmarshal decodes a raw body into v, which is always a *interface{}.
+	// It must produce the generic values of encoding/json: map[string]interface{}, []interface{},
+	// string, bool, nil, and float64 or json.Number for numbers. When nil, json.Unmarshal is used.
+	Unmarshal func(data []byte, v interface{}) error
+
+	// BeforeMatch, when set, is called before every node of the expected tree is matched,
+	// including nodes probed by matchers such as Any or UnorderedArray.
+	BeforeMatch func(path string, value interface{})
+
+	// AfterMatch, when set, is called after every node of the expected tree is matched, with its result.
+	AfterMatch func(path string, value interface{}, err error)
+
+	// IgnorePaths lists dot-separated paths, relative to the document root, that are skipped
+	// during matching. A "*" segment matches any object key or array index, e.g. "data.*.updated_at".
+	// Paths starting with "$" are JSONPath expressions, e.g. "$.data[*].updated_at".
+	IgnorePaths []string
+
+	// UnorderedArrays, when set, matches every array of the expected tree regardless of order:
+	// Array and literal arrays behave like UnorderedArray.
+	UnorderedArrays bool
+
+	// StrictObjects, when set, reports extra keys in every object of the expected tree, as if Object
+	// were StrictObject, except for objects that opt out with AllowExtra.
+	StrictObjects bool
+
+	// Epsilon, when positive, is the tolerance number literals are compared within,
+	// e.g. 1e-9 so 0.1+0.2 matches 0.3. Matchers such as Range are not affected.
+	Epsilon float64
+
+	// TimeLayouts lists the layouts, in the format of time.Parse, accepted by Timestamp and the
+	// time matchers in addition to RFC3339, e.g. "2006-01-02 15:04:05" for timestamps without offset.
+	TimeLayouts []string
+
+	// MaxErrors, when positive, caps how many mismatches a failed assertion reports.
+	// Every mismatch is reported by default.
+	MaxErrors int
+
+	// MaxBodySize, when positive, caps the size in bytes of response bodies read by DoAndAssert.
+	MaxBodySize int64
+
+	// ReadTimeout, when positive, caps how long DoAndAssert waits for the complete response,
+	// so endpoints that stream indefinitely fail instead of hanging the test.
+	ReadTimeout time.Duration
+
+	// Client, when set, sends the requests of AssertGET and its siblings, e.g. the Client of an
+	// httptest.NewTLSServer. http.DefaultClient is used otherwise.
+	Client *http.Client
+
+	// Diff, when set, appends the actual document to failures, with each mismatch marked on its line.
+	Diff bool
+
+	// PathFormat is the notation of the paths reported by failures, JSONPath by default.
+	PathFormat PathFormat
+
+	// DecimalNumbers, when set, decodes numbers as json.Number and compares number literals as
+	// arbitrary-precision decimals rather than float64, so values with 17 or more significant digits
+	// are compared exactly. Float literals are compared by their shortest decimal representation,
+	// e.g. 0.1 equals "0.10"; use a json.Number literal for values float64 cannot represent.
+	// Matchers such as Range still compare float64 values.
+	DecimalNumbers bool
+
+	// FailedPathsFile, when set, is a file the paths of mismatches are appended to, one per line.
+	FailedPathsFile string
+
+	// OnlyPathsFile, when set, is a file listing the only paths to assert, as written to FailedPathsFile.
+	// Values at other paths are skipped. A missing or empty file asserts every path.
+	OnlyPathsFile string
+
+	// Environment names the environment tests run in, for OnlyInEnv and SkipInEnv.
+	// When empty, the BODYGUARD_ENV variable is used.
+	Environment string
+
+	// ManifestFile, when set, is a file every assertion run by the engine is listed in, one JSON
+	// ManifestEntry per line, to audit which contracts a test run verifies.
+	ManifestFile string
+}
+
+var defaultEngine = &Engine{}
+
+// Assert checks that the given body (as a string, []byte or io.Reader) matches the expected structure.
+// A reader, such as resp.Body, is read to its end and closed when it is an io.ReadCloser.
 // expected can be a Matcher, or a raw value (which will be strictly compared).
+// Options adjust the behaviour of this assertion only. It fails the test if there is a mismatch.
+func Assert(t *testing.T, expected interface{}, body interface{}, opts ...Option) {
+	t.Helper()
+	defaultEngine.Assert(t, expected, body, opts...)
+}
+
+// Assert checks that the given body (as a string, []byte or io.Reader) matches the expected structure,
+// decoding it with the engine's Unmarshal function. Options adjust the engine for this assertion only.
 // It fails the test if there is a mismatch.
-func Assert(t *testing.T, expected interface{}, body interface{}) {
+func (e *Engine) Assert(t *testing.T, expected interface{}, body interface{}, opts ...Option) {
+	t.Helper()
+	e = e.with(opts)
+	e.report(t, "Assert", expected, e.isMatch(body, expected))
+}
+
+// Require checks that the body matches the expected structure, as Assert does, but stops the test with
+// t.FailNow on a mismatch, so later steps depending on the structure of the body do not run and add
+// cascading failures.
+func Require(t *testing.T, expected interface{}, body interface{}, opts ...Option) {
+	t.Helper()
+	defaultEngine.Require(t, expected, body, opts...)
+}
+
+// Require checks that the body matches the expected structure, decoding it with the engine's Unmarshal
+// function, and stops the test on a mismatch. See the package-level Require.
+func (e *Engine) Require(t *testing.T, expected interface{}, body interface{}, opts ...Option) {
 	t.Helper()
-	if err := isMatch(body, expected); err != nil {
-		t.Error(err)
+	e = e.with(opts)
+	err := e.isMatch(body, expected)
+	e.report(t, "Require", expected, err)
+	if err != nil {
+		t.FailNow()
 	}
 }
 
+// Validate checks that the body (as a string, []byte or io.Reader) matches the expected structure and
+// returns the mismatches as an error, or nil when it matches. It needs no *testing.T, for contract checks
+// in production code, test harnesses and custom assertion helpers. Mismatches are reported as a
+// *MatchError. Options adjust the behaviour of this check only.
+func Validate(expected interface{}, body interface{}, opts ...Option) error {
+	return defaultEngine.Validate(expected, body, opts...)
+}
+
+// Validate checks that the body matches the expected structure, decoding it with the engine's Unmarshal
+// function, and returns the mismatches as an error. See the package-level Validate.
+func (e *Engine) Validate(expected interface{}, body interface{}, opts ...Option) error {
+	return e.with(opts).isMatch(body, expected)
+}
+
 func isMatch(body interface{}, expected interface{}) error {
+	return defaultEngine.isMatch(body, expected)
+}
+
+func (e *Engine) isMatch(body interface{}, expected interface{}) error {
+	if r, ok := body.(io.Reader); ok {
+		return e.isReaderMatch(r, expected)
+	}
+	data, err := bodyBytes(body)
+	if err != nil {
+		return err
+	}
+	return e.matchBody(data, expected)
+}
+
+func (e *Engine) unmarshal(data []byte, v interface{}) error {
+	if e.Unmarshal != nil {
+		return e.Unmarshal(data, v)
+	}
+	if e.DecimalNumbers {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(v); err != nil {
+			return err
+		}
+		if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+			return fmt.Errorf("unexpected data after top-level value")
+		}
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (e *Engine) matchBody(data []byte, expected interface{}) error {
+	return e.matchBodyState(e.newMatchState(), data, expected)
+}
+
+// matchBodyState matches data within the given match run, so its state can be inspected afterwards.
+func (e *Engine) matchBodyState(st *matchState, data []byte, expected interface{}) error {
+	expected, err := e.matchBodyMatchers(data, expected)
+	if err != nil || expected == nil {
+		return err
+	}
+
+	actual, err := e.decodeBody(data)
+	if err != nil {
+		return err
+	}
+	return e.matchValueState(st, expected, actual)
+}
+
+// matchBodyValue matches data as matchBody does and returns the decoded body, or nil when it is not JSON.
+// The body is decoded once, even when only body matchers check it.
+func (e *Engine) matchBodyValue(data []byte, expected interface{}) (interface{}, error) {
+	decoded, decodeErr := e.decodeBody(data)
+	expected, err := e.matchBodyMatchers(data, expected)
+	if err != nil || expected == nil {
+		return decoded, err
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	return decoded, e.matchValue(expected, decoded)
+}
+
+// matchBodyMatchers runs the body matchers of expected against data. It returns the rest of expected,
+// to match against the decoded body, or nil when expected is a single body matcher.
+func (e *Engine) matchBodyMatchers(data []byte, expected interface{}) (interface{}, error) {
+	if m, ok := expected.(BodyMatcher); ok {
+		return nil, e.matchBodyMatcher(m, data)
+	}
+
+	all, ok := expected.(allMatcher)
+	if !ok {
+		return expected, nil
+	}
+	var rest allMatcher
+	for _, expected := range all {
+		if m, ok := expected.(BodyMatcher); ok {
+			if err := e.matchBodyMatcher(m, data); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		rest = append(rest, expected)
+	}
+	return rest, nil
+}
+
+// matchBodyMatcher runs a body matcher, with the options of the engine when it takes them.
+func (e *Engine) matchBodyMatcher(m BodyMatcher, data []byte) error {
+	if em, ok := m.(engineBodyMatcher); ok {
+		return em.matchBodyEngine(e, data)
+	}
+	return m.MatchBody(data)
+}
+
+// decodeBody decodes a JSON body with the engine's Unmarshal function.
+func (e *Engine) decodeBody(data []byte) (interface{}, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, fmt.Errorf("expected JSON body, got empty body")
+	}
+
 	var actual interface{}
-	var bodyBytes []byte
+	if err := e.unmarshal(data, &actual); err != nil {
+		return nil, fmt.Errorf("invalid json: %w", err)
+	}
+	return actual, nil
+}
+
+func (e *Engine) matchValue(expected interface{}, actual interface{}) error {
+	return e.matchValueState(e.newMatchState(), expected, actual)
+}
+
+func (e *Engine) matchValueState(st *matchState, expected interface{}, actual interface{}) error {
+	if len(e.IgnorePaths) > 0 {
+		var err error
+		if actual, st.ignored, err = ignorePaths(actual, e.IgnorePaths); err != nil {
+			return err
+		}
+	}
 
+	var only *pathTree
+	if e.OnlyPathsFile != "" {
+		paths, err := readPaths(e.OnlyPathsFile)
+		if err != nil {
+			return fmt.Errorf("cannot read paths to assert: %w", err)
+		}
+		if len(paths) > 0 {
+			only = newPathTree(paths)
+			actual = only.keepOnly(actual)
+		}
+	}
+
+	err := matchRoot(st, expected, "$", actual)
+	if only != nil {
+		err = only.keepErrors(err)
+	}
+	if e.FailedPathsFile != "" {
+		if recordErr := recordFailedPaths(e.FailedPathsFile, err); recordErr != nil {
+			return errors.Join(err, recordErr)
+		}
+	}
+
+	err = capErrors(err, e.MaxErrors)
+	if mErr, ok := err.(*MatchError); ok && e.PathFormat != JSONPath {
+		formatted := *mErr
+		formatted.format = e.PathFormat
+		err = &formatted
+	}
+	if err != nil && e.Diff {
+		err = fmt.Errorf("%w\n\nactual document:\n%s", err, renderDiff(actual, err, st.moves))
+	}
+	return err
+}
+
+// capErrors keeps the first max mismatches of err, noting how many were left out.
+func capErrors(err error, max int) error {
+	if err == nil || max <= 0 {
+		return err
+	}
+
+	mErr, ok := err.(*MatchError)
+	if !ok {
+		return err
+	}
+	leaves := mErr.Leaves()
+	if len(leaves) <= max {
+		return err
+	}
+
+	mismatches := make([]error, 0, max+1)
+	for _, leaf := range leaves[:max] {
+		mismatches = append(mismatches, leaf)
+	}
+	mismatches = append(mismatches, fmt.Errorf("... and %d more mismatches", len(leaves)-max))
+	return &MatchError{path: mErr.path, expected: mErr.expected, actual: mErr.actual, err: errors.Join(mismatches...)}
+}
+
+func bodyBytes(body interface{}) ([]byte, error) {
 	switch b := body.(type) {
 	case string:
-		bodyBytes = []byte(b)
+		return []byte(b), nil
 	case []byte:
-		bodyBytes = b
+		return b, nil
+	case io.Reader:
+		if c, ok := b.(io.Closer); ok {
+			defer c.Close()
+		}
+		data, err := io.ReadAll(b)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read body: %w", err)
+		}
+		return data, nil
 	default:
-		return fmt.Errorf("body must be string or []byte, got %T", body)
+		return nil, fmt.Errorf("body must be string, []byte or io.Reader, got %T", body)
+	}
+}
+
+// AssertFile checks that the JSON document stored in the named file matches the expected structure,
+// so fixtures and recorded payloads need not be loaded by the test. The whole document is decoded before
+// it is matched, so memory use grows with the size of the file. It fails the test if there is a mismatch.
+func AssertFile(t *testing.T, expected interface{}, filename string, opts ...Option) {
+	t.Helper()
+	defaultEngine.AssertFile(t, expected, filename, opts...)
+}
+
+// AssertFile checks that the document stored in the named file matches the expected structure.
+// The file is decoded with encoding/json unless a custom Unmarshal function or a body matcher needs its
+// raw bytes. It fails the test if there is a mismatch.
+func (e *Engine) AssertFile(t *testing.T, expected interface{}, filename string, opts ...Option) {
+	t.Helper()
+	e = e.with(opts)
+	e.report(t, "AssertFile "+filename, expected, e.isFileMatch(filename, expected))
+}
+
+func isFileMatch(filename string, expected interface{}) error {
+	return defaultEngine.isFileMatch(filename, expected)
+}
+
+func (e *Engine) isFileMatch(filename string, expected interface{}) error {
+	if needsRawBody(expected) || e.Unmarshal != nil {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return fmt.Errorf("cannot open body file: %w", err)
+		}
+		return e.matchBody(data, expected)
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open body file: %w", err)
 	}
+	defer f.Close()
+	return e.decodeAndMatch(f, expected)
+}
+
+// isReaderMatch matches the body read from r, closing it when it is an io.ReadCloser. The document is
+// decoded from r directly, without keeping a copy of the raw body, unless the expectation or a custom
+// Unmarshal function needs it. The whole decoded document is still held before matching.
+func (e *Engine) isReaderMatch(r io.Reader, expected interface{}) error {
+	if c, ok := r.(io.Closer); ok {
+		defer c.Close()
+	}
+	if needsRawBody(expected) || e.Unmarshal != nil {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("cannot read body: %w", err)
+		}
+		return e.matchBody(data, expected)
+	}
+	return e.decodeAndMatch(r, expected)
+}
 
-	if err := json.Unmarshal(bodyBytes, &actual); err != nil {
+// decodeAndMatch decodes a single JSON document from r and matches it. The document is decoded in full
+// before it is matched.
+func (e *Engine) decodeAndMatch(r io.Reader, expected interface{}) error {
+	var actual interface{}
+	dec := json.NewDecoder(r)
+	if e.DecimalNumbers {
+		dec.UseNumber()
+	}
+	if err := dec.Decode(&actual); err != nil {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("expected JSON body, got empty body")
+		}
 		return fmt.Errorf("invalid json: %w", err)
 	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid json: unexpected data after top-level value")
+	}
+
+	return e.matchValue(expected, actual)
+}
+
+// needsRawBody reports whether the top-level expectation holds body matchers.
+func needsRawBody(expected interface{}) bool {
+	if _, ok := expected.(BodyMatcher); ok {
+		return true
+	}
+	if all, ok := expected.(allMatcher); ok {
+		for _, e := range all {
+			if _, ok := e.(BodyMatcher); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func match(st *matchState, expected interface{}, path string, actual interface{}) error {
+	if _, ok := actual.(ignoredValue); ok {
+		return nil
+	}
 
-	return match(expected, "$", actual)
+	if st.beforeMatch != nil {
+		st.beforeMatch(path, actual)
+	}
+	err := matchNode(st, expected, path, actual)
+	if err != nil {
+		err = newMatchError(path, expected, actual, err)
+	}
+	if st.afterMatch != nil {
+		st.afterMatch(path, actual, err)
+	}
+	return err
 }
 
-func match(expected interface{}, path string, actual interface{}) error {
+func matchNode(st *matchState, expected interface{}, path string, actual interface{}) error {
+	if _, ok := expected.(BodyMatcher); ok {
+		return fmt.Errorf("at %s: body matchers can only be used as the top-level expectation", path)
+	}
+
+	if m, ok := expected.(statefulMatcher); ok {
+		return m.matchState(st, path, actual)
+	}
+
 	if m, ok := expected.(Matcher); ok {
 		return m.Match(path, actual)
 	}
 
+	if m, ok := comparatorFor(expected); ok {
+		return matchNode(st, m, path, actual)
+	}
+
+	// Literal containers are matched element by element so nested arrays are unordered too
+	if st.unorderedArrays {
+		switch e := expected.(type) {
+		case []interface{}:
+			return matchUnordered(st, path, e, actual)
+		case map[string]interface{}:
+			return StrictObject(e).matchState(st, path, actual)
+		}
+	}
+
+	// Under Ignoring they are too, as ignored values would fail an exact comparison
+	if len(st.ignored) > 0 {
+		switch e := expected.(type) {
+		case []interface{}:
+			return matchNode(st, Array(e...), path, actual)
+		case map[string]interface{}:
+			return StrictObject(e).matchState(st, path, actual)
+		}
+	}
+
 	// Exact match handling for literals
 	if reflect.DeepEqual(expected, actual) {
 		return nil
 	}
 
-	// conversions for numbers which unmarshal as float64
+	// comparison within the engine's tolerance, e.g. for computed floats
+	if st.epsilon > 0 {
+		if want, ok := literalFloat64(expected); ok {
+			if got, ok := toFloat64(actual); ok && math.Abs(want-got) <= st.epsilon {
+				return nil
+			}
+		}
+	}
+
+	// exact comparison of decimals decoded as json.Number
+	if n, ok := actual.(json.Number); ok && st.decimalNumbers {
+		if want, ok := decimalValue(expected); ok {
+			if got, ok := new(big.Rat).SetString(n.String()); ok && got.Cmp(want) == 0 {
+				return nil
+			}
+			return fmt.Errorf("at %s: expected %v (%T), got %v (%T)", path, expected, expected, actual, actual)
+		}
+	}
+
+	// conversions for numbers which unmarshal as float64 or json.Number
 	val := reflect.ValueOf(expected)
 	matched := false
 	switch val.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		if f64, ok := actual.(float64); ok {
+		if f64, ok := toFloat64(actual); ok {
 			if float64(val.Int()) == f64 {
 				matched = true
 			}
 		}
+	case reflect.Float32, reflect.Float64:
+		if n, ok := actual.(json.Number); ok {
+			if f64, ok := toFloat64(n); ok && val.Float() == f64 {
+				matched = true
+			}
+		}
 	}
 
 	if matched {
@@ -86,25 +608,54 @@ func match(expected interface{}, path string, actual interface{}) error {
 	return fmt.Errorf("at %s: expected %v (%T), got %v (%T)", path, expected, expected, actual, actual)
 }
 
-// Null asserts the value is null
+// EmptyBody asserts the body is empty or only whitespace, as for a 204 No Content response
+// or a request without a body.
+func EmptyBody() BodyMatcher {
+	return describeBody(BodyMatcherFunc(func(body []byte) error {
+		if len(bytes.TrimSpace(body)) != 0 {
+			return fmt.Errorf("expected empty body, got %d bytes", len(body))
+		}
+		return nil
+	}), "empty body")
+}
+
+// Null asserts the value is null. In an Object the key must be present; a literal nil behaves the same.
 func Null() Matcher {
-	return MatcherFunc(func(path string, value interface{}) error {
+	return describe(MatcherFunc(func(path string, value interface{}) error {
 		if value != nil {
 			return fmt.Errorf("at %s: expected null, got %v", path, value)
 		}
 		return nil
-	})
+	}), &schema{typ: "null"})
+}
+
+// Anything asserts nothing about the value, which may be of any type including null.
+// In an Object it asserts only that the key is present.
+func Anything() Matcher {
+	return describe(MatcherFunc(func(path string, value interface{}) error {
+		return nil
+	}), &schema{})
+}
+
+// NotNull asserts the value is present and not null, whatever its type.
+func NotNull() Matcher {
+	return describe(MatcherFunc(func(path string, value interface{}) error {
+		if value == nil {
+			return fmt.Errorf("at %s: expected non-null value, got null", path)
+		}
+		return nil
+	}), &schema{not: &schema{typ: "null"}})
 }
 
 // Bool asserts the value is a boolean.
 func Bool() Matcher {
-	return MatcherFunc(func(path string, value interface{}) error {
+	return describe(MatcherFunc(func(path string, value interface{}) error {
 		_, ok := value.(bool)
 		if !ok {
 			return fmt.Errorf("at %s: expected boolean, got %T", path, value)
 		}
 		return nil
-	})
+	}), &schema{typ: "boolean"})
 }
 
 func stringValue(validators ...func(string) error) Matcher {
@@ -124,37 +675,61 @@ func stringValue(validators ...func(string) error) Matcher {
 
 // String checks if the value is a string
 func String() Matcher {
-	return stringValue()
+	return describe(stringValue(), &schema{typ: "string"})
 }
 
 var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
 
 // UUID checks if the value is a valid UUID string
 func UUID() Matcher {
-	return stringValue(func(s string) error {
+	return describe(stringValue(func(s string) error {
 		if !uuidRegex.MatchString(s) {
 			return fmt.Errorf("expected UUID, got %q", s)
 		}
 		return nil
-	})
+	}), &schema{typ: "string", format: "uuid"})
+}
+
+// UUIDLiteral checks if the value is the given UUID, ignoring case.
+// expected can be a string, a [16]byte or any fmt.Stringer such as uuid.UUID.
+func UUIDLiteral(expected interface{}) Matcher {
+	var want string
+	switch u := expected.(type) {
+	case string:
+		want = u
+	case [16]byte:
+		want = fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+	case fmt.Stringer:
+		want = u.String()
+	}
+
+	return describe(stringValue(func(s string) error {
+		if !uuidRegex.MatchString(want) {
+			return fmt.Errorf("invalid UUID literal %v", expected)
+		}
+		if !strings.EqualFold(s, want) {
+			return fmt.Errorf("expected UUID %s, got %q", want, s)
+		}
+		return nil
+	}), &schema{typ: "string", format: "uuid", description: fmt.Sprintf("UUID %v", cmp.Or(want, fmt.Sprint(expected)))})
 }
 
 var emailRegex = regexp.MustCompile(`^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,4}$`)
 
 // Email checks if the value is a valid email string
 func Email() Matcher {
-	return stringValue(func(s string) error {
+	return describe(stringValue(func(s string) error {
 		if !emailRegex.MatchString(s) {
 			return fmt.Errorf("expected email, got %q", s)
 		}
 		return nil
-	})
+	}), &schema{typ: "string", format: "email"})
 }
 
 // Regexp checks if the value matches the specified regular expression
 func Regexp(pattern string) Matcher {
-	re, err := regexp.Compile(pattern)
-	return stringValue(func(s string) error {
+	re, err := compileRegexp(pattern)
+	return describe(stringValue(func(s string) error {
 		if err != nil {
 			return fmt.Errorf("invalid regexp pattern %q: %w", pattern, err)
 		}
@@ -162,80 +737,227 @@ func Regexp(pattern string) Matcher {
 			return fmt.Errorf("expected to match %q, got %q", pattern, s)
 		}
 		return nil
-	})
+	}), &schema{typ: "string", pattern: pattern})
 }
 
 // StringLength checks if the string length is within the specified range
 func StringLength(min, max int) Matcher {
-	return stringValue(func(s string) error {
+	return describe(stringValue(func(s string) error {
 		length := len(s)
 		if length < min || length > max {
 			return fmt.Errorf("expected string length between %d and %d, got %d", min, max, length)
 		}
 		return nil
-	})
+	}), &schema{typ: "string", minLength: ptr(min), maxLength: ptr(max)})
 }
 
+var urlRegex = regexp.MustCompile(`^https?://[^\s/$.?#].[^\s]*$`)
+
 // URL checks if the value is a valid URL
 func URL() Matcher {
-	return stringValue(func(s string) error {
-		if !regexp.MustCompile(`^https?://[^\s/$.?#].[^\s]*$`).MatchString(s) {
+	return describe(stringValue(func(s string) error {
+		if !urlRegex.MatchString(s) {
 			return fmt.Errorf("expected valid URL, got %q", s)
 		}
 		return nil
-	})
+	}), &schema{typ: "string", format: "uri"})
 }
 
 // OneOf checks if the value is one of the specified strings
 func OneOf(options ...string) Matcher {
-	return stringValue(func(s string) error {
+	return describe(stringValue(func(s string) error {
 		for _, opt := range options {
 			if s == opt {
 				return nil
 			}
 		}
 		return fmt.Errorf("expected one of %v, got %q", options, s)
-	})
+	}), &schema{typ: "string", enum: stringValues(options)})
 }
 
-// StringWithFormat checks if the value matches a custom string format
-func StringWithFormat(formatCheck func(string) error) Matcher {
-	return stringValue(formatCheck)
+// DigitsOnly checks if the string consists of ASCII digits only, with a length within the specified range
+func DigitsOnly(minLen, maxLen int) Matcher {
+	return describe(stringValue(func(s string) error {
+		if !isDigits(s) {
+			return fmt.Errorf("expected digits only, got %q", s)
+		}
+		if len(s) < minLen || len(s) > maxLen {
+			return fmt.Errorf("expected between %d and %d digits, got %d", minLen, maxLen, len(s))
+		}
+		return nil
+	}), &schema{typ: "string", pattern: "^[0-9]*$", minLength: ptr(minLen), maxLength: ptr(maxLen)})
 }
 
-func timeValue(parser func(string) (time.Time, error), validators ...func(time.Time) error) Matcher {
-	return MatcherFunc(func(path string, value interface{}) error {
-		s, ok := value.(string)
-		if !ok {
-			return fmt.Errorf("at %s: expected time string, got %T", path, value)
+// PaddedNumericString checks if the string is a number zero-padded to exactly width digits, e.g. "000042"
+func PaddedNumericString(width int) Matcher {
+	return describe(stringValue(func(s string) error {
+		if !isDigits(s) || len(s) != width {
+			return fmt.Errorf("expected %d-digit zero-padded number, got %q", width, s)
 		}
+		return nil
+	}), &schema{typ: "string", pattern: fmt.Sprintf("^[0-9]{%d}$", width)})
+}
 
-		parsed, err := parser(s)
-		if err != nil {
-			return fmt.Errorf("at %s: %w", path, err)
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
 		}
+	}
+	return true
+}
 
-		for _, v := range validators {
-			if err := v(parsed); err != nil {
-				return fmt.Errorf("at %s: %w", path, err)
-			}
+// MinEntropy checks if the string has at least the given Shannon entropy, in bits per character.
+// Useful to catch predictable secrets, API keys and verification codes.
+func MinEntropy(bitsPerChar float64) Matcher {
+	return describe(stringValue(func(s string) error {
+		if entropy := shannonEntropy(s); entropy < bitsPerChar {
+			return fmt.Errorf("expected entropy of at least %.2f bits per character, got %.2f", bitsPerChar, entropy)
 		}
 		return nil
-	})
+	}), &schema{typ: "string", description: fmt.Sprintf("string of at least %s bits of entropy per character", formatFloat(bitsPerChar))})
 }
 
-// Timestamp checks if the value is a valid timestamp in RFC3339 string format
-func Timestamp() Matcher {
-	return timeValue(rfc3339Parser)
+func shannonEntropy(s string) float64 {
+	counts := map[rune]int{}
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+
+	entropy := 0.0
+	for _, c := range counts {
+		p := float64(c) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
 }
 
-func rfc3339Parser(s string) (time.Time, error) {
-	return time.Parse(time.RFC3339, s)
+// NotSequential checks if the string is not a run of repeated or consecutive characters,
+// such as "aaaa", "1234" or "dcba".
+func NotSequential() Matcher {
+	return describe(stringValue(func(s string) error {
+		runes := []rune(s)
+		if len(runes) < 3 {
+			return nil
+		}
+
+		step := runes[1] - runes[0]
+		if step < -1 || step > 1 {
+			return nil
+		}
+		for i := 2; i < len(runes); i++ {
+			if runes[i]-runes[i-1] != step {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected non-sequential string, got %q", s)
+	}), &schema{typ: "string", description: "string other than a run of repeated or consecutive characters"})
 }
 
-// Date checks if the value is a valid date in the format YYYY-MM-DD string
-func Date() Matcher {
-	return timeValue(dateParser)
+// WellFormedUnicode checks if the string holds no unpaired surrogates or replacement characters (U+FFFD).
+// encoding/json decodes unpaired surrogate escapes such as "\ud83d" and invalid UTF-8 to U+FFFD,
+// so either sign of upstream encoding corruption is reported as a replacement character.
+func WellFormedUnicode() Matcher {
+	return describe(stringValue(func(s string) error {
+		for offset, r := range s {
+			if r == utf8.RuneError {
+				return fmt.Errorf("expected well-formed unicode, got %U at byte offset %d in %q", r, offset, s)
+			}
+		}
+		return nil
+	}), &schema{typ: "string", description: "string of well-formed unicode"})
+}
+
+// TZName checks if the value is an IANA time zone name such as "Europe/Paris".
+// Names are validated with time.LoadLocation against the zone database of the host, falling back to the
+// copy embedded in the binary, so they validate the same way on minimal containers without one.
+func TZName() Matcher {
+	return describe(stringValue(func(s string) error {
+		if s == "" || s == "Local" {
+			return fmt.Errorf("expected IANA time zone name, got %q", s)
+		}
+		if _, err := time.LoadLocation(s); err != nil {
+			return fmt.Errorf("expected IANA time zone name, got %q", s)
+		}
+		return nil
+	}), &schema{typ: "string", description: "IANA time zone name"})
+}
+
+// StringWithFormat checks if the value matches a custom string format
+func StringWithFormat(formatCheck func(string) error) Matcher {
+	return describe(stringValue(formatCheck), &schema{typ: "string", description: "string in a custom format"})
+}
+
+func timeValue(parser func(string) (time.Time, error), validators ...func(time.Time) error) Matcher {
+	return MatcherFunc(func(path string, value interface{}) error {
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("at %s: expected time string, got %T", path, value)
+		}
+
+		parsed, err := parser(s)
+		if err != nil {
+			return fmt.Errorf("at %s: %w", path, err)
+		}
+
+		for _, v := range validators {
+			if err := v(parsed); err != nil {
+				return fmt.Errorf("at %s: %w", path, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Timestamp checks if the value is a valid timestamp in RFC3339 string format,
+// or in one of the layouts set with WithTimeLayouts.
+func Timestamp() Matcher {
+	return describe(timestampValue(), &schema{typ: "string", format: "date-time"})
+}
+
+func rfc3339Parser(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339, s)
+}
+
+// timestampValue is timeValue for RFC3339 timestamps, also accepting the time layouts of the engine.
+func timestampValue(validators ...func(time.Time) error) Matcher {
+	rfc3339 := timeValue(rfc3339Parser, validators...)
+	return stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		if len(st.timeLayouts) == 0 {
+			return rfc3339.Match(path, value)
+		}
+		layouts := st.timeLayouts
+		return timeValue(func(s string) (time.Time, error) {
+			parsed, err := rfc3339Parser(s)
+			if err == nil {
+				return parsed, nil
+			}
+			for _, layout := range layouts {
+				if parsed, layoutErr := time.Parse(layout, s); layoutErr == nil {
+					return parsed, nil
+				}
+			}
+			return time.Time{}, err
+		}, validators...).Match(path, value)
+	})
+}
+
+// TimeLiteral checks if the value is an RFC3339 timestamp for the same instant as expected.
+// Any offset or fractional second representation of the instant matches.
+func TimeLiteral(expected time.Time) Matcher {
+	return describe(timestampValue(func(parsed time.Time) error {
+		if !parsed.Equal(expected) {
+			return fmt.Errorf("expected time %v, got %v", expected.Format(time.RFC3339Nano), parsed.Format(time.RFC3339Nano))
+		}
+		return nil
+	}), &schema{typ: "string", format: "date-time", description: "RFC3339 timestamp equal to " + expected.Format(time.RFC3339Nano)})
+}
+
+// Date checks if the value is a valid date in the format YYYY-MM-DD string
+func Date() Matcher {
+	return describe(timeValue(dateParser), &schema{typ: "string", format: "date"})
 }
 
 func dateParser(s string) (time.Time, error) {
@@ -246,262 +968,1410 @@ func dateParser(s string) (time.Time, error) {
 	return parsed, nil
 }
 
+// HTTPDate checks if the value is an RFC 7231 HTTP-date such as "Tue, 15 Nov 1994 08:12:31 GMT".
+// The parsed time is then passed as an RFC3339 timestamp to each check, so the time matchers
+// can be applied, e.g. HTTPDate(TimeAfter(start)).
+func HTTPDate(checks ...Matcher) Matcher {
+	parse := timeValue(httpDateParser)
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		if err := parse.Match(path, value); err != nil {
+			return err
+		}
+
+		parsed, _ := httpDateParser(value.(string))
+		for _, check := range checks {
+			if err := match(st, check, path, parsed.Format(time.RFC3339)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}), &schema{typ: "string", description: "HTTP-date"})
+}
+
+func httpDateParser(s string) (time.Time, error) {
+	parsed, err := time.Parse(http.TimeFormat, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected HTTP-date, got %q", s)
+	}
+	return parsed, nil
+}
+
 // TimeWithinDuration checks if the value is a valid time within the specified duration
 func TimeWithinDuration(expected time.Time, delta time.Duration) Matcher {
-	return timeValue(rfc3339Parser, func(parsed time.Time) error {
+	return describe(timestampValue(func(parsed time.Time) error {
 		if math.Abs(parsed.Sub(expected).Seconds()) > delta.Seconds() {
 			return fmt.Errorf("expected time within %v of %v, got %v", delta, expected, parsed)
 		}
 		return nil
-	})
+	}), &schema{typ: "string", format: "date-time", description: fmt.Sprintf("RFC3339 timestamp within %v of %s", delta, expected.Format(time.RFC3339Nano))})
 }
 
 // TimeWithinRange checks if the value is a valid time within the specified range
 func TimeWithinRange(startTime, endTime time.Time) Matcher {
-	return timeValue(rfc3339Parser, func(parsed time.Time) error {
+	return describe(timestampValue(func(parsed time.Time) error {
 		if parsed.Before(startTime) || parsed.After(endTime) {
 			return fmt.Errorf("expected time between %v and %v, got %v", startTime, endTime, parsed)
 		}
 		return nil
-	})
+	}), &schema{typ: "string", format: "date-time", description: fmt.Sprintf("RFC3339 timestamp between %s and %s", startTime.Format(time.RFC3339Nano), endTime.Format(time.RFC3339Nano))})
 }
 
 // TimeBefore checks if the value is a valid time before the specified time
 func TimeBefore(before time.Time) Matcher {
-	return timeValue(rfc3339Parser, func(parsed time.Time) error {
+	return describe(timestampValue(func(parsed time.Time) error {
 		if !parsed.Before(before) {
 			return fmt.Errorf("expected time before %v, got %v", before, parsed)
 		}
 		return nil
-	})
+	}), &schema{typ: "string", format: "date-time", description: "RFC3339 timestamp before " + before.Format(time.RFC3339Nano)})
 }
 
 // TimeAfter checks if the value is a valid time after the specified time
 func TimeAfter(after time.Time) Matcher {
-	return timeValue(rfc3339Parser, func(parsed time.Time) error {
+	return describe(timestampValue(func(parsed time.Time) error {
 		if !parsed.After(after) {
 			return fmt.Errorf("expected time after %v, got %v", after, parsed)
 		}
 		return nil
-	})
+	}), &schema{typ: "string", format: "date-time", description: "RFC3339 timestamp after " + after.Format(time.RFC3339Nano)})
+}
+
+// ExpiresAfterCreation asserts that the value is an object whose expiry time equals its creation time
+// plus wantTTL, within tolerance, as in token and session APIs. createdPath and expiresPath are
+// dot-separated paths relative to the object, e.g. "meta.created_at". Times are RFC3339 strings
+// or numbers of seconds since the Unix epoch.
+func ExpiresAfterCreation(createdPath, expiresPath string, wantTTL, tolerance time.Duration) Matcher {
+	return describe(MatcherFunc(func(path string, value interface{}) error {
+		created, err := timeAtPath(path, value, createdPath)
+		if err != nil {
+			return err
+		}
+		expires, err := timeAtPath(path, value, expiresPath)
+		if err != nil {
+			return err
+		}
+
+		ttl := expires.Sub(created)
+		if (ttl - wantTTL).Abs() > tolerance {
+			return fmt.Errorf("at %s.%s: expected expiry %v after %s (±%v), got %v", path, expiresPath, wantTTL, createdPath, tolerance, ttl)
+		}
+		return nil
+	}), &schema{typ: "object", description: fmt.Sprintf("object whose %s is %v after %s", expiresPath, wantTTL, createdPath)})
+}
+
+// timeAtPath returns the time found at the dot-separated path relative to value.
+func timeAtPath(path string, value interface{}, field string) (time.Time, error) {
+	current := value
+	for _, key := range strings.Split(field, ".") {
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return time.Time{}, fmt.Errorf("at %s: expected object, got %T", path, current)
+		}
+		if current, ok = obj[key]; !ok {
+			return time.Time{}, fmt.Errorf("at %s: missing key %q", path, key)
+		}
+		path = fmt.Sprintf("%s.%s", path, key)
+	}
+
+	if s, ok := current.(string); ok {
+		parsed, err := rfc3339Parser(s)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("at %s: %w", path, err)
+		}
+		return parsed, nil
+	}
+	if f, ok := toFloat64(current); ok {
+		sec, frac := math.Modf(f)
+		return time.Unix(int64(sec), int64(frac*1e9)), nil
+	}
+	return time.Time{}, fmt.Errorf("at %s: expected time string or epoch seconds, got %T", path, current)
+}
+
+// toFloat64 returns the value of a decoded JSON number, which is a float64
+// or, for decoders that preserve the raw token, a json.Number.
+func toFloat64(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f64, err := n.Float64()
+		return f64, err == nil
+	}
+	return 0, false
+}
+
+// literalFloat64 returns the value of a number literal as a float64.
+func literalFloat64(expected interface{}) (float64, bool) {
+	if n, ok := expected.(json.Number); ok {
+		return toFloat64(n)
+	}
+	val := reflect.ValueOf(expected)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), true
+	}
+	return 0, false
+}
+
+// decimalValue returns the exact decimal value of a number literal. Floats are taken at their
+// shortest decimal representation, the one they are written with in code.
+func decimalValue(expected interface{}) (*big.Rat, bool) {
+	switch n := expected.(type) {
+	case json.Number:
+		return new(big.Rat).SetString(n.String())
+	case *big.Rat:
+		return n, n != nil
+	}
+
+	val := reflect.ValueOf(expected)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Rat).SetInt64(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Rat).SetUint64(val.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return new(big.Rat).SetString(strconv.FormatFloat(val.Float(), 'g', -1, val.Type().Bits()))
+	}
+	return nil, false
 }
 
 // Number asserts the value is a number
 func Number() Matcher {
-	return MatcherFunc(func(path string, value interface{}) error {
-		_, ok := value.(float64)
+	return describe(MatcherFunc(func(path string, value interface{}) error {
+		_, ok := toFloat64(value)
 		if !ok {
 			return fmt.Errorf("at %s: expected number, got %T", path, value)
 		}
 		return nil
-	})
+	}), &schema{typ: "number"})
 }
 
 // NumberWithinDelta asserts the value is a number within a delta of the expected value
 func NumberWithinDelta(expected float64, delta float64) Matcher {
-	return MatcherFunc(func(path string, value interface{}) error {
-		f64, ok := value.(float64)
+	return describe(MatcherFunc(func(path string, value interface{}) error {
+		f64, ok := toFloat64(value)
 		if !ok {
 			return fmt.Errorf("at %s: expected number, got %T", path, value)
 		}
 
 		if math.Abs(f64-expected) > delta {
-			return fmt.Errorf("expected number within %v of %v, got %v", delta, expected, f64)
+			return fmt.Errorf("at %s: expected number within %v of %v, got %v", path, delta, expected, f64)
 		}
 
 		return nil
-	})
+	}), &schema{typ: "number", minimum: ptr(expected - delta), maximum: ptr(expected + delta)})
 }
 
 // NumberWithinRange asserts the value is a number within a range
 func NumberWithinRange(min float64, max float64) Matcher {
-	return MatcherFunc(func(path string, value interface{}) error {
-		f64, ok := value.(float64)
+	return describe(MatcherFunc(func(path string, value interface{}) error {
+		f64, ok := toFloat64(value)
 		if !ok {
 			return fmt.Errorf("at %s: expected number, got %T", path, value)
 		}
 
 		if f64 < min || f64 > max {
-			return fmt.Errorf("expected number within range %v to %v, got %v", min, max, f64)
+			return fmt.Errorf("at %s: expected number within range %v to %v, got %v", path, min, max, f64)
 		}
 
 		return nil
-	})
+	}), &schema{typ: "number", minimum: ptr(min), maximum: ptr(max)})
 }
 
 // NumberGreater asserts the value is a number greater than the minimum
 func NumberGreater(min float64) Matcher {
-	return MatcherFunc(func(path string, value interface{}) error {
-		f64, ok := value.(float64)
+	return describe(MatcherFunc(func(path string, value interface{}) error {
+		f64, ok := toFloat64(value)
 		if !ok {
 			return fmt.Errorf("at %s: expected number, got %T", path, value)
 		}
 
 		if f64 <= min {
-			return fmt.Errorf("expected number greater than %v, got %v", min, f64)
+			return fmt.Errorf("at %s: expected number greater than %v, got %v", path, min, f64)
 		}
 
 		return nil
-	})
+	}), &schema{typ: "number", exclusiveMinimum: ptr(min)})
 }
 
 // NumberSmaller asserts the value is a number smaller than the maximum
 func NumberSmaller(max float64) Matcher {
-	return MatcherFunc(func(path string, value interface{}) error {
-		f64, ok := value.(float64)
+	return describe(MatcherFunc(func(path string, value interface{}) error {
+		f64, ok := toFloat64(value)
 		if !ok {
 			return fmt.Errorf("at %s: expected number, got %T", path, value)
 		}
 
 		if f64 >= max {
-			return fmt.Errorf("expected number smaller than %v, got %v", max, f64)
+			return fmt.Errorf("at %s: expected number smaller than %v, got %v", path, max, f64)
 		}
 
 		return nil
-	})
+	}), &schema{typ: "number", exclusiveMaximum: ptr(max)})
 }
 
 // Integer asserts the value is an integer
 func Integer() Matcher {
-	return MatcherFunc(func(path string, value interface{}) error {
-		f64, ok := value.(float64)
+	return describe(MatcherFunc(func(path string, value interface{}) error {
+		f64, ok := toFloat64(value)
 		if !ok {
 			return fmt.Errorf("at %s: expected number, got %T", path, value)
 		}
 
 		if f64 != math.Trunc(f64) {
-			return fmt.Errorf("expected integer, got %v", f64)
+			return fmt.Errorf("at %s: expected integer, got %v", path, f64)
 		}
 
 		return nil
-	})
+	}), &schema{typ: "integer"})
 }
 
-// Positive asserts the value is a positive number
-func Positive() Matcher {
-	return NumberGreater(0)
+// StringAsInt checks if the value is a string holding a base 10 integer, such as "42",
+// and applies the number matchers in checks to the parsed value, e.g. StringAsInt(NumberWithinRange(1, 100)).
+func StringAsInt(checks ...Matcher) Matcher {
+	return describe(numericString("integer", func(s string) (float64, error) {
+		n, err := strconv.ParseInt(s, 10, 64)
+		return float64(n), err
+	}, checks), &schema{typ: "string", pattern: `^[-+]?[0-9]+$`})
 }
 
-// Negative asserts the value is a negative number
-func Negative() Matcher {
-	return NumberSmaller(0)
+// StringAsFloat checks if the value is a string holding a decimal number, such as "3.14",
+// and applies the number matchers in checks to the parsed value.
+func StringAsFloat(checks ...Matcher) Matcher {
+	return describe(numericString("number", func(s string) (float64, error) {
+		f, err := strconv.ParseFloat(s, 64)
+		if err == nil && (math.IsInf(f, 0) || math.IsNaN(f)) {
+			err = strconv.ErrSyntax
+		}
+		return f, err
+	}, checks), &schema{typ: "string", description: "string holding a number"})
 }
 
-// Object is a function that returns a Matcher that matches a JSON object.
-// Extra keys in the actual object are ignored (partial matching).
-func Object(expected map[string]any) Matcher {
-	return MatcherFunc(func(path string, value interface{}) error {
-		actualMap, ok := value.(map[string]any)
+func numericString(kind string, parse func(string) (float64, error), checks []Matcher) Matcher {
+	return stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		s, ok := value.(string)
 		if !ok {
-			return fmt.Errorf("at %s: expected object, got %T", path, value)
+			return fmt.Errorf("at %s: expected %s string, got %T", path, kind, value)
 		}
 
-		for key, expectedVal := range expected {
-			actualVal, exists := actualMap[key]
-			if !exists {
-				return fmt.Errorf("at %s: missing key %q", path, key)
-			}
+		parsed, err := parse(s)
+		if err != nil {
+			return fmt.Errorf("at %s: expected %s string, got %q", path, kind, s)
+		}
 
-			childPath := fmt.Sprintf("%s.%s", path, key)
-			if err := match(expectedVal, childPath, actualVal); err != nil {
+		for _, check := range checks {
+			if err := match(st, check, path, parsed); err != nil {
 				return err
 			}
 		}
-
 		return nil
 	})
 }
 
-// StrictObject is a function that returns a Matcher that matches a JSON object.
-// Extra keys in the actual object cause a mismatch error.
-func StrictObject(expected map[string]any) Matcher {
-	return MatcherFunc(func(path string, value interface{}) error {
-		actualMap, ok := value.(map[string]any)
+// GzipBase64JSON checks if the value is a string holding base64-encoded, gzip-compressed JSON, as
+// carried by some event and audit payloads, and matches the decoded document against expected.
+// Paths inside the document continue from the string's path, e.g. $.payload.user.id.
+func GzipBase64JSON(expected interface{}) Matcher {
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		s, ok := value.(string)
 		if !ok {
-			return fmt.Errorf("at %s: expected object, got %T", path, value)
+			return fmt.Errorf("at %s: expected base64 string, got %T", path, value)
 		}
 
-		for key := range actualMap {
-			if _, expectedExists := expected[key]; !expectedExists {
-				return fmt.Errorf("at %s: unexpected key %q", path, key)
-			}
+		compressed, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			compressed, err = base64.URLEncoding.DecodeString(s)
+		}
+		if err != nil {
+			return fmt.Errorf("at %s: expected base64 string, got %q", path, s)
 		}
 
-		for key, expectedVal := range expected {
-			actualVal, exists := actualMap[key]
-			if !exists {
-				return fmt.Errorf("at %s: missing key %q", path, key)
-			}
+		zr, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return fmt.Errorf("at %s: expected gzip data: %w", path, err)
+		}
+		data, err := io.ReadAll(zr)
+		if err != nil {
+			return fmt.Errorf("at %s: expected gzip data: %w", path, err)
+		}
 
-			childPath := fmt.Sprintf("%s.%s", path, key)
-			if err := match(expectedVal, childPath, actualVal); err != nil {
-				return err
-			}
+		var doc interface{}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		if st.decimalNumbers {
+			dec.UseNumber()
+		}
+		if err := dec.Decode(&doc); err != nil {
+			return fmt.Errorf("at %s: expected JSON in gzip data: %w", path, err)
+		}
+		if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+			return fmt.Errorf("at %s: expected JSON in gzip data: unexpected data after top-level value", path)
 		}
 
-		return nil
-	})
+		return match(st, expected, path, doc)
+	}), &schema{typ: "string", description: "base64 string of gzip-compressed " + Describe(expected)})
 }
 
-// Array asserts that the value is an array and matches elements in order.
-func Array(elements ...interface{}) Matcher {
-	return MatcherFunc(func(path string, value interface{}) error {
-		arr, ok := value.([]interface{})
-		if !ok {
-			return fmt.Errorf("at %s: expected array, got %T", path, value)
-		}
+// Positive asserts the value is a positive number
+func Positive() Matcher {
+	return NumberGreater(0)
+}
 
-		if len(arr) != len(elements) {
-			return fmt.Errorf("at %s: expected array length %d, got %d", path, len(elements), len(arr))
+// Negative asserts the value is a negative number
+func Negative() Matcher {
+	return NumberSmaller(0)
+}
+
+// allMatcher is returned by All. As the top-level expectation it may also hold body matchers.
+type allMatcher []interface{}
+
+func (m allMatcher) Match(path string, value interface{}) error {
+	return matchRoot(newMatchState(), m, path, value)
+}
+
+func (m allMatcher) matchState(st *matchState, path string, value interface{}) error {
+	for _, expected := range m {
+		if err := match(st, expected, path, value); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		for i, expected := range elements {
-			childPath := fmt.Sprintf("%s[%d]", path, i)
-			if err := match(expected, childPath, arr[i]); err != nil {
-				return err
+// All asserts that the value matches every one of the given matchers or literals,
+// e.g. All(ArrayLength(1, 100), Each(UUID())). Unlike the container matchers it stops at the first
+// mismatch, as later expectations usually refine earlier ones.
+// As the top-level expectation it may also hold body matchers, which are checked against the raw body
+// before it is decoded, e.g. All(ValidUTF8(), Object(...)).
+func All(expectations ...interface{}) Matcher {
+	return allMatcher(expectations)
+}
+
+// Any asserts that the value matches at least one of the given matchers or literals.
+// When none match, the error lists the failure of every alternative.
+func Any(alternatives ...interface{}) Matcher {
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		failures := make([]string, 0, len(alternatives))
+		for _, alternative := range alternatives {
+			probe := st.fork()
+			err := match(probe, alternative, path, value)
+			if err == nil {
+				st.commit(probe)
+				return nil
 			}
+			failures = append(failures, err.Error())
 		}
-		return nil
-	})
+		return fmt.Errorf("at %s: expected any of %d alternatives to match, got:\n\t- %s", path, len(alternatives), strings.Join(failures, "\n\t- "))
+	}), &schema{anyOf: schemasOf(alternatives)})
 }
 
-// UnorderedArray asserts that the value is an array containing the specified elements, in any order.
-func UnorderedArray(elements ...interface{}) Matcher {
-	return MatcherFunc(func(path string, value interface{}) error {
-		arr, ok := value.([]interface{})
-		if !ok {
-			return fmt.Errorf("at %s: expected array, got %T", path, value)
+// Not asserts that the value does not match the given matcher or literal.
+func Not(expected interface{}) Matcher {
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		if err := match(st.fork(), expected, path, value); err != nil {
+			return nil
 		}
+		if _, ok := expected.(Matcher); ok {
+			return fmt.Errorf("at %s: expected not to match %s, got %v (%T)", path, Describe(expected), value, value)
+		}
+		return fmt.Errorf("at %s: expected not to match %v (%T), got %v (%T)", path, expected, expected, value, value)
+	}), &schema{not: schemaOf(expected)})
+}
 
-		if len(arr) != len(elements) {
-			return fmt.Errorf("at %s: expected array length %d, got %d", path, len(elements), len(arr))
+// Wrap decorates a matcher or literal with functions called before and after it is matched,
+// for cross-cutting concerns such as logging or metrics. Either function may be nil.
+func Wrap(expected interface{}, before func(path string, value interface{}), after func(path string, value interface{}, err error)) Matcher {
+	return describeWrapper(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		if before != nil {
+			before(path, value)
+		}
+		err := match(st, expected, path, value)
+		if after != nil {
+			after(path, value, err)
 		}
+		return err
+	}), expected)
+}
 
-		// Create a checklist of used indices in the actual array
-		used := make([]bool, len(arr))
+// keyPresence is how an Object expects one of its keys to be present.
+type keyPresence int
 
-		// For each expected element, find a match in the actual array that hasn't been used
-		for i, expected := range elements {
-			found := false
-			for j, actual := range arr {
-				if used[j] {
-					continue
-				}
+const (
+	keyRequired keyPresence = iota
+	keyOptional
+	keyAbsent
+)
 
-				// Try to match
-				// We pass a dummy path because we are just probing
-				if err := match(expected, "probe", actual); err == nil {
-					used[j] = true
-					found = true
-					break
-				}
+// presenceMatcher is implemented by expectations that let an object key be missing, such as Optional, Absent
+// and OnlyInEnv, and by wrappers such as Label, which keep the presence of the expectation they wrap.
+// st is nil when the expectation is described rather than matched.
+type presenceMatcher interface {
+	keyPresence(st *matchState) keyPresence
+}
+
+// presenceOf returns how an Object expects a key holding expected to be present.
+func presenceOf(st *matchState, expected interface{}) keyPresence {
+	if m, ok := expected.(presenceMatcher); ok {
+		return m.keyPresence(st)
+	}
+	return keyRequired
+}
+
+// optionalMatcher marks an object key that may be absent.
+type optionalMatcher struct {
+	expected interface{}
+}
+
+func (m optionalMatcher) Match(path string, value interface{}) error {
+	return matchRoot(newMatchState(), m, path, value)
+}
+
+func (m optionalMatcher) matchState(st *matchState, path string, value interface{}) error {
+	return match(st, m.expected, path, value)
+}
+
+func (optionalMatcher) keyPresence(*matchState) keyPresence {
+	return keyOptional
+}
+
+// Optional marks a key of an Object or StrictObject as optional:
+// the key may be missing, but when present its value must match expected.
+func Optional(expected interface{}) Matcher {
+	return optionalMatcher{expected: expected}
+}
+
+// absentMatcher marks an object key that must not be present.
+type absentMatcher struct{}
+
+func (absentMatcher) Match(path string, value interface{}) error {
+	return fmt.Errorf("at %s: expected absent value, got %v", path, value)
+}
+
+func (absentMatcher) keyPresence(*matchState) keyPresence {
+	return keyAbsent
+}
+
+// Absent asserts that a key of an Object or StrictObject is not present at all.
+// Use Null(), or a literal nil, for a key that must be present with a null value,
+// and Optional(Null()) for a key that may be absent or null.
+func Absent() Matcher {
+	return absentMatcher{}
+}
+
+var (
+	_ Matcher         = (*ObjectMatcher)(nil)
+	_ statefulMatcher = (*ObjectMatcher)(nil)
+)
+
+// ObjectMatcher matches a JSON object against a map of expected keys.
+// It is returned by Object and StrictObject; Strict and AllowExtra switch between the two behaviours,
+// so subtrees of one expectation can opt in or out of strict matching.
+type ObjectMatcher struct {
+	expected map[string]any
+	strict   bool
+	// allowExtra keeps extra keys allowed under WithStrictObjects
+	allowExtra bool
+}
+
+// Object is a function that returns a Matcher that matches a JSON object.
+// Extra keys in the actual object are ignored (partial matching).
+// The values may be of any single type, e.g. map[string]Matcher for expectations built only from matchers.
+func Object[V any](expected map[string]V) *ObjectMatcher {
+	return &ObjectMatcher{expected: anyMap(expected)}
+}
+
+// StrictObject is a function that returns a Matcher that matches a JSON object.
+// Extra keys in the actual object cause a mismatch error.
+// The values may be of any single type, e.g. map[string]Matcher for expectations built only from matchers.
+func StrictObject[V any](expected map[string]V) *ObjectMatcher {
+	return &ObjectMatcher{expected: anyMap(expected), strict: true}
+}
+
+// anyMap returns m as a map[string]any, copying it only when its values have another type.
+func anyMap[V any](m map[string]V) map[string]any {
+	if expected, ok := any(m).(map[string]any); ok {
+		return expected
+	}
+	expected := make(map[string]any, len(m))
+	for key, v := range m {
+		expected[key] = expectation(v)
+	}
+	return expected
+}
+
+// Strict returns a copy of the matcher where extra keys in the actual object cause a mismatch error.
+func (m *ObjectMatcher) Strict() *ObjectMatcher {
+	return &ObjectMatcher{expected: m.expected, strict: true}
+}
+
+// AllowExtra returns a copy of the matcher where extra keys in the actual object are ignored,
+// even under WithStrictObjects.
+func (m *ObjectMatcher) AllowExtra() *ObjectMatcher {
+	return &ObjectMatcher{expected: m.expected, allowExtra: true}
+}
+
+func (m *ObjectMatcher) Match(path string, value interface{}) error {
+	return matchRoot(newMatchState(), m, path, value)
+}
+
+func (m *ObjectMatcher) matchState(st *matchState, path string, value interface{}) error {
+	actualMap, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("at %s: expected object, got %T", path, value)
+	}
+
+	var errs []error
+	if m.strict || (st.strictObjects && !m.allowExtra) {
+		for _, key := range slices.Sorted(maps.Keys(actualMap)) {
+			if _, ignored := actualMap[key].(ignoredValue); ignored {
+				continue
+			}
+			if _, expectedExists := m.expected[key]; !expectedExists {
+				errs = append(errs, fmt.Errorf("at %s: unexpected key %q", path, key))
 			}
+		}
+	}
 
-			if !found {
-				return fmt.Errorf("at %s: expected element %v (index %d) not found in remaining actual elements", path, expected, i)
+	for _, key := range slices.Sorted(maps.Keys(m.expected)) {
+		expectedVal := m.expected[key]
+		actualVal, exists := actualMap[key]
+		if _, ignored := actualVal.(ignoredValue); ignored || !exists && st.ignores(fmt.Sprintf("%s.%s", path, key)) {
+			continue
+		}
+		if !exists {
+			if presenceOf(st, expectedVal) == keyRequired {
+				errs = append(errs, missingKeyError(path, key, m.expected, actualMap))
 			}
+			continue
+		}
+		if _, absent := expectedVal.(absentMatcher); absent {
+			errs = append(errs, fmt.Errorf("at %s: expected key %q to be absent, got %v", path, key, actualVal))
+			continue
 		}
 
+		childPath := fmt.Sprintf("%s.%s", path, key)
+		if err := match(st, expectedVal, childPath, actualVal); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// MapOf asserts that the value is an object used as a map with dynamic keys, such as ids or dates:
+// every key must match keyMatcher and every value must match valueMatcher.
+// A nil keyMatcher accepts any key.
+func MapOf(keyMatcher Matcher, valueMatcher interface{}) Matcher {
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		actualMap, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("at %s: expected object, got %T", path, value)
+		}
+
+		var errs []error
+		for _, key := range slices.Sorted(maps.Keys(actualMap)) {
+			actualVal := actualMap[key]
+			if _, ignored := actualVal.(ignoredValue); ignored {
+				continue
+			}
+
+			childPath := fmt.Sprintf("%s.%s", path, key)
+			if keyMatcher != nil {
+				if err := match(st, keyMatcher, childPath, key); err != nil {
+					errs = append(errs, fmt.Errorf("invalid key: %w", err))
+					continue
+				}
+			}
+			if err := match(st, valueMatcher, childPath, actualVal); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}), &schema{typ: "object", propertyNames: schemaOf(keyMatcher), additional: schemaOf(valueMatcher)})
+}
+
+// KeysMatching asserts that the value is an object whose keys matching the regular expression pattern
+// all have values matching expected, e.g. KeysMatching(`^feature_`, Bool()). Other keys are ignored,
+// so it can be combined with Object through All.
+func KeysMatching(pattern string, expected interface{}) Matcher {
+	re, err := compileRegexp(pattern)
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		if err != nil {
+			return fmt.Errorf("invalid regexp pattern %q: %w", pattern, err)
+		}
+
+		actualMap, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("at %s: expected object, got %T", path, value)
+		}
+
+		var errs []error
+		for _, key := range slices.Sorted(maps.Keys(actualMap)) {
+			if !re.MatchString(key) {
+				continue
+			}
+			if err := match(st, expected, fmt.Sprintf("%s.%s", path, key), actualMap[key]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}), &schema{typ: "object", patternProperties: map[string]*schema{pattern: schemaOf(expected)}})
+}
+
+// ObjectSize asserts that the value is an object with a number of keys within the specified range.
+// Keys skipped through Ignoring are not counted.
+func ObjectSize(min, max int) Matcher {
+	return describe(MatcherFunc(func(path string, value interface{}) error {
+		if _, ok := value.(map[string]any); !ok {
+			return fmt.Errorf("at %s: expected object, got %T", path, value)
+		}
+
+		size, _ := valueSize(value)
+		if size < min || size > max {
+			return fmt.Errorf("at %s: expected object size between %d and %d, got %d", path, min, max, size)
+		}
+		return nil
+	}), &schema{typ: "object", minProperties: ptr(min), maxProperties: ptr(max)})
+}
+
+// Array asserts that the value is an array and matches elements in order.
+func Array(elements ...interface{}) Matcher {
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		if st.unorderedArrays {
+			return matchUnordered(st, path, elements, value)
+		}
+
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("at %s: expected array, got %T", path, value)
+		}
+
+		arr, indices := arrayElements(arr)
+		if len(arr) != len(elements) {
+			return fmt.Errorf("at %s: expected array length %d, got %d; %s", path, len(elements), len(arr), lengthMismatchDetail(elements, arr))
+		}
+
+		var errs []error
+		for i, expected := range elements {
+			childPath := elementPath(path, indices[i], arr[i])
+			if err := match(st, expected, childPath, arr[i]); err != nil {
+				errs = append(errs, err)
+				if st.moves != nil {
+					st.detectMove(path, childPath, i, elements, arr)
+				}
+			}
+		}
+		return errors.Join(errs...)
+	}), arraySchema(elements))
+}
+
+// ArrayOf is Array for elements of a single type, such as a []Matcher built at run time:
+// ArrayOf(matchers...).
+func ArrayOf[T any](elements ...T) Matcher {
+	expected := make([]interface{}, len(elements))
+	for i, element := range elements {
+		expected[i] = expectation(element)
+	}
+	return Array(expected...)
+}
+
+// ArrayPrefix asserts that the value is an array whose first elements match the specified elements in order.
+// Any further elements are ignored.
+func ArrayPrefix(elements ...interface{}) Matcher {
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("at %s: expected array, got %T", path, value)
+		}
+
+		arr, indices := arrayElements(arr)
+		if len(arr) < len(elements) {
+			return fmt.Errorf("at %s: expected array length at least %d, got %d; %s", path, len(elements), len(arr), lengthMismatchDetail(elements, arr))
+		}
+
+		var errs []error
+		for i, expected := range elements {
+			childPath := elementPath(path, indices[i], arr[i])
+			if err := match(st, expected, childPath, arr[i]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}), &schema{typ: "array", prefixItems: schemasOf(elements), minItems: ptr(len(elements))})
+}
+
+// ArrayStartsWith is an alias of ArrayPrefix, for checking the first page of paginated or streamed results.
+func ArrayStartsWith(elements ...interface{}) Matcher {
+	return ArrayPrefix(elements...)
+}
+
+// maxReportedElements caps how many missing or extra elements are listed in a length mismatch.
+const maxReportedElements = 3
+
+// lengthMismatchDetail lists the trailing elements that are missing from, or extra in, the actual array.
+func lengthMismatchDetail(expected, actual []interface{}) string {
+	kind, elements, offset := "missing", expected[min(len(actual), len(expected)):], len(actual)
+	if len(actual) > len(expected) {
+		kind, elements, offset = "extra", actual[len(expected):], len(expected)
+	}
+
+	parts := make([]string, 0, maxReportedElements+1)
+	for i, element := range elements {
+		if i == maxReportedElements {
+			parts = append(parts, fmt.Sprintf("... %d more", len(elements)-i))
+			break
+		}
+		if _, ok := element.(Matcher); ok {
+			element = "<matcher>"
+		}
+		parts = append(parts, fmt.Sprintf("[%d]=%v", offset+i, element))
+	}
+	return fmt.Sprintf("%s elements: %s", kind, strings.Join(parts, ", "))
+}
+
+// Indexed asserts that the value is an array and matches only the elements at the specified indices.
+// Elements at other indices are ignored.
+func Indexed(elements map[int]any) Matcher {
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("at %s: expected array, got %T", path, value)
+		}
+
+		indices := make([]int, 0, len(elements))
+		for i := range elements {
+			indices = append(indices, i)
+		}
+		sort.Ints(indices)
+
+		var errs []error
+		for _, i := range indices {
+			if i < 0 || i >= len(arr) {
+				errs = append(errs, fmt.Errorf("at %s: expected element at index %d, got array length %d", path, i, len(arr)))
+				continue
+			}
+
+			childPath := elementPath(path, i, arr[i])
+			if err := match(st, elements[i], childPath, arr[i]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}), &schema{typ: "array", description: describeIndexed(elements)})
+}
+
+// describeIndexed describes the elements checked by Indexed, e.g. "array with [0] "a" and [2] integer".
+func describeIndexed(elements map[int]any) string {
+	indices := slices.Sorted(maps.Keys(elements))
+	parts := make([]string, len(indices))
+	for i, index := range indices {
+		parts[i] = fmt.Sprintf("[%d] %s", index, Describe(elements[index]))
+	}
+	return "array with " + strings.Join(parts, " and ")
+}
+
+// ArrayLength asserts that the value is an array with a length within the specified range.
+// Combine it with Each to check the elements, e.g. All(ArrayLength(1, 100), Each(UUID())).
+func ArrayLength(min, max int) Matcher {
+	return describe(MatcherFunc(func(path string, value interface{}) error {
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("at %s: expected array, got %T", path, value)
+		}
+
+		arr, _ = arrayElements(arr)
+		if len(arr) < min || len(arr) > max {
+			return fmt.Errorf("at %s: expected array length between %d and %d, got %d", path, min, max, len(arr))
+		}
+		return nil
+	}), &schema{typ: "array", minItems: ptr(min), maxItems: ptr(max)})
+}
+
+// ArrayLengthExact asserts that the value is an array with exactly n elements.
+func ArrayLengthExact(n int) Matcher {
+	return describe(MatcherFunc(func(path string, value interface{}) error {
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("at %s: expected array, got %T", path, value)
+		}
+
+		arr, _ = arrayElements(arr)
+		if len(arr) != n {
+			return fmt.Errorf("at %s: expected array length %d, got %d", path, n, len(arr))
+		}
+		return nil
+	}), &schema{typ: "array", minItems: ptr(n), maxItems: ptr(n)})
+}
+
+// Empty asserts that the value is an empty string, array or object.
+func Empty() Matcher {
+	return describe(MatcherFunc(func(path string, value interface{}) error {
+		size, ok := valueSize(value)
+		if !ok {
+			return fmt.Errorf("at %s: expected string, array or object, got %T", path, value)
+		}
+
+		if size != 0 {
+			return fmt.Errorf("at %s: expected empty %s, got %v", path, kindName(value), value)
+		}
+		return nil
+	}), &schema{description: "empty string, array or object"})
+}
+
+// NotEmpty asserts that the value is a non-empty string, array or object.
+func NotEmpty() Matcher {
+	return describe(MatcherFunc(func(path string, value interface{}) error {
+		size, ok := valueSize(value)
+		if !ok {
+			return fmt.Errorf("at %s: expected string, array or object, got %T", path, value)
+		}
+
+		if size == 0 {
+			return fmt.Errorf("at %s: expected non-empty %s, got %v", path, kindName(value), value)
+		}
+		return nil
+	}), &schema{description: "non-empty string, array or object"})
+}
+
+// valueSize returns the length of a string, array or object. Ignored elements and keys are not counted.
+func valueSize(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case string:
+		return len(v), true
+	case []interface{}:
+		elements, _ := arrayElements(v)
+		return len(elements), true
+	case map[string]interface{}:
+		n := 0
+		for _, el := range v {
+			if _, ignored := el.(ignoredValue); !ignored {
+				n++
+			}
+		}
+		return n, true
+	}
+	return 0, false
+}
+
+// arrayElements returns the elements of arr that are not skipped through Ignoring, with the index of each
+// in arr, so matchers count and align only the checked elements but report the paths of the document.
+func arrayElements(arr []interface{}) ([]interface{}, []int) {
+	elements := make([]interface{}, 0, len(arr))
+	indices := make([]int, 0, len(arr))
+	for i, element := range arr {
+		if _, ignored := element.(ignoredValue); !ignored {
+			elements = append(elements, element)
+			indices = append(indices, i)
+		}
+	}
+	return elements, indices
+}
+
+// kindName names the JSON kind of a string, array or object value.
+func kindName(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// Each asserts that the value is an array and that every element matches expected.
+func Each(expected interface{}) Matcher {
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("at %s: expected array, got %T", path, value)
+		}
+
+		var errs []error
+		for i, element := range arr {
+			if err := match(st, expected, elementPath(path, i, element), element); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}), &schema{typ: "array", items: schemaOf(expected)})
+}
+
+// ArrayMatchingPattern asserts that the value is an array whose first elements match head in order
+// and whose remaining elements each match tailEach, e.g. a header row followed by data rows.
+func ArrayMatchingPattern(head []any, tailEach Matcher) Matcher {
+	// items would also apply to the head elements, so the tail is only described
+	prefixItems := schemasOf(head)
+	description := fmt.Sprintf("array starting with [%s], followed by elements of %s", joinSchemas(prefixItems, ", "), Describe(tailEach))
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("at %s: expected array, got %T", path, value)
+		}
+
+		arr, indices := arrayElements(arr)
+		if len(arr) < len(head) {
+			return fmt.Errorf("at %s: expected array length at least %d, got %d; %s", path, len(head), len(arr), lengthMismatchDetail(head, arr))
+		}
+
+		var errs []error
+		for i, element := range arr {
+			var expected interface{} = tailEach
+			if i < len(head) {
+				expected = head[i]
+			}
+			if err := match(st, expected, elementPath(path, indices[i], element), element); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}), &schema{typ: "array", prefixItems: prefixItems, minItems: ptr(len(head)), description: description})
+}
+
+// EachOneOf asserts that the value is an array of strings, each one of the options,
+// e.g. a list of tags or statuses. The error lists every offending element.
+func EachOneOf(options ...string) Matcher {
+	return describe(MatcherFunc(func(path string, value interface{}) error {
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("at %s: expected array, got %T", path, value)
+		}
+
+		arr, indices := arrayElements(arr)
+		var offending []string
+		for i, element := range arr {
+			if s, ok := element.(string); !ok || !slices.Contains(options, s) {
+				offending = append(offending, fmt.Sprintf("[%d]=%#v", indices[i], element))
+			}
+		}
+		if len(offending) > 0 {
+			return fmt.Errorf("at %s: expected every element to be one of %v, got %s", path, options, strings.Join(offending, ", "))
+		}
+		return nil
+	}), &schema{typ: "array", items: &schema{typ: "string", enum: stringValues(options)}})
+}
+
+// elementPath returns the path of the i-th element of an array.
+// When the element is an object with a scalar "id" field the id is appended,
+// e.g. $.data[3](id=42), so failures in large arrays are easy to trace.
+// Ids that could be mistaken for path syntax are quoted, e.g. $.data[3](id="a)b.c").
+func elementPath(path string, i int, element interface{}) string {
+	childPath := fmt.Sprintf("%s[%d]", path, i)
+	id, ok := elementID(element)
+	if !ok {
+		return childPath
+	}
+	if s, ok := id.(string); ok && (s == "" || strings.ContainsAny(s, "()[].\"\\ \t\n")) {
+		return fmt.Sprintf("%s(id=%s)", childPath, strconv.Quote(s))
+	}
+	return fmt.Sprintf("%s(id=%v)", childPath, id)
+}
+
+// elementID returns the scalar "id" field of an array element that is an object.
+func elementID(element interface{}) (interface{}, bool) {
+	obj, ok := element.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	switch id := obj["id"].(type) {
+	case string, float64:
+		return id, true
+	}
+	return nil, false
+}
+
+// UnorderedArray asserts that the value is an array containing the specified elements, in any order.
+func UnorderedArray(elements ...interface{}) Matcher {
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		return matchUnordered(st, path, elements, value)
+	}), &schema{typ: "array", items: &schema{anyOf: schemasOf(elements)}, minItems: ptr(len(elements)), maxItems: ptr(len(elements))})
+}
+
+func matchUnordered(st *matchState, path string, elements []interface{}, value interface{}) error {
+	arr, ok := value.([]interface{})
+	if !ok {
+		return fmt.Errorf("at %s: expected array, got %T", path, value)
+	}
+
+	arr, indices := arrayElements(arr)
+	if len(arr) != len(elements) {
+		return fmt.Errorf("at %s: expected array length %d, got %d", path, len(elements), len(arr))
+	}
+
+	unmatched := assignUnordered(st, path, elements, arr, indices)
+	if len(unmatched) > 0 {
+		return fmt.Errorf("at %s: expected %s not found in remaining actual elements", path, describeUnmatched(elements, unmatched))
+	}
+
+	return nil
+}
+
+// ArraySuperset asserts that the value is an array containing every specified element, in any order.
+// Unlike UnorderedArray, the array may hold further elements.
+func ArraySuperset(elements ...interface{}) Matcher {
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("at %s: expected array, got %T", path, value)
+		}
+
+		arr, indices := arrayElements(arr)
+		unmatched := assignUnordered(st, path, elements, arr, indices)
+		if len(unmatched) > 0 {
+			return fmt.Errorf("at %s: expected %s not found in actual elements", path, describeUnmatched(elements, unmatched))
+		}
+		return nil
+	}), &schema{typ: "array", minItems: ptr(len(elements))})
+}
+
+// ArraySubset asserts that the value is an array whose every element matches one of the specified elements,
+// in any order. Each specified element accounts for at most one actual element, but need not be present.
+func ArraySubset(elements ...interface{}) Matcher {
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("at %s: expected array, got %T", path, value)
+		}
+
+		arr, indices := arrayElements(arr)
+		probe := probeElements(st, path, elements, arr, indices)
+		paired, unmatched := assignElements(len(arr), len(elements), func(i, j int) bool {
+			return probe(j, i)
+		})
+		pairs := make([]elementPair, 0, len(arr))
+		for i, j := range paired {
+			if j >= 0 {
+				pairs = append(pairs, elementPair{expected: j, actual: i})
+			}
+		}
+		for _, pair := range commitElements(st, path, elements, arr, indices, pairs) {
+			unmatched = append(unmatched, pair.actual)
+		}
+		slices.Sort(unmatched)
+		if len(unmatched) > 0 {
+			i := unmatched[0]
+			return fmt.Errorf("at %s: unexpected element %v, not among the remaining expected elements", elementPath(path, indices[i], arr[i]), arr[i])
+		}
+		return nil
+	}), &schema{typ: "array", items: &schema{anyOf: schemasOf(elements)}, maxItems: ptr(len(elements))})
+}
+
+// probeElements returns a function reporting whether the i-th expected element matches the j-th actual one,
+// found at indices[j] of the document. Probes run on throwaway forks of the state, as most of them are
+// discarded; the pairs finally chosen are committed by commitElements.
+func probeElements(st *matchState, path string, expected, actual []interface{}, indices []int) func(i, j int) bool {
+	return func(i, j int) bool {
+		return match(st.fork(), expected[i], elementPath(path, indices[j], actual[j]), actual[j]) == nil
+	}
+}
+
+// elementPair is an expected element paired with the actual element it matches, by index.
+type elementPair struct {
+	expected, actual int
+}
+
+// commitElements matches every chosen pair again and commits its state, so Capture, SameAs, ConsistentValues
+// and CaptureAs only see the actual element each expectation was paired with. It returns the pairs whose state
+// conflicts with that of the pairs committed before them, e.g. two different values captured under one name.
+func commitElements(st *matchState, path string, expected, actual []interface{}, indices []int, pairs []elementPair) []elementPair {
+	var conflicting []elementPair
+	for _, pair := range pairs {
+		probe := st.fork()
+		if err := match(probe, expected[pair.expected], elementPath(path, indices[pair.actual], actual[pair.actual]), actual[pair.actual]); err != nil {
+			conflicting = append(conflicting, pair)
+			continue
+		}
+		st.commit(probe)
+	}
+	return conflicting
+}
+
+// assignElements pairs each of the left elements with a distinct right element it matches,
+// finding a maximum bipartite matching so overlapping expectations such as String() and "apple"
+// are paired whenever any valid assignment exists. Each pair is probed at most once.
+// It returns the right element paired with every left element, or -1, and the indices of the
+// left elements that could not be paired.
+func assignElements(left, right int, matches func(i, j int) bool) (paired, unmatched []int) {
+	const unknown, yes, no = 0, 1, 2
+	probed := make([]int8, left*right)
+	matchesOnce := func(i, j int) bool {
+		if probed[i*right+j] == unknown {
+			probed[i*right+j] = no
+			if matches(i, j) {
+				probed[i*right+j] = yes
+			}
+		}
+		return probed[i*right+j] == yes
+	}
+
+	pairedWith := make([]int, right)
+	for j := range pairedWith {
+		pairedWith[j] = -1
+	}
+
+	// augment looks for a path of alternating pairs that frees a right element for i
+	var augment func(i int, visited []bool) bool
+	augment = func(i int, visited []bool) bool {
+		for j := 0; j < right; j++ {
+			if visited[j] || !matchesOnce(i, j) {
+				continue
+			}
+			visited[j] = true
+			if pairedWith[j] < 0 || augment(pairedWith[j], visited) {
+				pairedWith[j] = i
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := 0; i < left; i++ {
+		if !augment(i, make([]bool, right)) {
+			unmatched = append(unmatched, i)
+		}
+	}
+
+	paired = make([]int, left)
+	for i := range paired {
+		paired[i] = -1
+	}
+	for j, i := range pairedWith {
+		if i >= 0 {
+			paired[i] = j
+		}
+	}
+	return paired, unmatched
+}
+
+// describeUnmatched lists the expected elements that could not be paired, e.g. "elements b (index 1), c (index 2)".
+func describeUnmatched(elements []interface{}, unmatched []int) string {
+	described := make([]string, len(unmatched))
+	for k, i := range unmatched {
+		if _, ok := elements[i].(Matcher); ok {
+			described[k] = fmt.Sprintf("%s (index %d)", Describe(elements[i]), i)
+			continue
+		}
+		described[k] = fmt.Sprintf("%v (index %d)", elements[i], i)
+	}
+	if len(described) == 1 {
+		return "element " + described[0]
+	}
+	return "elements " + strings.Join(described, ", ")
+}
+
+// Unique asserts that the value is an array without duplicate elements.
+func Unique() Matcher {
+	return describe(uniqueMatcher(func(path string, element interface{}) (interface{}, string, error) {
+		return element, path, nil
+	}), &schema{typ: "array", uniqueItems: true})
+}
+
+// UniqueBy asserts that the value is an array of objects whose values of the named field are all distinct,
+// e.g. UniqueBy("id") to catch duplicated rows.
+func UniqueBy(field string) Matcher {
+	return describe(uniqueMatcher(func(path string, element interface{}) (interface{}, string, error) {
+		obj, ok := element.(map[string]interface{})
+		if !ok {
+			return nil, path, fmt.Errorf("at %s: expected object, got %T", path, element)
+		}
+		key, ok := obj[field]
+		if !ok {
+			return nil, path, fmt.Errorf("at %s: missing key %q", path, field)
+		}
+		return key, fmt.Sprintf("%s.%s", path, field), nil
+	}), &schema{typ: "array", description: fmt.Sprintf("array of objects with distinct %q values", field)})
+}
+
+// uniqueMatcher checks the keys extracted from every array element are pairwise distinct.
+func uniqueMatcher(uniqueKey func(path string, element interface{}) (interface{}, string, error)) Matcher {
+	return MatcherFunc(func(path string, value interface{}) error {
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("at %s: expected array, got %T", path, value)
+		}
+
+		arr, indices := arrayElements(arr)
+		keys := make([]interface{}, 0, len(arr))
+		for i, element := range arr {
+			key, keyPath, err := uniqueKey(elementPath(path, indices[i], element), element)
+			if err != nil {
+				return err
+			}
+			for j, seen := range keys {
+				if reflect.DeepEqual(seen, key) {
+					return fmt.Errorf("at %s: duplicate value %v, first seen at index %d", keyPath, key, indices[j])
+				}
+			}
+			keys = append(keys, key)
+		}
+		return nil
+	})
+}
+
+// Collation compares two strings, returning a negative number when a sorts before b,
+// zero when they sort equally and a positive number otherwise.
+// The CompareString method of a golang.org/x/text/collate Collator can be used for locale-aware ordering.
+type Collation func(a, b string) int
+
+var (
+	// ByteOrder orders strings byte by byte.
+	ByteOrder Collation = strings.Compare
+
+	// CaseInsensitive orders strings ignoring case.
+	CaseInsensitive Collation = func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}
+)
+
+// SortedStrings asserts that the value is an array of strings sorted in ascending order according to collation.
+// A nil collation uses ByteOrder.
+func SortedStrings(collation Collation) Matcher {
+	if collation == nil {
+		collation = ByteOrder
+	}
+	return describe(MatcherFunc(func(path string, value interface{}) error {
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("at %s: expected array, got %T", path, value)
+		}
+
+		arr, indices := arrayElements(arr)
+		for i, element := range arr {
+			s, ok := element.(string)
+			if !ok {
+				return fmt.Errorf("at %s[%d]: expected string, got %T", path, indices[i], element)
+			}
+			if i > 0 && collation(arr[i-1].(string), s) > 0 {
+				return fmt.Errorf("at %s[%d]: expected sorted strings, got %q after %q", path, indices[i], s, arr[i-1])
+			}
+		}
+		return nil
+	}), &schema{typ: "array", items: &schema{typ: "string"}, description: "array of sorted strings"})
+}
+
+// SortOrder is the direction in which Sorted and SortedBy expect an array to be ordered.
+type SortOrder int
+
+const (
+	// Asc orders values from smallest to largest.
+	Asc SortOrder = iota
+	// Desc orders values from largest to smallest.
+	Desc
+)
+
+func (o SortOrder) String() string {
+	if o == Desc {
+		return "descending"
+	}
+	return "ascending"
+}
+
+// Sorted asserts that the value is an array of numbers or strings sorted in the given order.
+// Equal neighbours are allowed. When every string is an RFC3339 timestamp, they are compared as instants;
+// otherwise every string is compared byte-wise, so a mix of timestamps and other strings is ordered as text.
+func Sorted(order SortOrder) Matcher {
+	return describe(sortedMatcher(order, func(path string, element interface{}) (interface{}, string, error) {
+		return element, path, nil
+	}), &schema{typ: "array", description: fmt.Sprintf("array sorted in %s order", order)})
+}
+
+// SortedBy asserts that the value is an array of objects sorted in the given order by the named field,
+// e.g. SortedBy("created_at", Desc). Field values are compared as in Sorted.
+func SortedBy(field string, order SortOrder) Matcher {
+	return describe(sortedMatcher(order, func(path string, element interface{}) (interface{}, string, error) {
+		obj, ok := element.(map[string]interface{})
+		if !ok {
+			return nil, path, fmt.Errorf("at %s: expected object, got %T", path, element)
+		}
+		key, ok := obj[field]
+		if !ok {
+			return nil, path, fmt.Errorf("at %s: missing key %q", path, field)
+		}
+		return key, fmt.Sprintf("%s.%s", path, field), nil
+	}), &schema{typ: "array", description: fmt.Sprintf("array of objects sorted by %q in %s order", field, order)})
+}
+
+// sortedMatcher checks the sort keys extracted from every array element are in order.
+func sortedMatcher(order SortOrder, sortKey func(path string, element interface{}) (interface{}, string, error)) Matcher {
+	return MatcherFunc(func(path string, value interface{}) error {
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("at %s: expected array, got %T", path, value)
+		}
+
+		arr, indices := arrayElements(arr)
+		keys := make([]interface{}, len(arr))
+		keyPaths := make([]string, len(arr))
+		for i, element := range arr {
+			key, keyPath, err := sortKey(elementPath(path, indices[i], element), element)
+			if err != nil {
+				return err
+			}
+			keys[i], keyPaths[i] = key, keyPath
+		}
+
+		// timestamps are compared as instants only when every key is one, so the order is the same for every pair
+		ordered := keys
+		if instants, ok := timestamps(keys); ok {
+			ordered = instants
+		}
+		for i := 1; i < len(keys); i++ {
+			diff, ok := compareValues(ordered[i-1], ordered[i])
+			if !ok {
+				return fmt.Errorf("at %s: cannot order %v (%T) after %v (%T)", keyPaths[i], keys[i], keys[i], keys[i-1], keys[i-1])
+			}
+			if (order == Asc && diff > 0) || (order == Desc && diff < 0) {
+				return fmt.Errorf("at %s: expected %s order, got %v after %v", keyPaths[i], order, keys[i], keys[i-1])
+			}
+		}
 		return nil
 	})
 }
+
+// timestamps parses keys as RFC3339 timestamps, reporting false unless every key is one.
+func timestamps(keys []interface{}) ([]interface{}, bool) {
+	instants := make([]interface{}, len(keys))
+	for i, key := range keys {
+		s, ok := key.(string)
+		if !ok {
+			return nil, false
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, false
+		}
+		instants[i] = t
+	}
+	return instants, true
+}
+
+// compareValues orders two numbers, two strings or two instants. It reports false when the values
+// cannot be ordered.
+func compareValues(a, b interface{}) (int, bool) {
+	if x, ok := toFloat64(a); ok {
+		if y, ok := toFloat64(b); ok {
+			return cmp.Compare(x, y), true
+		}
+		return 0, false
+	}
+
+	if x, ok := a.(time.Time); ok {
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y), true
+		}
+		return 0, false
+	}
+
+	x, ok := a.(string)
+	if !ok {
+		return 0, false
+	}
+	y, ok := b.(string)
+	if !ok {
+		return 0, false
+	}
+	return strings.Compare(x, y), true
+}
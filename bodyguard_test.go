@@ -1,9 +1,18 @@
 package bodyguard
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 )
 
@@ -13,6 +22,40 @@ func TestMatchers(t *testing.T) {
 		expected interface{}
 		wantErr  string
 	}{
+		// --- Top-level Scalars ---
+		"Top-level String Pass": {
+			body:     `"ok"`,
+			expected: "ok",
+			wantErr:  "",
+		},
+		"Top-level Number Fail": {
+			body:     `42`,
+			expected: 41,
+			wantErr:  "at $: expected 41 (int), got 42 (float64)",
+		},
+
+		// --- EmptyBody ---
+		"EmptyBody Pass": {
+			body:     "",
+			expected: EmptyBody(),
+			wantErr:  "",
+		},
+		"EmptyBody Whitespace Pass": {
+			body:     " \n",
+			expected: EmptyBody(),
+			wantErr:  "",
+		},
+		"EmptyBody Fail": {
+			body:     `{}`,
+			expected: EmptyBody(),
+			wantErr:  "expected empty body, got 2 bytes",
+		},
+		"Empty Body Against Matcher": {
+			body:     "",
+			expected: Null(),
+			wantErr:  "expected JSON body, got empty body",
+		},
+
 		// --- Null ---
 		"Null Pass": {
 			body:     `null`,
@@ -25,6 +68,35 @@ func TestMatchers(t *testing.T) {
 			wantErr:  "expected null, got 123",
 		},
 
+		// --- Anything ---
+		"Anything Null Pass": {
+			body:     `null`,
+			expected: Anything(),
+			wantErr:  "",
+		},
+		"Anything Object Key Pass": {
+			body:     `{"a": [1, 2]}`,
+			expected: Object(map[string]any{"a": Anything()}),
+			wantErr:  "",
+		},
+		"Anything Missing Key Fail": {
+			body:     `{"b": 1}`,
+			expected: Object(map[string]any{"a": Anything()}),
+			wantErr:  "missing key \"a\"",
+		},
+
+		// --- NotNull ---
+		"NotNull Pass": {
+			body:     `{"a": 0}`,
+			expected: Object(map[string]any{"a": NotNull()}),
+			wantErr:  "",
+		},
+		"NotNull Fail": {
+			body:     `{"a": null}`,
+			expected: Object(map[string]any{"a": NotNull()}),
+			wantErr:  "at $.a: expected non-null value, got null",
+		},
+
 		// --- Bool ---
 		"Bool Pass": {
 			body:     `true`,
@@ -61,6 +133,28 @@ func TestMatchers(t *testing.T) {
 			wantErr:  "expected UUID, got \"not-a-uuid\"",
 		},
 
+		// --- UUIDLiteral ---
+		"UUIDLiteral Pass": {
+			body:     `"550E8400-E29B-41D4-A716-446655440000"`,
+			expected: UUIDLiteral("550e8400-e29b-41d4-a716-446655440000"),
+			wantErr:  "",
+		},
+		"UUIDLiteral Bytes Pass": {
+			body:     `"550e8400-e29b-41d4-a716-446655440000"`,
+			expected: UUIDLiteral([16]byte{0x55, 0x0e, 0x84, 0x00, 0xe2, 0x9b, 0x41, 0xd4, 0xa7, 0x16, 0x44, 0x66, 0x55, 0x44, 0x00, 0x00}),
+			wantErr:  "",
+		},
+		"UUIDLiteral Fail": {
+			body:     `"550e8400-e29b-41d4-a716-446655440001"`,
+			expected: UUIDLiteral("550e8400-e29b-41d4-a716-446655440000"),
+			wantErr:  "expected UUID 550e8400-e29b-41d4-a716-446655440000, got \"550e8400-e29b-41d4-a716-446655440001\"",
+		},
+		"UUIDLiteral Invalid Literal": {
+			body:     `"550e8400-e29b-41d4-a716-446655440000"`,
+			expected: UUIDLiteral("not-a-uuid"),
+			wantErr:  "invalid UUID literal not-a-uuid",
+		},
+
 		// --- Email ---
 		"Email Pass": {
 			body:     `"test@example.com"`,
@@ -121,6 +215,106 @@ func TestMatchers(t *testing.T) {
 			wantErr:  "expected one of [apple banana cherry], got \"pear\"",
 		},
 
+		// --- DigitsOnly ---
+		"DigitsOnly Pass": {
+			body:     `"0012345"`,
+			expected: DigitsOnly(5, 10),
+			wantErr:  "",
+		},
+		"DigitsOnly Non Digit Fail": {
+			body:     `"12a45"`,
+			expected: DigitsOnly(1, 10),
+			wantErr:  "expected digits only, got \"12a45\"",
+		},
+		"DigitsOnly Length Fail": {
+			body:     `"123"`,
+			expected: DigitsOnly(5, 10),
+			wantErr:  "expected between 5 and 10 digits, got 3",
+		},
+		"DigitsOnly Empty Pass": {
+			body:     `""`,
+			expected: DigitsOnly(0, 4),
+			wantErr:  "",
+		},
+		"DigitsOnly Empty Fail": {
+			body:     `""`,
+			expected: DigitsOnly(1, 4),
+			wantErr:  "expected between 1 and 4 digits, got 0",
+		},
+
+		// --- PaddedNumericString ---
+		"PaddedNumericString Pass": {
+			body:     `"000042"`,
+			expected: PaddedNumericString(6),
+			wantErr:  "",
+		},
+		"PaddedNumericString Unpadded Fail": {
+			body:     `"42"`,
+			expected: PaddedNumericString(6),
+			wantErr:  "expected 6-digit zero-padded number, got \"42\"",
+		},
+		"PaddedNumericString Sign Fail": {
+			body:     `"-00042"`,
+			expected: PaddedNumericString(6),
+			wantErr:  "expected 6-digit zero-padded number",
+		},
+
+		// --- MinEntropy ---
+		"MinEntropy Pass": {
+			body:     `"k3J9xQ2mZp7L"`,
+			expected: MinEntropy(3),
+			wantErr:  "",
+		},
+		"MinEntropy Fail": {
+			body:     `"aaaaaaab"`,
+			expected: MinEntropy(3),
+			wantErr:  "expected entropy of at least 3.00 bits per character, got 0.54",
+		},
+
+		// --- NotSequential ---
+		"NotSequential Pass": {
+			body:     `"4821"`,
+			expected: NotSequential(),
+			wantErr:  "",
+		},
+		"NotSequential Ascending Fail": {
+			body:     `"123456"`,
+			expected: NotSequential(),
+			wantErr:  "expected non-sequential string, got \"123456\"",
+		},
+		"NotSequential Descending Fail": {
+			body:     `"fedcba"`,
+			expected: NotSequential(),
+			wantErr:  "expected non-sequential string",
+		},
+		"NotSequential Repeated Fail": {
+			body:     `"0000"`,
+			expected: NotSequential(),
+			wantErr:  "expected non-sequential string",
+		},
+
+		// --- TZName ---
+		"TZName Pass": {
+			body:     `"Europe/Paris"`,
+			expected: TZName(),
+			wantErr:  "",
+		},
+		"TZName UTC Pass": {
+			body:     `"UTC"`,
+			expected: TZName(),
+			wantErr:  "",
+		},
+		"TZName Fail": {
+			body:     `"Europe/Atlantis"`,
+			expected: TZName(),
+			wantErr:  "expected IANA time zone name, got \"Europe/Atlantis\"",
+		},
+		"TZName Local Fail": {
+			body:     `"Local"`,
+			expected: TZName(),
+			wantErr:  "expected IANA time zone name, got \"Local\"",
+		},
+
 		// --- Timestamp (formerly RFC3339) ---
 		"Timestamp Pass": {
 			body:     `"2023-10-27T10:00:00Z"`,
@@ -145,6 +339,45 @@ func TestMatchers(t *testing.T) {
 			wantErr:  "expected YYYY-MM-DD",
 		},
 
+		// --- TimeLiteral ---
+		"TimeLiteral Pass": {
+			body:     `"2023-10-27T12:00:00+02:00"`,
+			expected: TimeLiteral(time.Date(2023, 10, 27, 10, 0, 0, 0, time.UTC)),
+			wantErr:  "",
+		},
+		"TimeLiteral Fractional Pass": {
+			body:     `"2023-10-27T10:00:00.000Z"`,
+			expected: TimeLiteral(time.Date(2023, 10, 27, 10, 0, 0, 0, time.UTC)),
+			wantErr:  "",
+		},
+		"TimeLiteral Fail": {
+			body:     `"2023-10-27T10:00:01Z"`,
+			expected: TimeLiteral(time.Date(2023, 10, 27, 10, 0, 0, 0, time.UTC)),
+			wantErr:  "expected time 2023-10-27T10:00:00Z, got 2023-10-27T10:00:01Z",
+		},
+
+		// --- HTTPDate ---
+		"HTTPDate Pass": {
+			body:     `"Tue, 15 Nov 1994 08:12:31 GMT"`,
+			expected: HTTPDate(),
+			wantErr:  "",
+		},
+		"HTTPDate Fail": {
+			body:     `"1994-11-15T08:12:31Z"`,
+			expected: HTTPDate(),
+			wantErr:  "expected HTTP-date, got \"1994-11-15T08:12:31Z\"",
+		},
+		"HTTPDate With Time Check Pass": {
+			body:     `"Tue, 15 Nov 1994 08:12:31 GMT"`,
+			expected: HTTPDate(TimeBefore(time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC))),
+			wantErr:  "",
+		},
+		"HTTPDate With Time Check Fail": {
+			body:     `"Tue, 15 Nov 1994 08:12:31 GMT"`,
+			expected: HTTPDate(TimeAfter(time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC))),
+			wantErr:  "expected time after 1995-01-01 00:00:00 +0000 UTC, got 1994-11-15 08:12:31 +0000 UTC",
+		},
+
 		// --- Time Matchers ---
 		"TimeWithinDuration Pass": {
 			body:     fmt.Sprintf("%q", time.Now().Format(time.RFC3339)),
@@ -221,6 +454,77 @@ func TestMatchers(t *testing.T) {
 			wantErr:  "expected number greater than 10, got 10",
 		},
 
+		// --- StringAsInt ---
+		"StringAsInt Pass": {
+			body:     `"42"`,
+			expected: StringAsInt(NumberWithinRange(1, 100)),
+			wantErr:  "",
+		},
+		"StringAsInt Range Fail": {
+			body:     `"142"`,
+			expected: StringAsInt(NumberWithinRange(1, 100)),
+			wantErr:  "at $: expected number within range 1 to 100, got 142",
+		},
+		"StringAsInt Not Integer": {
+			body:     `"4.2"`,
+			expected: StringAsInt(),
+			wantErr:  "at $: expected integer string, got \"4.2\"",
+		},
+		"StringAsInt Not String": {
+			body:     `42`,
+			expected: StringAsInt(),
+			wantErr:  "at $: expected integer string, got float64",
+		},
+
+		// --- StringAsFloat ---
+		"StringAsFloat Pass": {
+			body:     `"3.14"`,
+			expected: StringAsFloat(Positive(), NumberWithinDelta(3.14, 0.001)),
+			wantErr:  "",
+		},
+		"StringAsFloat Fail": {
+			body:     `"-3.14"`,
+			expected: StringAsFloat(Positive()),
+			wantErr:  "expected number greater than 0, got -3.14",
+		},
+		"StringAsFloat Not Number": {
+			body:     `"NaN"`,
+			expected: StringAsFloat(),
+			wantErr:  "at $: expected number string, got \"NaN\"",
+		},
+
+		// --- GzipBase64JSON ---
+		"GzipBase64JSON Pass": {
+			body:     `{"payload": "` + gzipBase64(`{"user": {"id": 7}}`) + `"}`,
+			expected: Object(map[string]any{"payload": GzipBase64JSON(Object(map[string]any{"user": Object(map[string]any{"id": 7})}))}),
+			wantErr:  "",
+		},
+		"GzipBase64JSON Nested Fail": {
+			body:     `{"payload": "` + gzipBase64(`{"user": {"id": "7"}}`) + `"}`,
+			expected: Object(map[string]any{"payload": GzipBase64JSON(Object(map[string]any{"user": Object(map[string]any{"id": Number()})}))}),
+			wantErr:  "at $.payload.user.id: expected number, got string",
+		},
+		"GzipBase64JSON Not Base64": {
+			body:     `"not base64!"`,
+			expected: GzipBase64JSON(Anything()),
+			wantErr:  "at $: expected base64 string, got \"not base64!\"",
+		},
+		"GzipBase64JSON Not Gzip": {
+			body:     `"eyJpZCI6IDEsICJuYW1lIjogImpkb2UifQ=="`,
+			expected: GzipBase64JSON(Anything()),
+			wantErr:  "at $: expected gzip data: gzip: invalid header",
+		},
+		"GzipBase64JSON Not JSON": {
+			body:     `"` + gzipBase64(`{"id":`) + `"`,
+			expected: GzipBase64JSON(Anything()),
+			wantErr:  "at $: expected JSON in gzip data: unexpected EOF",
+		},
+		"GzipBase64JSON Not String": {
+			body:     `42`,
+			expected: GzipBase64JSON(Anything()),
+			wantErr:  "at $: expected base64 string, got float64",
+		},
+
 		// --- Integer ---
 		"Integer Pass": {
 			body:     `123`,
@@ -257,6 +561,57 @@ func TestMatchers(t *testing.T) {
 			wantErr:  "expected number smaller than 0, got 1",
 		},
 
+		// --- All ---
+		"All Pass": {
+			body:     `"abc"`,
+			expected: All(String(), StringLength(1, 5), "abc"),
+			wantErr:  "",
+		},
+		"All Fail": {
+			body:     `"abcdef"`,
+			expected: All(String(), StringLength(1, 5)),
+			wantErr:  "expected string length between 1 and 5, got 6",
+		},
+
+		// --- Any ---
+		"Any String Pass": {
+			body:     `"abc"`,
+			expected: Any(String(), Number()),
+			wantErr:  "",
+		},
+		"Any Literal Pass": {
+			body:     `42`,
+			expected: Any(UUID(), 42),
+			wantErr:  "",
+		},
+		"Any Fail Lists Alternatives": {
+			body:     `true`,
+			expected: Any(String(), 42),
+			wantErr:  "at $: expected any of 2 alternatives to match, got:\n\t- at $: expected string, got bool\n\t- at $: expected 42 (int), got true (bool)",
+		},
+
+		// --- Not ---
+		"Not Literal Pass": {
+			body:     `"user"`,
+			expected: Not("admin"),
+			wantErr:  "",
+		},
+		"Not Literal Fail": {
+			body:     `"admin"`,
+			expected: Not("admin"),
+			wantErr:  "at $: expected not to match admin (string), got admin (string)",
+		},
+		"Not Matcher Pass": {
+			body:     `"x"`,
+			expected: Not(Null()),
+			wantErr:  "",
+		},
+		"Not Matcher Fail": {
+			body:     `null`,
+			expected: Not(Null()),
+			wantErr:  "at $: expected not to match null, got <nil> (<nil>)",
+		},
+
 		// --- Object ---
 		"Object Pass": {
 			body: `{"a": 1, "b": "s"}`,
@@ -266,6 +621,21 @@ func TestMatchers(t *testing.T) {
 			}),
 			wantErr: "",
 		},
+		"Object Of Matchers Pass": {
+			body: `{"a": 1, "b": "s"}`,
+			expected: Object(map[string]Matcher{
+				"a": Integer(),
+				"b": String(),
+			}),
+			wantErr: "",
+		},
+		"Strict Object Of Ints Mismatch": {
+			body: `{"a": 1, "b": 2}`,
+			expected: StrictObject(map[string]int{
+				"a": 1,
+			}),
+			wantErr: "at $: unexpected key \"b\"",
+		},
 		"Object Partial Pass": {
 			body: `{"a": 1, "b": "s", "c": 3}`,
 			expected: Object(map[string]any{
@@ -280,6 +650,14 @@ func TestMatchers(t *testing.T) {
 			}),
 			wantErr: "missing key \"b\"",
 		},
+		"Object Missing Key Suggestion": {
+			body: `{"id": 1, "createAt": "2023-10-27T10:00:00Z"}`,
+			expected: Object(map[string]any{
+				"id":        1,
+				"createdAt": Timestamp(),
+			}),
+			wantErr: "missing key \"createdAt\", did you mean \"createAt\"?",
+		},
 		"Object Type Mismatch": {
 			body: `{"a": 1}`,
 			expected: Object(map[string]any{
@@ -288,6 +666,88 @@ func TestMatchers(t *testing.T) {
 			wantErr: "expected string, got float64",
 		},
 
+		// --- Optional ---
+		"Optional Missing Pass": {
+			body: `{"a": 1}`,
+			expected: Object(map[string]any{
+				"a": 1,
+				"b": Optional(UUID()),
+			}),
+			wantErr: "",
+		},
+		"Optional Present Pass": {
+			body: `{"a": 1, "b": "550e8400-e29b-41d4-a716-446655440000"}`,
+			expected: Object(map[string]any{
+				"b": Optional(UUID()),
+			}),
+			wantErr: "",
+		},
+		"Optional Present Fail": {
+			body: `{"a": 1, "b": "nope"}`,
+			expected: Object(map[string]any{
+				"b": Optional(UUID()),
+			}),
+			wantErr: "at $.b: expected UUID, got \"nope\"",
+		},
+		"Optional StrictObject Missing Pass": {
+			body: `{"a": 1}`,
+			expected: StrictObject(map[string]any{
+				"a": 1,
+				"b": Optional(String()),
+			}),
+			wantErr: "",
+		},
+		"Optional StrictObject Present Pass": {
+			body: `{"a": 1, "b": "x"}`,
+			expected: StrictObject(map[string]any{
+				"a": 1,
+				"b": Optional(String()),
+			}),
+			wantErr: "",
+		},
+
+		// --- Null vs Absent ---
+		"Literal Nil Present Null Pass": {
+			body:     `{"deleted_at": null}`,
+			expected: Object(map[string]any{"deleted_at": nil}),
+			wantErr:  "",
+		},
+		"Literal Nil Missing Key Fail": {
+			body:     `{}`,
+			expected: Object(map[string]any{"deleted_at": nil}),
+			wantErr:  "missing key \"deleted_at\"",
+		},
+		"Null Missing Key Fail": {
+			body:     `{}`,
+			expected: Object(map[string]any{"deleted_at": Null()}),
+			wantErr:  "missing key \"deleted_at\"",
+		},
+		"Absent Pass": {
+			body:     `{"id": 1}`,
+			expected: Object(map[string]any{"password": Absent()}),
+			wantErr:  "",
+		},
+		"Absent Present Null Fail": {
+			body:     `{"password": null}`,
+			expected: Object(map[string]any{"password": Absent()}),
+			wantErr:  "at $: expected key \"password\" to be absent, got <nil>",
+		},
+		"Absent StrictObject Pass": {
+			body:     `{"id": 1}`,
+			expected: StrictObject(map[string]any{"id": 1, "password": Absent()}),
+			wantErr:  "",
+		},
+		"Optional Null Absent Pass": {
+			body:     `{}`,
+			expected: Object(map[string]any{"deleted_at": Optional(Null())}),
+			wantErr:  "",
+		},
+		"Optional Null Present Null Pass": {
+			body:     `{"deleted_at": null}`,
+			expected: Object(map[string]any{"deleted_at": Optional(Null())}),
+			wantErr:  "",
+		},
+
 		// --- StrictObject ---
 		"StrictObject Pass": {
 			body: `{"a": 1, "b": 2}`,
@@ -314,23 +774,251 @@ func TestMatchers(t *testing.T) {
 			wantErr: "missing key \"b\"",
 		},
 
+		// --- ObjectMatcher Strictness ---
+		"Object Strict Method Fail": {
+			body:     `{"a": 1, "b": 2}`,
+			expected: Object(map[string]any{"a": 1}).Strict(),
+			wantErr:  "unexpected key \"b\"",
+		},
+		"StrictObject AllowExtra Pass": {
+			body:     `{"a": 1, "b": 2}`,
+			expected: StrictObject(map[string]any{"a": 1}).AllowExtra(),
+			wantErr:  "",
+		},
+		"Mixed Strictness Subtrees": {
+			body: `{"user": {"id": 1, "extra": true}, "meta": {"page": 1, "debug": true}}`,
+			expected: StrictObject(map[string]any{
+				"user": Object(map[string]any{"id": 1}),
+				"meta": Object(map[string]any{"page": 1}).Strict(),
+			}),
+			wantErr: "at $.meta: unexpected key \"debug\"",
+		},
+
 		// --- Array ---
 		"Array Pass": {
 			body:     `[1, "two", true]`,
 			expected: Array(1, "two", Bool()),
 			wantErr:  "",
 		},
+		"Array Of Matchers Pass": {
+			body:     `[1, "two"]`,
+			expected: ArrayOf([]Matcher{Number(), String()}...),
+			wantErr:  "",
+		},
+		"Array Of Strings Mismatch": {
+			body:     `["a", "b"]`,
+			expected: ArrayOf("a", "c"),
+			wantErr:  "at $[1]: expected c (string), got b (string)",
+		},
 		"Array Length Mismatch": {
 			body:     `[1, 2]`,
 			expected: Array(1, 2, 3),
 			wantErr:  "expected array length 3, got 2",
 		},
+		"Array Missing Elements": {
+			body:     `[1]`,
+			expected: Array(1, 2, String()),
+			wantErr:  "at $: expected array length 3, got 1; missing elements: [1]=2, [2]=<matcher>",
+		},
+		"Array Extra Elements": {
+			body:     `[1, 2, 3, 4, 5, 6, 7]`,
+			expected: Array(1, 2),
+			wantErr:  "at $: expected array length 2, got 7; extra elements: [2]=3, [3]=4, [4]=5, ... 2 more",
+		},
 		"Array Value Mismatch": {
 			body:     `[1, 2, 3]`,
 			expected: Array(1, 4, 3),
 			wantErr:  "expected 4 (int), got 2 (float64)",
 		},
 
+		"Array Element Path With Id": {
+			body:     `[{"id": 41, "price": 1}, {"id": 42, "price": 2}]`,
+			expected: Array(Object(map[string]any{"price": 1}), Object(map[string]any{"price": 3})),
+			wantErr:  "at $[1](id=42).price: expected 3 (int), got 2 (float64)",
+		},
+
+		// --- ArrayPrefix ---
+		"ArrayPrefix Pass": {
+			body:     `[1, "two", true, 4]`,
+			expected: ArrayPrefix(1, String()),
+			wantErr:  "",
+		},
+		"ArrayPrefix Exact Length Pass": {
+			body:     `[1, 2]`,
+			expected: ArrayPrefix(1, 2),
+			wantErr:  "",
+		},
+		"ArrayPrefix Too Short": {
+			body:     `[1]`,
+			expected: ArrayPrefix(1, 2),
+			wantErr:  "at $: expected array length at least 2, got 1; missing elements: [1]=2",
+		},
+		"ArrayPrefix Value Mismatch": {
+			body:     `[1, 3, 5]`,
+			expected: ArrayPrefix(1, 2),
+			wantErr:  "at $[1]: expected 2 (int), got 3 (float64)",
+		},
+		"ArrayStartsWith Pass": {
+			body:     `{"data": [{"id": 1}, {"id": 2}, {"id": 3}]}`,
+			expected: Object(map[string]any{"data": ArrayStartsWith(Object(map[string]any{"id": 1}))}),
+			wantErr:  "",
+		},
+		"ArrayStartsWith Mismatch Path": {
+			body:     `{"data": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}]}`,
+			expected: Object(map[string]any{"data": ArrayStartsWith(Anything(), Object(map[string]any{"name": "c"}))}),
+			wantErr:  "at $.data[1](id=2).name: expected c (string), got b (string)",
+		},
+
+		// --- ArrayMatchingPattern ---
+		"ArrayMatchingPattern Pass": {
+			body:     `[{"columns": ["name", "total"]}, ["a", 1], ["b", 2]]`,
+			expected: ArrayMatchingPattern([]any{Object(map[string]any{"columns": Array("name", "total")})}, Array(String(), Number())),
+			wantErr:  "",
+		},
+		"ArrayMatchingPattern Head Only Pass": {
+			body:     `[{"columns": []}]`,
+			expected: ArrayMatchingPattern([]any{Object(map[string]any{"columns": Empty()})}, Array(String(), Number())),
+			wantErr:  "",
+		},
+		"ArrayMatchingPattern Head Mismatch": {
+			body:     `[["a", 1], ["b", 2]]`,
+			expected: ArrayMatchingPattern([]any{Object(map[string]any{"columns": Anything()})}, Array(String(), Number())),
+			wantErr:  "at $[0]: expected object",
+		},
+		"ArrayMatchingPattern Tail Mismatch": {
+			body:     `[{"columns": ["name", "total"]}, ["a", 1], ["b", "2"]]`,
+			expected: ArrayMatchingPattern([]any{Anything()}, Array(String(), Number())),
+			wantErr:  "at $[2][1]: expected number",
+		},
+		"ArrayMatchingPattern Too Short": {
+			body:     `[]`,
+			expected: ArrayMatchingPattern([]any{Anything()}, Number()),
+			wantErr:  "at $: expected array length at least 1, got 0",
+		},
+
+		// --- Indexed ---
+		"Indexed Pass": {
+			body:     `[1, "two", true, null]`,
+			expected: Indexed(map[int]any{1: String(), 3: Null()}),
+			wantErr:  "",
+		},
+		"Indexed Value Mismatch": {
+			body:     `[{"id": "a", "price": 1}, {"id": "b", "price": 2}]`,
+			expected: Indexed(map[int]any{1: Object(map[string]any{"price": 5})}),
+			wantErr:  "at $[1](id=b).price: expected 5 (int), got 2 (float64)",
+		},
+		"Indexed Out Of Range": {
+			body:     `[1, 2]`,
+			expected: Indexed(map[int]any{5: 1}),
+			wantErr:  "expected element at index 5, got array length 2",
+		},
+		"Indexed Not Array": {
+			body:     `{}`,
+			expected: Indexed(map[int]any{0: 1}),
+			wantErr:  "expected array, got map[string]interface {}",
+		},
+
+		// --- Empty ---
+		"Empty String Pass": {
+			body:     `{"a": ""}`,
+			expected: Object(map[string]any{"a": Empty()}),
+			wantErr:  "",
+		},
+		"Empty Array Pass": {
+			body:     `[]`,
+			expected: Empty(),
+			wantErr:  "",
+		},
+		"Empty Object Pass": {
+			body:     `{}`,
+			expected: Empty(),
+			wantErr:  "",
+		},
+		"Empty Array Fail": {
+			body:     `{"items": [1]}`,
+			expected: Object(map[string]any{"items": Empty()}),
+			wantErr:  "at $.items: expected empty array, got [1]",
+		},
+		"Empty String Fail": {
+			body:     `"x"`,
+			expected: Empty(),
+			wantErr:  "at $: expected empty string, got x",
+		},
+		"Empty Wrong Type": {
+			body:     `0`,
+			expected: Empty(),
+			wantErr:  "at $: expected string, array or object, got float64",
+		},
+		"NotEmpty Pass": {
+			body:     `{"a": {"b": 1}}`,
+			expected: Object(map[string]any{"a": NotEmpty()}),
+			wantErr:  "",
+		},
+		"NotEmpty Fail": {
+			body:     `{}`,
+			expected: NotEmpty(),
+			wantErr:  "at $: expected non-empty object, got map[]",
+		},
+
+		// --- ArrayLength ---
+		"ArrayLength Pass": {
+			body:     `[1, 2, 3]`,
+			expected: ArrayLength(1, 3),
+			wantErr:  "",
+		},
+		"ArrayLength Empty Fail": {
+			body:     `[]`,
+			expected: ArrayLength(1, 100),
+			wantErr:  "at $: expected array length between 1 and 100, got 0",
+		},
+		"ArrayLength Not Array": {
+			body:     `{}`,
+			expected: ArrayLength(1, 100),
+			wantErr:  "expected array, got map[string]interface {}",
+		},
+		"ArrayLengthExact Pass": {
+			body:     `[1, 2]`,
+			expected: ArrayLengthExact(2),
+			wantErr:  "",
+		},
+		"ArrayLengthExact Fail": {
+			body:     `[1, 2]`,
+			expected: ArrayLengthExact(3),
+			wantErr:  "at $: expected array length 3, got 2",
+		},
+		"ArrayLength With Each Pass": {
+			body:     `["550e8400-e29b-41d4-a716-446655440000"]`,
+			expected: All(ArrayLength(1, 100), Each(UUID())),
+			wantErr:  "",
+		},
+		"ArrayLength With Each Fail": {
+			body:     `[]`,
+			expected: All(ArrayLength(1, 100), Each(UUID())),
+			wantErr:  "expected array length between 1 and 100, got 0",
+		},
+
+		// --- Each ---
+		"Each Pass": {
+			body:     `["550e8400-e29b-41d4-a716-446655440000", "6ba7b810-9dad-11d1-80b4-00c04fd430c8"]`,
+			expected: Each(UUID()),
+			wantErr:  "",
+		},
+		"Each Empty Pass": {
+			body:     `[]`,
+			expected: Each(UUID()),
+			wantErr:  "",
+		},
+		"Each Fail Reports Index": {
+			body:     `[{"id": 1, "price": 5}, {"id": 2, "price": -1}]`,
+			expected: Each(Object(map[string]any{"price": Positive()})),
+			wantErr:  "at $[1](id=2).price: expected number greater than 0, got -1",
+		},
+		"Each Not Array": {
+			body:     `"x"`,
+			expected: Each(String()),
+			wantErr:  "expected array, got string",
+		},
+
 		// --- UnorderedArray ---
 		"UnorderedArray Pass": {
 			body:     `[3, 1, 2]`,
@@ -347,6 +1035,313 @@ func TestMatchers(t *testing.T) {
 			expected: UnorderedArray(1, 2, 4),
 			wantErr:  "element 4 (index 2) not found",
 		},
+		"UnorderedArray Overlapping Matchers Pass": {
+			body:     `["apple", "banana"]`,
+			expected: UnorderedArray(String(), "apple"),
+			wantErr:  "",
+		},
+		"UnorderedArray Reassignment Pass": {
+			body:     `[1, "a", "b"]`,
+			expected: UnorderedArray(Anything(), String(), "a"),
+			wantErr:  "",
+		},
+		"UnorderedArray Lists All Unmatched": {
+			body:     `[1, 2, 3]`,
+			expected: UnorderedArray(1, 4, 5),
+			wantErr:  "at $: expected elements 4 (index 1), 5 (index 2) not found in remaining actual elements",
+		},
+
+		// --- ExpiresAfterCreation ---
+		"ExpiresAfterCreation Pass": {
+			body:     `{"created_at": "2024-01-01T00:00:00Z", "expires_at": "2024-01-01T01:00:02Z"}`,
+			expected: ExpiresAfterCreation("created_at", "expires_at", time.Hour, 5*time.Second),
+			wantErr:  "",
+		},
+		"ExpiresAfterCreation Epoch Seconds Pass": {
+			body:     `{"token": {"iat": 1704067200, "exp": 1704070800}}`,
+			expected: ExpiresAfterCreation("token.iat", "token.exp", time.Hour, 0),
+			wantErr:  "",
+		},
+		"ExpiresAfterCreation Fail": {
+			body:     `{"session": {"created_at": "2024-01-01T00:00:00Z", "expires_at": "2024-01-02T00:00:00Z"}}`,
+			expected: Object(map[string]any{"session": ExpiresAfterCreation("created_at", "expires_at", time.Hour, time.Minute)}),
+			wantErr:  "at $.session.expires_at: expected expiry 1h0m0s after created_at (±1m0s), got 24h0m0s",
+		},
+		"ExpiresAfterCreation Missing Field": {
+			body:     `{"created_at": "2024-01-01T00:00:00Z"}`,
+			expected: ExpiresAfterCreation("created_at", "expires_at", time.Hour, 0),
+			wantErr:  "at $: missing key \"expires_at\"",
+		},
+		"ExpiresAfterCreation Invalid Time": {
+			body:     `{"created_at": true, "expires_at": "2024-01-01T00:00:00Z"}`,
+			expected: ExpiresAfterCreation("created_at", "expires_at", time.Hour, 0),
+			wantErr:  "at $.created_at: expected time string or epoch seconds, got bool",
+		},
+
+		// --- EachOneOf ---
+		"EachOneOf Pass": {
+			body:     `{"statuses": ["open", "closed", "open"]}`,
+			expected: Object(map[string]any{"statuses": EachOneOf("open", "closed")}),
+			wantErr:  "",
+		},
+		"EachOneOf Empty Pass": {
+			body:     `[]`,
+			expected: EachOneOf("open"),
+			wantErr:  "",
+		},
+		"EachOneOf Fail": {
+			body:     `{"statuses": ["open", "pending", 3, "closed", "gone"]}`,
+			expected: Object(map[string]any{"statuses": EachOneOf("open", "closed")}),
+			wantErr:  "at $.statuses: expected every element to be one of [open closed], got [1]=\"pending\", [2]=3, [4]=\"gone\"",
+		},
+
+		// --- MapOf ---
+		"MapOf Pass": {
+			body:     `{"2024-01-01": {"count": 1}, "2024-01-02": {"count": 4}}`,
+			expected: MapOf(Date(), Object(map[string]any{"count": Integer()})),
+			wantErr:  "",
+		},
+		"MapOf Empty Pass": {
+			body:     `{}`,
+			expected: MapOf(UUID(), Number()),
+			wantErr:  "",
+		},
+		"MapOf Value Fail": {
+			body:     `{"users": {"a": 1, "b": "two"}}`,
+			expected: Object(map[string]any{"users": MapOf(nil, Number())}),
+			wantErr:  "at $.users.b: expected number",
+		},
+		"MapOf Key Fail": {
+			body:     `{"2024-01-01": 1, "yesterday": 2}`,
+			expected: MapOf(Date(), Number()),
+			wantErr:  "invalid key: at $.yesterday:",
+		},
+		"MapOf Not Object": {
+			body:     `[]`,
+			expected: MapOf(nil, Anything()),
+			wantErr:  "at $: expected object, got []interface {}",
+		},
+
+		// --- KeysMatching ---
+		"KeysMatching Pass": {
+			body:     `{"feature_a": true, "feature_b": false, "version": 3}`,
+			expected: KeysMatching(`^feature_`, Bool()),
+			wantErr:  "",
+		},
+		"KeysMatching Fail": {
+			body:     `{"flags": {"feature_a": true, "feature_b": "on"}}`,
+			expected: Object(map[string]any{"flags": KeysMatching(`^feature_`, Bool())}),
+			wantErr:  "at $.flags.feature_b: expected boolean",
+		},
+		"KeysMatching With Object": {
+			body:     `{"feature_a": true, "version": 3}`,
+			expected: All(Object(map[string]any{"version": 3}), KeysMatching(`^feature_`, Bool())),
+			wantErr:  "",
+		},
+		"KeysMatching Invalid Pattern": {
+			body:     `{}`,
+			expected: KeysMatching(`(`, Bool()),
+			wantErr:  "invalid regexp pattern \"(\"",
+		},
+
+		// --- ObjectSize ---
+		"ObjectSize Pass": {
+			body:     `{"a": 1, "b": 2}`,
+			expected: ObjectSize(1, 2),
+			wantErr:  "",
+		},
+		"ObjectSize Empty Fail": {
+			body:     `{"config": {}}`,
+			expected: Object(map[string]any{"config": ObjectSize(1, 10)}),
+			wantErr:  "at $.config: expected object size between 1 and 10, got 0",
+		},
+		"ObjectSize Too Large": {
+			body:     `{"a": 1, "b": 2, "c": 3}`,
+			expected: ObjectSize(0, 2),
+			wantErr:  "at $: expected object size between 0 and 2, got 3",
+		},
+		"ObjectSize Not Object": {
+			body:     `[]`,
+			expected: ObjectSize(0, 2),
+			wantErr:  "at $: expected object, got []interface {}",
+		},
+
+		// --- Unique ---
+		"Unique Pass": {
+			body:     `[1, 2, "1", {"a": 1}, {"a": 2}]`,
+			expected: Unique(),
+			wantErr:  "",
+		},
+		"Unique Fail": {
+			body:     `[{"a": 1}, {"a": 2}, {"a": 1}]`,
+			expected: Unique(),
+			wantErr:  "at $[2]: duplicate value map[a:1], first seen at index 0",
+		},
+		"UniqueBy Pass": {
+			body:     `[{"id": 1, "name": "a"}, {"id": 2, "name": "a"}]`,
+			expected: UniqueBy("id"),
+			wantErr:  "",
+		},
+		"UniqueBy Fail": {
+			body:     `{"data": [{"id": 1}, {"id": 2}, {"id": 2}]}`,
+			expected: Object(map[string]any{"data": UniqueBy("id")}),
+			wantErr:  "at $.data[2](id=2).id: duplicate value 2, first seen at index 1",
+		},
+		"UniqueBy Missing Field": {
+			body:     `[{"id": 1}, {}]`,
+			expected: UniqueBy("id"),
+			wantErr:  "at $[1]: missing key \"id\"",
+		},
+		"Unique Not Array": {
+			body:     `{}`,
+			expected: Unique(),
+			wantErr:  "at $: expected array, got map[string]interface {}",
+		},
+
+		// --- SortedStrings ---
+		"SortedStrings Pass": {
+			body:     `["apple", "banana", "cherry"]`,
+			expected: SortedStrings(nil),
+			wantErr:  "",
+		},
+		"SortedStrings Byte Order Fail": {
+			body:     `["apple", "Banana", "cherry"]`,
+			expected: SortedStrings(ByteOrder),
+			wantErr:  "at $[1]: expected sorted strings, got \"Banana\" after \"apple\"",
+		},
+		"SortedStrings Case Insensitive Pass": {
+			body:     `["apple", "Banana", "cherry"]`,
+			expected: SortedStrings(CaseInsensitive),
+			wantErr:  "",
+		},
+		"SortedStrings Custom Collation Pass": {
+			body:     `["zeta", "beta", "alpha"]`,
+			expected: SortedStrings(func(a, b string) int { return strings.Compare(b, a) }),
+			wantErr:  "",
+		},
+		"SortedStrings Non String": {
+			body:     `["a", 1]`,
+			expected: SortedStrings(nil),
+			wantErr:  "at $[1]: expected string, got float64",
+		},
+
+		// --- Sorted ---
+		"Sorted Asc Pass": {
+			body:     `[1, 2, 2, 10]`,
+			expected: Sorted(Asc),
+			wantErr:  "",
+		},
+		"Sorted Desc Strings Pass": {
+			body:     `["c", "b", "a"]`,
+			expected: Sorted(Desc),
+			wantErr:  "",
+		},
+		"Sorted Asc Fail": {
+			body:     `[1, 3, 2]`,
+			expected: Sorted(Asc),
+			wantErr:  "at $[2]: expected ascending order, got 2 after 3",
+		},
+		"Sorted Mixed Types": {
+			body:     `[1, "a"]`,
+			expected: Sorted(Asc),
+			wantErr:  "at $[1]: cannot order a (string) after 1 (float64)",
+		},
+		"SortedBy Timestamps Across Offsets Pass": {
+			body:     `[{"created_at": "2024-01-02T01:00:00+02:00"}, {"created_at": "2024-01-01T23:30:00Z"}]`,
+			expected: SortedBy("created_at", Asc),
+			wantErr:  "",
+		},
+		"Sorted Timestamps Mixed With Strings": {
+			body:     `["2024-01-02T01:00:00+02:00", "2024-01-01T23:30:00Z", "b"]`,
+			expected: Sorted(Asc),
+			wantErr:  "at $[1]: expected ascending order, got 2024-01-01T23:30:00Z after 2024-01-02T01:00:00+02:00",
+		},
+		"SortedBy Fail": {
+			body:     `{"data": [{"id": 1, "created_at": "2024-01-01T00:00:00Z"}, {"id": 2, "created_at": "2024-01-02T00:00:00Z"}]}`,
+			expected: Object(map[string]any{"data": SortedBy("created_at", Desc)}),
+			wantErr:  "at $.data[1](id=2).created_at: expected descending order, got 2024-01-02T00:00:00Z after 2024-01-01T00:00:00Z",
+		},
+		"SortedBy Missing Field": {
+			body:     `[{"rank": 1}, {"score": 2}]`,
+			expected: SortedBy("rank", Asc),
+			wantErr:  "at $[1]: missing key \"rank\"",
+		},
+		"SortedBy Not Object": {
+			body:     `[1, 2]`,
+			expected: SortedBy("rank", Asc),
+			wantErr:  "at $[0]: expected object, got float64",
+		},
+
+		// --- ArraySuperset ---
+		"ArraySuperset Pass": {
+			body:     `["c", "a", "x", "b"]`,
+			expected: ArraySuperset("a", "b", "c"),
+			wantErr:  "",
+		},
+		"ArraySuperset With Matchers Pass": {
+			body:     `[1, "two", 3]`,
+			expected: ArraySuperset(String(), 3),
+			wantErr:  "",
+		},
+		"ArraySuperset Fail": {
+			body:     `["a", "b"]`,
+			expected: ArraySuperset("a", "c"),
+			wantErr:  "at $: expected element c (index 1) not found in actual elements",
+		},
+		"ArraySuperset Overlapping Matchers Pass": {
+			body:     `["apple", 1, "banana"]`,
+			expected: ArraySuperset(String(), "apple"),
+			wantErr:  "",
+		},
+		"ArraySuperset Duplicates Fail": {
+			body:     `["a", "b"]`,
+			expected: ArraySuperset("a", "a"),
+			wantErr:  "expected element a (index 1) not found",
+		},
+
+		// --- ArraySubset ---
+		"ArraySubset Pass": {
+			body:     `["b", "a"]`,
+			expected: ArraySubset("a", "b", "c"),
+			wantErr:  "",
+		},
+		"ArraySubset Empty Pass": {
+			body:     `[]`,
+			expected: ArraySubset("a"),
+			wantErr:  "",
+		},
+		"ArraySubset Fail": {
+			body:     `["a", "z"]`,
+			expected: ArraySubset("a", "b"),
+			wantErr:  "at $[1]: unexpected element z, not among the remaining expected elements",
+		},
+		"ArraySubset Duplicates Fail": {
+			body:     `["a", "a"]`,
+			expected: ArraySubset("a", "b"),
+			wantErr:  "at $[1]: unexpected element a",
+		},
+		"ArraySubset Overlapping Matchers Pass": {
+			body:     `["apple", "x"]`,
+			expected: ArraySubset(String(), "apple"),
+			wantErr:  "",
+		},
+
+		// --- WellFormedUnicode ---
+		"WellFormedUnicode Pass": {
+			body:     `{"name": "Zoë \ud83d\ude00"}`,
+			expected: Object(map[string]any{"name": WellFormedUnicode()}),
+			wantErr:  "",
+		},
+		"WellFormedUnicode Unpaired Surrogate": {
+			body:     `{"name": "smile \ud83d"}`,
+			expected: Object(map[string]any{"name": WellFormedUnicode()}),
+			wantErr:  "at $.name: expected well-formed unicode, got U+FFFD at byte offset 6",
+		},
+		"WellFormedUnicode Replacement Character": {
+			body:     `"caf\ufffd"`,
+			expected: WellFormedUnicode(),
+			wantErr:  "at $: expected well-formed unicode, got U+FFFD at byte offset 3",
+		},
 
 		// --- StringWithFormat ---
 		"StringWithFormat Pass": {
@@ -420,3 +1415,294 @@ func TestMatchers(t *testing.T) {
 		})
 	}
 }
+
+func TestIsFileMatch(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return filename
+	}
+
+	tests := map[string]struct {
+		filename string
+		expected interface{}
+		wantErr  string
+	}{
+		"Pass": {
+			filename: write("pass.json", `{"data": [{"id": 1}, {"id": 2}]}`),
+			expected: Object(map[string]any{"data": Array(Object(map[string]any{"id": 1}), Object(map[string]any{"id": 2}))}),
+			wantErr:  "",
+		},
+		"Mismatch": {
+			filename: write("mismatch.json", `{"data": [{"id": 1}]}`),
+			expected: Object(map[string]any{"data": Array(Object(map[string]any{"id": 2}))}),
+			wantErr:  "at $.data[0](id=1).id: expected 2 (int), got 1 (float64)",
+		},
+		"Invalid JSON": {
+			filename: write("invalid.json", `{"data": `),
+			expected: Object(map[string]any{}),
+			wantErr:  "invalid json",
+		},
+		"Trailing Data": {
+			filename: write("trailing.json", `{} {}`),
+			expected: Object(map[string]any{}),
+			wantErr:  "unexpected data after top-level value",
+		},
+		"Body Matcher In All": {
+			filename: write("bom.json", "\xef\xbb\xbf{}"),
+			expected: All(NoBOM(), Object(map[string]any{})),
+			wantErr:  "expected body without byte order mark",
+		},
+		"Missing File": {
+			filename: filepath.Join(dir, "missing.json"),
+			expected: Object(map[string]any{}),
+			wantErr:  "cannot open body file",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := isFileMatch(tt.filename, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+// closeRecorder is a body that records whether it was closed.
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestIsReaderMatch(t *testing.T) {
+	tests := map[string]struct {
+		body     io.Reader
+		expected interface{}
+		wantErr  string
+	}{
+		"Pass": {
+			body:     strings.NewReader(`{"data": [{"id": 1}, {"id": 2}]}`),
+			expected: Object(map[string]any{"data": Array(Object(map[string]any{"id": 1}), Object(map[string]any{"id": 2}))}),
+			wantErr:  "",
+		},
+		"Mismatch": {
+			body:     bytes.NewBufferString(`{"data": [{"id": 1}]}`),
+			expected: Object(map[string]any{"data": Array(Object(map[string]any{"id": 2}))}),
+			wantErr:  "at $.data[0](id=1).id: expected 2 (int), got 1 (float64)",
+		},
+		"Empty Body": {
+			body:     strings.NewReader(""),
+			expected: Object(map[string]any{}),
+			wantErr:  "expected JSON body, got empty body",
+		},
+		"Trailing Data": {
+			body:     strings.NewReader(`{} {}`),
+			expected: Object(map[string]any{}),
+			wantErr:  "unexpected data after top-level value",
+		},
+		"Body Matcher In All": {
+			body:     strings.NewReader("\xef\xbb\xbf{}"),
+			expected: All(NoBOM(), Object(map[string]any{})),
+			wantErr:  "expected body without byte order mark",
+		},
+		"Read Error": {
+			body:     io.MultiReader(strings.NewReader(`{"id": `), iotest.ErrReader(errors.New("connection reset"))),
+			expected: Object(map[string]any{}),
+			wantErr:  "invalid json: connection reset",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			body := &closeRecorder{Reader: tt.body}
+			err := isMatch(body, tt.expected)
+			if !body.closed {
+				t.Errorf("Expected the body to be closed")
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	expected := Object(map[string]any{"id": UUID(), "tags": Each(String())})
+
+	if err := Validate(expected, `{"id": "1b4e28ba-2fa1-11d2-883f-0016d3cca427", "tags": ["a"]}`); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	err := Validate(expected, strings.NewReader(`{"id": "1", "tags": [1, 2]}`), WithMaxErrors(2))
+	var mErr *MatchError
+	if !errors.As(err, &mErr) {
+		t.Fatalf("Expected a *MatchError, got %v", err)
+	}
+	if want := "at $.id: expected UUID, got \"1\"\nat $.tags[0]: expected string, got float64\n... and 1 more mismatches"; err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+
+	engine := &Engine{Unmarshal: func(data []byte, v interface{}) error {
+		return json.Unmarshal(bytes.TrimPrefix(data, []byte(")]}',")), v)
+	}}
+	if err := engine.Validate(expected, `)]}',{"id": "1b4e28ba-2fa1-11d2-883f-0016d3cca427", "tags": []}`); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestEngineUnmarshal(t *testing.T) {
+	useNumber := func(data []byte, v interface{}) error {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		return dec.Decode(v)
+	}
+
+	tests := map[string]struct {
+		engine   *Engine
+		body     string
+		expected interface{}
+		wantErr  string
+	}{
+		"Number Matchers With json.Number": {
+			engine: &Engine{Unmarshal: useNumber},
+			body:   `{"count": 3, "price": 9.99, "big": 12345678901234567890}`,
+			expected: Object(map[string]any{
+				"count": Integer(),
+				"price": NumberWithinRange(9, 10),
+				"big":   Positive(),
+			}),
+			wantErr: "",
+		},
+		"Literals With json.Number": {
+			engine:   &Engine{Unmarshal: useNumber},
+			body:     `[3, 9.99, "x"]`,
+			expected: Array(3, 9.99, "x"),
+			wantErr:  "",
+		},
+		"Raw Token Preserved": {
+			engine:   &Engine{Unmarshal: useNumber},
+			body:     `1.50`,
+			expected: json.Number("1.50"),
+			wantErr:  "",
+		},
+		"Literal Mismatch With json.Number": {
+			engine:   &Engine{Unmarshal: useNumber},
+			body:     `4`,
+			expected: 3,
+			wantErr:  "expected 3 (int), got 4 (json.Number)",
+		},
+		"Decoder Error": {
+			engine: &Engine{Unmarshal: func(data []byte, v interface{}) error {
+				return fmt.Errorf("decoder failed")
+			}},
+			body:     `{}`,
+			expected: Object(map[string]any{}),
+			wantErr:  "invalid json: decoder failed",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.engine.isMatch(tt.body, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestEngineHooks(t *testing.T) {
+	var before []string
+	failed := map[string]bool{}
+	engine := &Engine{
+		BeforeMatch: func(path string, value interface{}) {
+			before = append(before, path)
+		},
+		AfterMatch: func(path string, value interface{}, err error) {
+			failed[path] = err != nil
+		},
+	}
+
+	err := engine.isMatch(`{"a": [1, 2]}`, Object(map[string]any{"a": Array(1, 3)}))
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if want := []string{"$", "$.a", "$.a[0]", "$.a[1]"}; strings.Join(before, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected before hook for %v, got %v", want, before)
+	}
+	if failed["$.a[0]"] || !failed["$.a[1]"] || !failed["$.a"] || !failed["$"] {
+		t.Errorf("Expected after hook to report failures up from $.a[1], got %v", failed)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	var calls []string
+	m := Wrap(String(),
+		func(path string, value interface{}) {
+			calls = append(calls, fmt.Sprintf("before %s %v", path, value))
+		},
+		func(path string, value interface{}, err error) {
+			calls = append(calls, fmt.Sprintf("after %s %v", path, err != nil))
+		},
+	)
+
+	if err := isMatch(`{"a": "x", "b": 1}`, Object(map[string]any{"a": m})); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := isMatch(`{"b": 1}`, Object(map[string]any{"b": m})); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	want := []string{"before $.a x", "after $.a false", "before $.b 1", "after $.b true"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected calls %v, got %v", want, calls)
+	}
+
+	if err := isMatch(`1`, Wrap(1, nil, nil)); err != nil {
+		t.Errorf("Expected nil hooks to be allowed, got %v", err)
+	}
+}
+
+// gzipBase64 compresses s with gzip and encodes the result as standard base64.
+func gzipBase64(s string) string {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(s))
+	zw.Close()
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
@@ -0,0 +1,113 @@
+package bodyguard
+
+import (
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// CacheStats reports how often compiled expectations were reused.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// HitRate returns the fraction of lookups served from the cache, or 0 if there were none.
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// maxCachedPatterns bounds the number of patterns kept by the regexp cache. Patterns seen once the
+// cache is full are compiled on every use.
+const maxCachedPatterns = 4096
+
+type compiledRegexp struct {
+	re  *regexp.Regexp
+	err error
+}
+
+// regexpCache holds compiled patterns, so matcher trees rebuilt for every case of a
+// table-driven test compile each pattern only once.
+var regexpCache struct {
+	patterns sync.Map // pattern -> compiledRegexp
+	size     atomic.Int64
+}
+
+// cacheStats counts the lookups of every cache of compiled expectations.
+var cacheStats struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// compileRegexp compiles pattern, reusing a previous compilation when possible.
+// Compilation errors are cached too.
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := regexpCache.patterns.Load(pattern); ok {
+		cacheStats.hits.Add(1)
+		c := cached.(compiledRegexp)
+		return c.re, c.err
+	}
+
+	cacheStats.misses.Add(1)
+	re, err := regexp.Compile(pattern)
+	if regexpCache.size.Add(1) <= maxCachedPatterns {
+		regexpCache.patterns.Store(pattern, compiledRegexp{re: re, err: err})
+	} else {
+		regexpCache.size.Add(-1)
+	}
+	return re, err
+}
+
+// expectationKey identifies an expectation tree built by CachedExpectation.
+type expectationKey struct {
+	test string
+	key  string
+}
+
+type cachedExpectation struct {
+	once     sync.Once
+	expected interface{}
+}
+
+// expectationCache holds the trees built by CachedExpectation. It keeps one entry per test and key
+// for the lifetime of the test binary.
+var expectationCache sync.Map // expectationKey -> *cachedExpectation
+
+// CachedExpectation returns the expectation built by build, calling build only the first time the
+// test asks for key. Entries are keyed by the full name of the test, subtests included, so every run
+// of -count and every iteration of a benchmark share one tree, while each case of a table-driven
+// test gets its own:
+//
+//	expected := bodyguard.CachedExpectation(t, "user", func() interface{} {
+//		return bodyguard.Object(map[string]any{"id": bodyguard.UUID(), "email": bodyguard.Regexp(emailPattern)})
+//	})
+//
+// Use a different key for every tree a test builds. Trees holding CaptureAs store into the
+// destination given when they were built, so they should not be cached.
+func CachedExpectation(t testing.TB, key string, build func() interface{}) interface{} {
+	t.Helper()
+	entry, loaded := expectationCache.LoadOrStore(expectationKey{test: t.Name(), key: key}, &cachedExpectation{})
+	if loaded {
+		cacheStats.hits.Add(1)
+	} else {
+		cacheStats.misses.Add(1)
+	}
+
+	c := entry.(*cachedExpectation)
+	c.once.Do(func() { c.expected = build() })
+	return c.expected
+}
+
+// CompileCacheStats returns the hit and miss counts of the caches used for compiled expectations,
+// the Regexp patterns and the trees built by CachedExpectation.
+func CompileCacheStats() CacheStats {
+	return CacheStats{
+		Hits:   cacheStats.hits.Load(),
+		Misses: cacheStats.misses.Load(),
+	}
+}
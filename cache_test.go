@@ -0,0 +1,82 @@
+package bodyguard
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+// cacheTestRuns makes the patterns and keys of every run unique, so the process-wide caches start
+// empty for them under -count.
+var cacheTestRuns atomic.Int64
+
+func TestCompileRegexpCache(t *testing.T) {
+	run := cacheTestRuns.Add(1)
+	pattern := fmt.Sprintf(`^cache-test-%d$`, run)
+	body := fmt.Sprintf(`"cache-test-%d"`, run)
+	before := CompileCacheStats()
+
+	for i := 0; i < 3; i++ {
+		if err := isMatch(body, Regexp(pattern)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	after := CompileCacheStats()
+	if misses := after.Misses - before.Misses; misses != 1 {
+		t.Errorf("Expected 1 miss, got %d", misses)
+	}
+	if hits := after.Hits - before.Hits; hits != 2 {
+		t.Errorf("Expected 2 hits, got %d", hits)
+	}
+
+	if _, err := compileRegexp(`(`); err == nil {
+		t.Errorf("Expected compilation error, got nil")
+	}
+	if _, err := compileRegexp(`(`); err == nil {
+		t.Errorf("Expected cached compilation error, got nil")
+	}
+}
+
+func TestCacheStatsHitRate(t *testing.T) {
+	if rate := (CacheStats{}).HitRate(); rate != 0 {
+		t.Errorf("Expected 0 hit rate, got %v", rate)
+	}
+	if rate := (CacheStats{Hits: 3, Misses: 1}).HitRate(); rate != 0.75 {
+		t.Errorf("Expected 0.75 hit rate, got %v", rate)
+	}
+}
+
+func TestCachedExpectation(t *testing.T) {
+	key := fmt.Sprintf("id-%d", cacheTestRuns.Add(1))
+	builds := 0
+	build := func() interface{} {
+		builds++
+		return Object(map[string]any{"id": Regexp(`^[0-9]+$`)})
+	}
+	before := CompileCacheStats()
+
+	for _, body := range []string{`{"id": "1"}`, `{"id": "2"}`} {
+		t.Run(body, func(t *testing.T) {
+			for i := 0; i < 2; i++ {
+				if err := isMatch(body, CachedExpectation(t, key, build)); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			}
+		})
+	}
+	if builds != 2 {
+		t.Errorf("Expected 1 build per subtest, got %d", builds)
+	}
+
+	CachedExpectation(t, key, build)
+	CachedExpectation(t, key+"-other", build)
+	if builds != 4 {
+		t.Errorf("Expected 4 builds, got %d", builds)
+	}
+
+	after := CompileCacheStats()
+	if hits := after.Hits - before.Hits; hits < 2 {
+		t.Errorf("Expected at least 2 hits, got %d", hits)
+	}
+}
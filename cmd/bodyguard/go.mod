@@ -0,0 +1,15 @@
+module github.com/dedalusj/bodyguard/cmd/bodyguard
+
+go 1.25.5
+
+require (
+	github.com/dedalusj/bodyguard v0.0.0
+	github.com/dedalusj/bodyguard/spec v0.0.0
+)
+
+require gopkg.in/yaml.v3 v3.0.1 // indirect
+
+replace (
+	github.com/dedalusj/bodyguard => ../../
+	github.com/dedalusj/bodyguard/spec => ../../spec
+)
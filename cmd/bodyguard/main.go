@@ -0,0 +1,100 @@
+// Command bodyguard checks JSON payloads against spec files outside go test, so the assertions of a
+// test suite can also run in smoke tests and CI pipelines:
+//
+//	bodyguard check testdata/user.yaml response.json
+//
+// Spec files are written in the YAML or JSON format of the spec package. The payload is read from the
+// named file, or from standard input when it is "-". Every mismatch is reported with its path, one per
+// line, on standard error.
+//
+// Usage:
+//
+//	bodyguard check [flags] spec payload
+//
+// The flags are:
+//
+//	-diff
+//		also print the payload annotated with the mismatches
+//	-pointer
+//		report paths as JSON Pointers, e.g. /data/0/id, rather than JSONPath expressions
+//
+// The exit status is 0 when the payload matches, 1 when it does not and 2 when the spec or payload
+// cannot be read.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dedalusj/bodyguard"
+	"github.com/dedalusj/bodyguard/spec"
+)
+
+const usage = "usage: bodyguard check [-diff] [-pointer] spec payload"
+
+// errMismatch is returned when the payload does not match the spec, after the mismatches are reported.
+var errMismatch = errors.New("payload does not match spec")
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	switch {
+	case errors.Is(err, errMismatch):
+		os.Exit(1)
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "bodyguard: %v\n", err)
+		os.Exit(2)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) == 0 || args[0] != "check" {
+		return errors.New(usage)
+	}
+
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	diff := flags.Bool("diff", false, "also print the payload annotated with the mismatches")
+	pointer := flags.Bool("pointer", false, "report paths as JSON Pointers")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return errors.New(usage)
+	}
+	specFile, payloadFile := flags.Arg(0), flags.Arg(1)
+
+	expected, err := spec.Load(specFile)
+	if err != nil {
+		return err
+	}
+	if err := bodyguard.ValidateExpected(expected); err != nil {
+		return fmt.Errorf("%s: %w", specFile, err)
+	}
+
+	payload := stdin
+	if payloadFile != "-" {
+		f, err := os.Open(payloadFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		payload = f
+	}
+
+	var opts []bodyguard.Option
+	if *diff {
+		opts = append(opts, bodyguard.WithDiff())
+	}
+	if *pointer {
+		opts = append(opts, bodyguard.WithPathFormat(bodyguard.JSONPointer))
+	}
+	if err := bodyguard.Evaluate(expected, payload, opts...).Err; err != nil {
+		fmt.Fprintf(stderr, "%s does not match %s:\n%v\n", payloadFile, specFile, err)
+		return errMismatch
+	}
+	fmt.Fprintf(stdout, "%s matches %s\n", payloadFile, specFile)
+	return nil
+}
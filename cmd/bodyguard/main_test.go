@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return filename
+	}
+	userSpec := write("user.yaml", "type: object\nproperties:\n  id: {matcher: uuid}\n  tags: {items: {type: string}}\n")
+	invalidSpec := write("invalid.yaml", "properties:\n  name: {pattern: \"[a-z\"}\n")
+	valid := write("valid.json", `{"id": "1b4e28ba-2fa1-11d2-883f-0016d3cca427", "tags": ["a"]}`)
+	mismatched := write("mismatched.json", `{"id": "1", "tags": ["a", 2]}`)
+
+	tests := map[string]struct {
+		args       []string
+		stdin      string
+		wantErr    error
+		wantStdout string
+		wantStderr string
+	}{
+		"Match": {
+			args:       []string{"check", userSpec, valid},
+			wantStdout: valid + " matches " + userSpec,
+		},
+		"Standard Input": {
+			args:       []string{"check", userSpec, "-"},
+			stdin:      `{"id": "1b4e28ba-2fa1-11d2-883f-0016d3cca427", "tags": []}`,
+			wantStdout: "- matches " + userSpec,
+		},
+		"Mismatch": {
+			args:       []string{"check", userSpec, mismatched},
+			wantErr:    errMismatch,
+			wantStderr: "at $.id: expected UUID, got \"1\"\nat $.tags[1]: expected string, got float64",
+		},
+		"JSON Pointer Paths": {
+			args:       []string{"check", "-pointer", userSpec, mismatched},
+			wantErr:    errMismatch,
+			wantStderr: "at /tags/1: expected string, got float64",
+		},
+		"Diff": {
+			args:       []string{"check", "-diff", userSpec, mismatched},
+			wantErr:    errMismatch,
+			wantStderr: "actual document:",
+		},
+		"Invalid Spec": {
+			args:    []string{"check", invalidSpec, valid},
+			wantErr: errors.New(invalidSpec + ": at $.name: invalid pattern \"[a-z\""),
+		},
+		"Missing Payload": {
+			args:    []string{"check", userSpec, filepath.Join(dir, "missing.json")},
+			wantErr: errors.New("no such file or directory"),
+		},
+		"Unknown Command": {
+			args:    []string{"lint", userSpec, valid},
+			wantErr: errors.New(usage),
+		},
+		"Missing Argument": {
+			args:    []string{"check", userSpec},
+			wantErr: errors.New(usage),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Errorf("Expected no error, got %v", err)
+			case tt.wantErr == errMismatch && !errors.Is(err, errMismatch):
+				t.Errorf("Expected mismatch, got %v", err)
+			case tt.wantErr != nil && tt.wantErr != errMismatch && (err == nil || !strings.Contains(err.Error(), tt.wantErr.Error())):
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Errorf("Expected output containing %q, got %q", tt.wantStdout, stdout.String())
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("Expected errors containing %q, got %q", tt.wantStderr, stderr.String())
+			}
+		})
+	}
+}
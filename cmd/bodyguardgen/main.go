@@ -0,0 +1,188 @@
+// Command bodyguardgen generates the Go source of a bodyguard expectation from a sample JSON payload,
+// so large responses need not be transcribed by hand:
+//
+//	bodyguardgen -package users -name userContract testdata/user.json > user_contract_test.go
+//
+// Objects become Object trees with their keys in order, arrays whose elements are alike become Each,
+// and values become matchers of their type. Strings are checked for the formats bodyguard knows,
+// UUID, Timestamp, Date, Email and URL, and numbers are told apart as Integer or Number. The
+// generated expectation accepts the sample; review it and tighten the fields that matter.
+//
+// The payload is read from the named file, or from standard input when none is given.
+//
+// Usage:
+//
+//	bodyguardgen [flags] [file]
+//
+// The flags are:
+//
+//	-package name
+//		the package of the generated file (default "main")
+//	-name name
+//		the name of the generated variable (default "expected")
+//	-strict
+//		generate StrictObject rather than Object, so extra keys are mismatches
+//	-o file
+//		write the source to file rather than standard output
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dedalusj/bodyguard"
+)
+
+// options configures the generated source.
+type options struct {
+	pkg    string
+	name   string
+	strict bool
+	source string // the sample the expectation is generated from, for the comment of the variable
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "bodyguardgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("bodyguardgen", flag.ContinueOnError)
+	opts := options{source: "standard input"}
+	flags.StringVar(&opts.pkg, "package", "main", "the package of the generated file")
+	flags.StringVar(&opts.name, "name", "expected", "the name of the generated variable")
+	flags.BoolVar(&opts.strict, "strict", false, "generate StrictObject rather than Object")
+	output := flags.String("o", "", "write the source to `file` rather than standard output")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return errors.New("expected at most one payload file")
+	}
+
+	input := stdin
+	if flags.NArg() == 1 {
+		f, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		input = f
+		opts.source = flags.Arg(0)
+	}
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return fmt.Errorf("cannot read payload: %w", err)
+	}
+
+	src, err := generate(data, opts)
+	if err != nil {
+		return err
+	}
+	if *output != "" {
+		return os.WriteFile(*output, src, 0o644)
+	}
+	_, err = stdout.Write(src)
+	return err
+}
+
+// generate returns the formatted Go source declaring an expectation matching the JSON payload.
+func generate(data []byte, opts options) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var payload interface{}
+	if err := dec.Decode(&payload); err != nil {
+		return nil, fmt.Errorf("invalid json: %w", err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("invalid json: unexpected data after top-level value")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "package %s\n\n", opts.pkg)
+	b.WriteString("import \"github.com/dedalusj/bodyguard\"\n\n")
+	fmt.Fprintf(&b, "// %s was generated by bodyguardgen from %s.\n", opts.name, opts.source)
+	fmt.Fprintf(&b, "var %s = %s\n", opts.name, expression(payload, opts))
+	return format.Source([]byte(b.String()))
+}
+
+// expression returns the Go expression of the matcher guessed for a decoded value.
+func expression(v interface{}, opts options) string {
+	switch t := v.(type) {
+	case nil:
+		return "bodyguard.Null()"
+	case bool:
+		return "bodyguard.Bool()"
+	case json.Number:
+		if _, err := t.Int64(); err == nil {
+			return "bodyguard.Integer()"
+		}
+		return "bodyguard.Number()"
+	case string:
+		return stringExpression(t)
+	case []interface{}:
+		return arrayExpression(t, opts)
+	case map[string]interface{}:
+		constructor := "Object"
+		if opts.strict {
+			constructor = "StrictObject"
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "bodyguard.%s(map[string]any{\n", constructor)
+		for _, key := range slices.Sorted(maps.Keys(t)) {
+			fmt.Fprintf(&b, "%s: %s,\n", strconv.Quote(key), expression(t[key], opts))
+		}
+		b.WriteString("})")
+		return b.String()
+	}
+	return "bodyguard.Anything()"
+}
+
+// stringFormats lists the string matchers tried in order; the first one accepting a string is used.
+var stringFormats = []struct {
+	expression string
+	matcher    bodyguard.Matcher
+}{
+	{"bodyguard.UUID()", bodyguard.UUID()},
+	{"bodyguard.Timestamp()", bodyguard.Timestamp()},
+	{"bodyguard.Date()", bodyguard.Date()},
+	{"bodyguard.Email()", bodyguard.Email()},
+	{"bodyguard.URL()", bodyguard.URL()},
+}
+
+func stringExpression(s string) string {
+	for _, f := range stringFormats {
+		if f.matcher.Match("$", s) == nil {
+			return f.expression
+		}
+	}
+	return "bodyguard.String()"
+}
+
+// arrayExpression uses Each when every element has the same expression, and Array otherwise.
+func arrayExpression(elements []interface{}, opts options) string {
+	if len(elements) == 0 {
+		return "bodyguard.Each(bodyguard.Anything())"
+	}
+
+	expressions := make([]string, len(elements))
+	for i, element := range elements {
+		expressions[i] = expression(element, opts)
+	}
+	if len(slices.Compact(slices.Clone(expressions))) == 1 {
+		return "bodyguard.Each(" + expressions[0] + ")"
+	}
+	return "bodyguard.Array(\n" + strings.Join(expressions, ",\n") + ",\n)"
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	payload := `{
+		"id": "1b4e28ba-2fa1-11d2-883f-0016d3cca427",
+		"email": "jdoe@example.com",
+		"homepage": "https://example.com/jdoe",
+		"created_at": "2024-01-02T15:04:05Z",
+		"birthday": "1990-05-17",
+		"name": "jdoe",
+		"age": 42,
+		"score": 0.5,
+		"active": true,
+		"manager": null,
+		"tags": ["a", "b"],
+		"pair": [1, "x"],
+		"notes": [],
+		"orders": [{"id": 1, "total": 9.5}, {"id": 2, "total": 20.25}]
+	}`
+
+	want := `package users
+
+import "github.com/dedalusj/bodyguard"
+
+// userContract was generated by bodyguardgen from testdata/user.json.
+var userContract = bodyguard.Object(map[string]any{
+	"active":     bodyguard.Bool(),
+	"age":        bodyguard.Integer(),
+	"birthday":   bodyguard.Date(),
+	"created_at": bodyguard.Timestamp(),
+	"email":      bodyguard.Email(),
+	"homepage":   bodyguard.URL(),
+	"id":         bodyguard.UUID(),
+	"manager":    bodyguard.Null(),
+	"name":       bodyguard.String(),
+	"notes":      bodyguard.Each(bodyguard.Anything()),
+	"orders": bodyguard.Each(bodyguard.Object(map[string]any{
+		"id":    bodyguard.Integer(),
+		"total": bodyguard.Number(),
+	})),
+	"pair": bodyguard.Array(
+		bodyguard.Integer(),
+		bodyguard.String(),
+	),
+	"score": bodyguard.Number(),
+	"tags":  bodyguard.Each(bodyguard.String()),
+})
+`
+
+	got, err := generate([]byte(payload), options{pkg: "users", name: "userContract", source: "testdata/user.json"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "user.json")
+	if err := os.WriteFile(input, []byte(`{"id": 1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args    []string
+		stdin   string
+		want    string
+		wantErr string
+	}{
+		"File": {
+			args: []string{"-strict", input},
+			want: "var expected = bodyguard.StrictObject(map[string]any{\n\t\"id\": bodyguard.Integer(),\n})",
+		},
+		"Standard Input": {
+			args:  []string{"-package", "users"},
+			stdin: `[true]`,
+			want:  "package users",
+		},
+		"Invalid JSON": {
+			args:    nil,
+			stdin:   `{"id": `,
+			wantErr: "invalid json: unexpected EOF",
+		},
+		"Trailing Data": {
+			args:    nil,
+			stdin:   `{} {}`,
+			wantErr: "invalid json: unexpected data after top-level value",
+		},
+		"Too Many Files": {
+			args:    []string{input, input},
+			wantErr: "expected at most one payload file",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			err := run(tt.args, strings.NewReader(tt.stdin), &stdout)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !strings.Contains(stdout.String(), tt.want) {
+				t.Errorf("Expected output containing %q, got\n%s", tt.want, stdout.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package bodyguard
+
+import (
+	"reflect"
+	"sync"
+)
+
+// comparators maps the reflect.Type of a literal to a func(interface{}) Matcher.
+var comparators sync.Map
+
+func init() {
+	RegisterComparator(TimeLiteral)
+}
+
+// RegisterComparator registers how literals of type T placed in the expected tree are compared:
+// compare returns the matcher a literal is replaced with, typically checking the JSON string form of
+// the value. It lets domain types be used as literals without pre-formatting them, e.g.
+//
+//	bodyguard.RegisterComparator(func(id uuid.UUID) bodyguard.Matcher { return bodyguard.UUIDLiteral(id) })
+//
+// time.Time is registered by default and compared as with TimeLiteral.
+// Registering a type again replaces its comparator. It is safe for concurrent use.
+func RegisterComparator[T any](compare func(expected T) Matcher) {
+	comparators.Store(reflect.TypeFor[T](), func(expected interface{}) Matcher {
+		return compare(expected.(T))
+	})
+}
+
+// comparatorFor returns the matcher registered for the type of a literal, if any.
+func comparatorFor(expected interface{}) (Matcher, bool) {
+	if expected == nil {
+		return nil, false
+	}
+	compare, ok := comparators.Load(reflect.TypeOf(expected))
+	if !ok {
+		return nil, false
+	}
+	return compare.(func(interface{}) Matcher)(expected), true
+}
@@ -0,0 +1,63 @@
+package bodyguard
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// money is a domain type serialized as a decimal string such as "12.50".
+type money struct {
+	cents int64
+}
+
+func TestRegisterComparator(t *testing.T) {
+	RegisterComparator(func(expected money) Matcher {
+		return OneOf(fmt.Sprintf("%d.%02d", expected.cents/100, expected.cents%100))
+	})
+
+	tests := map[string]struct {
+		body     string
+		expected interface{}
+		wantErr  string
+	}{
+		"Custom Type Pass": {
+			body:     `{"total": "12.50"}`,
+			expected: Object(map[string]any{"total": money{cents: 1250}}),
+			wantErr:  "",
+		},
+		"Custom Type Fail": {
+			body:     `{"total": "12.5"}`,
+			expected: Object(map[string]any{"total": money{cents: 1250}}),
+			wantErr:  "at $.total: expected one of [12.50], got \"12.5\"",
+		},
+		"Time Pass": {
+			body:     `{"at": "2024-01-01T02:00:00+02:00"}`,
+			expected: Object(map[string]any{"at": time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}),
+			wantErr:  "",
+		},
+		"Time Fail": {
+			body:     `["2024-01-01T00:00:01Z"]`,
+			expected: Array(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+			wantErr:  "at $[0]: expected time 2024-01-01T00:00:00Z, got 2024-01-01T00:00:01Z",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := isMatch(tt.body, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
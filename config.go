@@ -0,0 +1,35 @@
+package bodyguard
+
+// Config holds the defaults of an asserter, so a test suite can configure them once and share the
+// asserter returned by New, e.g.
+//
+//	var asserter = bodyguard.New(bodyguard.Config{StrictObjects: true, Epsilon: 1e-9})
+//
+// Options passed to an assertion are applied on top of these defaults.
+type Config struct {
+	// StrictObjects reports extra keys in every object, as with WithStrictObjects.
+	StrictObjects bool
+
+	// Epsilon is the tolerance number literals are compared within, as with WithEpsilon.
+	Epsilon float64
+
+	// TimeLayouts lists the layouts accepted for timestamps in addition to RFC3339, as with WithTimeLayouts.
+	TimeLayouts []string
+
+	// Options sets any other default, e.g. UnorderedArrays() or WithPathFormat(JSONPointer).
+	Options []Option
+}
+
+// New returns an Engine asserting with the defaults of config. Its methods mirror the package-level
+// functions, e.g. asserter.Assert(t, expected, body).
+func New(config Config) *Engine {
+	e := &Engine{
+		StrictObjects: config.StrictObjects,
+		Epsilon:       config.Epsilon,
+		TimeLayouts:   config.TimeLayouts,
+	}
+	for _, opt := range config.Options {
+		opt(e)
+	}
+	return e
+}
@@ -0,0 +1,76 @@
+package bodyguard
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	asserter := New(Config{
+		StrictObjects: true,
+		Epsilon:       1e-9,
+		TimeLayouts:   []string{"2006-01-02 15:04:05"},
+		Options:       []Option{WithPathFormat(JSONPointer)},
+	})
+
+	tests := map[string]struct {
+		body     string
+		expected interface{}
+		opts     []Option
+		wantErr  string
+	}{
+		"Defaults Pass": {
+			body:     `{"total": 0.30000000000000004, "created_at": "2024-05-01 10:00:00"}`,
+			expected: Object(map[string]any{"total": 0.3, "created_at": Timestamp()}),
+			wantErr:  "",
+		},
+		"RFC3339 Still Accepted": {
+			body:     `{"created_at": "2024-05-01T10:00:00Z"}`,
+			expected: Object(map[string]any{"created_at": TimeBefore(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))}),
+			wantErr:  "",
+		},
+		"Custom Layout Checked": {
+			body:     `{"created_at": "2026-05-01 10:00:00"}`,
+			expected: Object(map[string]any{"created_at": TimeBefore(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))}),
+			wantErr:  "at /created_at: expected time before",
+		},
+		"Unknown Layout Mismatch": {
+			body:     `{"created_at": "01/05/2024"}`,
+			expected: Object(map[string]any{"created_at": Timestamp()}),
+			wantErr:  "at /created_at: parsing time \"01/05/2024\"",
+		},
+		"Strict Objects Default": {
+			body:     `{"id": 1, "role": "admin"}`,
+			expected: Object(map[string]any{"id": 1}),
+			wantErr:  "unexpected key \"role\"",
+		},
+		"Options Applied On Top": {
+			body:     `{"id": 1, "role": "admin"}`,
+			expected: Object(map[string]any{"id": 1}),
+			opts:     []Option{Ignoring("role")},
+			wantErr:  "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := asserter.Validate(tt.expected, tt.body, tt.opts...)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+
+	if err := Validate(Object(map[string]any{"created_at": Timestamp()}), `{"created_at": "2024-05-01 10:00:00"}`); err == nil {
+		t.Errorf("Expected the default engine to only accept RFC3339")
+	}
+}
@@ -0,0 +1,204 @@
+package bodyguard
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// contentTypeMatcher is returned by ByContentType.
+type contentTypeMatcher map[string]interface{}
+
+// ByContentType returns an expectation for DoAndAssert and StubRoute.ExpectBody that picks the
+// expectation for the Content-Type of the body, keyed by media type (e.g. "application/xml"),
+// and decodes the body accordingly, so one call can cover every format served by an endpoint.
+//
+// JSON and "+json" types use the engine's Unmarshal function. Other supported types are decoded into
+// the same generic values as JSON:
+//   - XML ("application/xml", "text/xml" and "+xml" types) becomes an object holding the root element.
+//     Elements with only text become strings, other elements objects whose attributes are keyed "@name"
+//     and whose text is keyed "#text". Repeated elements become arrays. All values are strings.
+//   - Forms ("application/x-www-form-urlencoded") become an object of strings, or arrays of strings
+//     for repeated fields.
+//   - NDJSON ("application/x-ndjson", "application/jsonl" and "application/json-seq") becomes an array
+//     of the documents.
+//   - MessagePack ("application/msgpack", "application/x-msgpack" and "application/vnd.msgpack").
+//
+// A body whose Content-Type has no expectation fails.
+func ByContentType(expectations map[string]interface{}) Matcher {
+	m := make(contentTypeMatcher, len(expectations))
+	for mediaType, expected := range expectations {
+		m[strings.ToLower(strings.TrimSpace(mediaType))] = expected
+	}
+	return m
+}
+
+func (m contentTypeMatcher) Match(path string, value interface{}) error {
+	return fmt.Errorf("at %s: ByContentType can only be used as the expectation of a response or request body", path)
+}
+
+// contentDecoders decode the media types supported by ByContentType, other than JSON and NDJSON.
+var contentDecoders = map[string]func(data []byte) (interface{}, error){
+	"application/xml":                   decodeXML,
+	"text/xml":                          decodeXML,
+	"application/x-www-form-urlencoded": decodeForm,
+	"application/msgpack":               decodeMsgpack,
+	"application/x-msgpack":             decodeMsgpack,
+	"application/vnd.msgpack":           decodeMsgpack,
+}
+
+// ndjsonTypes are the media types of sequences of JSON documents, which are decoded with the engine's
+// Unmarshal function and options, as JSON bodies are.
+var ndjsonTypes = map[string]bool{
+	"application/x-ndjson": true,
+	"application/ndjson":   true,
+	"application/jsonl":    true,
+	"application/json-seq": true,
+}
+
+// matchContent matches data against the expectation for its content type, returning the decoded body.
+func (e *Engine) matchContent(contentType string, data []byte, expectations contentTypeMatcher) (interface{}, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	expected, ok := expectations[mediaType]
+	if err != nil || !ok {
+		return nil, fmt.Errorf("expected Content-Type %s, got %q", strings.Join(slices.Sorted(maps.Keys(expectations)), " or "), contentType)
+	}
+
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		return e.matchBodyValue(data, expected)
+	}
+
+	decode := contentDecoders[mediaType]
+	switch {
+	case ndjsonTypes[mediaType]:
+		decode = e.decodeNDJSON
+	case decode == nil && strings.HasSuffix(mediaType, "+xml"):
+		decode = decodeXML
+	}
+	if decode == nil {
+		return nil, fmt.Errorf("cannot decode content type %q", mediaType)
+	}
+
+	if m, ok := expected.(BodyMatcher); ok {
+		return nil, e.matchBodyMatcher(m, data)
+	}
+	decoded, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s body: %w", mediaType, err)
+	}
+	return decoded, e.matchValue(expected, decoded)
+}
+
+// matchMessage matches the body of an HTTP message with the given Content-Type against expected,
+// decoding it according to its Content-Type when expected was built by ByContentType.
+func (e *Engine) matchMessage(contentType string, data []byte, expected interface{}) error {
+	if byType, ok := expected.(contentTypeMatcher); ok {
+		_, err := e.matchContent(contentType, data, byType)
+		return err
+	}
+	return e.matchBody(data, expected)
+}
+
+// decodeXML decodes an XML document into an object holding its root element.
+func decodeXML(data []byte) (interface{}, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("no root element")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			root, err := decodeXMLElement(dec, start)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{start.Name.Local: root}, nil
+		}
+	}
+}
+
+func decodeXMLElement(dec *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	fields := map[string]interface{}{}
+	for _, attr := range start.Attr {
+		fields["@"+attr.Name.Local] = attr.Value
+	}
+
+	var text strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			name := t.Name.Local
+			existing, seen := fields[name]
+			if !seen {
+				fields[name] = child
+			} else if list, ok := existing.([]interface{}); ok {
+				fields[name] = append(list, child)
+			} else {
+				fields[name] = []interface{}{existing, child}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			s := strings.TrimSpace(text.String())
+			if len(fields) == 0 {
+				return s, nil
+			}
+			if s != "" {
+				fields["#text"] = s
+			}
+			return fields, nil
+		}
+	}
+}
+
+// decodeForm decodes a URL-encoded form into an object of strings, using arrays for repeated fields.
+func decodeForm(data []byte) (interface{}, error) {
+	values, err := url.ParseQuery(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	form := make(map[string]interface{}, len(values))
+	for key, vs := range values {
+		if len(vs) == 1 {
+			form[key] = vs[0]
+			continue
+		}
+		list := make([]interface{}, len(vs))
+		for i, v := range vs {
+			list[i] = v
+		}
+		form[key] = list
+	}
+	return form, nil
+}
+
+// decodeNDJSON decodes newline-delimited JSON documents into an array.
+func (e *Engine) decodeNDJSON(data []byte) (interface{}, error) {
+	docs, err := e.decodeSequence(data)
+	if err != nil {
+		return nil, err
+	}
+	if docs == nil {
+		docs = []interface{}{}
+	}
+	return docs, nil
+}
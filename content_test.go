@@ -0,0 +1,148 @@
+package bodyguard
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestByContentType(t *testing.T) {
+	bodies := map[string]string{
+		"application/json; charset=utf-8":   `{"id": 1, "name": "jdoe"}`,
+		"application/problem+json":          `{"title": "not found"}`,
+		"application/xml":                   `<user id="1"><name>jdoe</name><role>admin</role><role>dev</role></user>`,
+		"application/x-www-form-urlencoded": `id=1&name=jdoe&role=admin&role=dev`,
+		"application/x-ndjson":              "{\"id\": 1}\n{\"id\": 2}\n",
+		"application/msgpack":               "\x82\xa2id\x01\xa4name\xa4jdoe",
+		"text/plain":                        `jdoe`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType := r.URL.Query().Get("type")
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write([]byte(bodies[contentType]))
+	}))
+	defer server.Close()
+
+	expected := ByContentType(map[string]any{
+		"application/json":                  Object(map[string]any{"id": 1}),
+		"application/problem+json":          Object(map[string]any{"title": String()}),
+		"application/xml":                   Object(map[string]any{"user": StrictObject(map[string]any{"@id": "1", "name": "jdoe", "role": []any{"admin", "dev"}})}),
+		"application/x-www-form-urlencoded": StrictObject(map[string]any{"id": "1", "name": "jdoe", "role": []any{"admin", "dev"}}),
+		"application/x-ndjson":              Each(Object(map[string]any{"id": Integer()})),
+		"application/msgpack":               StrictObject(map[string]any{"id": 1, "name": "jdoe"}),
+		"text/plain":                        Anything(),
+	})
+
+	tests := map[string]struct {
+		contentType string
+		expected    interface{}
+		wantBody    interface{}
+		wantErr     string
+	}{
+		"JSON Pass": {
+			contentType: "application/json; charset=utf-8",
+			expected:    expected,
+			wantBody:    map[string]any{"id": 1.0, "name": "jdoe"},
+			wantErr:     "",
+		},
+		"JSON Suffix Pass": {
+			contentType: "application/problem+json",
+			expected:    expected,
+			wantBody:    map[string]any{"title": "not found"},
+			wantErr:     "",
+		},
+		"XML Pass": {
+			contentType: "application/xml",
+			expected:    expected,
+			wantBody:    map[string]any{"user": map[string]any{"@id": "1", "name": "jdoe", "role": []any{"admin", "dev"}}},
+			wantErr:     "",
+		},
+		"Form Pass": {
+			contentType: "application/x-www-form-urlencoded",
+			expected:    expected,
+			wantBody:    map[string]any{"id": "1", "name": "jdoe", "role": []any{"admin", "dev"}},
+			wantErr:     "",
+		},
+		"NDJSON Pass": {
+			contentType: "application/x-ndjson",
+			expected:    expected,
+			wantBody:    []any{map[string]any{"id": 1.0}, map[string]any{"id": 2.0}},
+			wantErr:     "",
+		},
+		"MessagePack Pass": {
+			contentType: "application/msgpack",
+			expected:    expected,
+			wantBody:    map[string]any{"id": 1.0, "name": "jdoe"},
+			wantErr:     "",
+		},
+		"Mismatch": {
+			contentType: "application/xml",
+			expected:    ByContentType(map[string]any{"application/xml": Object(map[string]any{"user": Object(map[string]any{"name": "other"})})}),
+			wantBody:    map[string]any{"user": map[string]any{"@id": "1", "name": "jdoe", "role": []any{"admin", "dev"}}},
+			wantErr:     "at $.user.name: expected other (string), got jdoe (string)",
+		},
+		"Unexpected Content Type": {
+			contentType: "application/msgpack",
+			expected:    ByContentType(map[string]any{"application/json": Anything(), "application/xml": Anything()}),
+			wantBody:    nil,
+			wantErr:     "expected Content-Type application/json or application/xml, got \"application/msgpack\"",
+		},
+		"Unsupported Content Type": {
+			contentType: "text/plain",
+			expected:    expected,
+			wantBody:    nil,
+			wantErr:     "cannot decode content type \"text/plain\"",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, server.URL+"?type="+url.QueryEscape(tt.contentType), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body, err := doAndMatch(server.Client(), req, http.StatusOK, tt.expected)
+			if !reflect.DeepEqual(body, tt.wantBody) {
+				t.Errorf("Expected returned body %v, got %v", tt.wantBody, body)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+
+	if err := isMatch(`{}`, expected); err == nil || !strings.Contains(err.Error(), "ByContentType can only be used as the expectation of a response or request body") {
+		t.Errorf("Expected ByContentType to fail outside HTTP assertions, got %v", err)
+	}
+}
+
+func TestByContentTypeNDJSONOptions(t *testing.T) {
+	expected := ByContentType(map[string]any{"application/x-ndjson": Array(Object(map[string]any{"price": 0.1}))}).(contentTypeMatcher)
+	engine := defaultEngine.with([]Option{DecimalNumbers()})
+
+	body, err := engine.matchContent("application/x-ndjson", []byte(`{"price": 0.1}`+"\n"), expected)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := []any{map[string]any{"price": json.Number("0.1")}}; !reflect.DeepEqual(body, want) {
+		t.Errorf("Expected returned body %v, got %v", want, body)
+	}
+
+	_, err = engine.matchContent("application/x-ndjson", []byte(`{"price": 0.10000000000000001}`+"\n"), expected)
+	if want := "at $[0].price: expected 0.1"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected error containing %q, got %v", want, err)
+	}
+}
@@ -0,0 +1,295 @@
+package bodyguard
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Describer is implemented by matchers that can say in words which values they accept, such as
+// "UUID string", for failure messages and tooling such as documentation generators.
+// Every built-in matcher implements it. Custom matchers may implement it too: their description is
+// then used in failure messages, assertion manifests and by ToJSONSchema.
+type Describer interface {
+	Describe() string
+}
+
+// Describe returns the description of an expectation, e.g. "object with keys id, name?" for an Object
+// with an optional name, or "UUID string" for UUID().
+func Describe(expected interface{}) string {
+	if d, ok := expected.(Describer); ok {
+		if m, ok := expected.(Matcher); !ok || !isNilMatcher(m) {
+			return d.Describe()
+		}
+	}
+	return describeSchema(schemaOf(expected))
+}
+
+var (
+	_ Describer = describedMatcher{}
+	_ Describer = (*ObjectMatcher)(nil)
+	_ Describer = optionalMatcher{}
+	_ Describer = absentMatcher{}
+	_ Describer = allMatcher{}
+	_ Describer = invalidMatcher{}
+	_ Describer = envMatcher{}
+	_ Describer = contentTypeMatcher{}
+	_ Describer = (*templateMatcher)(nil)
+	_ Describer = (*Policy)(nil)
+	_ Describer = flatMatcher{}
+	_ Describer = (*pathMatcher)(nil)
+	_ Describer = describedBodyMatcher{}
+)
+
+func (m describedMatcher) Describe() string {
+	return describeSchema(m.schema)
+}
+
+func (m *ObjectMatcher) Describe() string {
+	return describeSchema(schemaOf(m))
+}
+
+func (m optionalMatcher) Describe() string {
+	return "optional " + Describe(m.expected)
+}
+
+func (absentMatcher) Describe() string {
+	return "absent key"
+}
+
+func (m allMatcher) Describe() string {
+	descriptions := make([]string, len(m))
+	for i, expected := range m {
+		descriptions[i] = Describe(expected)
+	}
+	return strings.Join(descriptions, " and ")
+}
+
+func (m invalidMatcher) Describe() string {
+	return describeSchema(&schema{invalid: m.err})
+}
+
+func (m envMatcher) Describe() string {
+	if m.only {
+		return fmt.Sprintf("%s in environment %s", Describe(m.expected), m.environment)
+	}
+	return fmt.Sprintf("%s except in environment %s", Describe(m.expected), m.environment)
+}
+
+func (m contentTypeMatcher) Describe() string {
+	return "body of content type " + strings.Join(slices.Sorted(maps.Keys(m)), " or ")
+}
+
+func (m *templateMatcher) Describe() string {
+	if m.err != nil {
+		return describeSchema(&schema{invalid: m.err})
+	}
+	return "template of " + Describe(m.document)
+}
+
+func (p *Policy) Describe() string {
+	rules := make([]string, len(p.Rules))
+	for i, rule := range p.Rules {
+		rules[i] = rule.Path + ": " + Describe(rule.Expected)
+	}
+	return "policy of " + strings.Join(rules, ", ")
+}
+
+func (m flatMatcher) Describe() string {
+	paths := make([]string, len(m))
+	for i, pm := range m {
+		paths[i] = pm.Describe()
+	}
+	return strings.Join(paths, ", ")
+}
+
+func (m *pathMatcher) Describe() string {
+	return m.selector + ": " + Describe(m.expected)
+}
+
+// describedBodyMatcher is a body matcher annotated with a description of the bodies it accepts.
+type describedBodyMatcher struct {
+	BodyMatcher
+	description string
+}
+
+func (m describedBodyMatcher) Describe() string {
+	return m.description
+}
+
+func (m describedBodyMatcher) matchBodyEngine(e *Engine, body []byte) error {
+	return e.matchBodyMatcher(m.BodyMatcher, body)
+}
+
+// describeBody annotates a body matcher with a description of the bodies it accepts.
+func describeBody(m BodyMatcher, description string) BodyMatcher {
+	return describedBodyMatcher{BodyMatcher: m, description: description}
+}
+
+// formatDescriptions describe the string formats of schemas.
+var formatDescriptions = map[string]string{
+	"uuid":      "UUID string",
+	"email":     "email address",
+	"uri":       "URL",
+	"date-time": "RFC3339 timestamp",
+	"date":      "date (YYYY-MM-DD)",
+}
+
+// describeSchema returns the description of the values accepted by a schema.
+func describeSchema(s *schema) string {
+	switch {
+	case s.invalid != nil:
+		return "invalid expectation: " + s.invalid.Error()
+	case s.description != "":
+		return s.description
+	case len(s.enum) == 1:
+		return describeLiteral(s.enum[0])
+	case len(s.enum) > 1:
+		literals := make([]string, len(s.enum))
+		for i, v := range s.enum {
+			literals[i] = describeLiteral(v)
+		}
+		return "one of " + strings.Join(literals, ", ")
+	case len(s.anyOf) > 0:
+		return joinSchemas(s.anyOf, " or ")
+	case len(s.allOf) > 0:
+		return joinSchemas(s.allOf, " and ")
+	}
+
+	switch s.typ {
+	case "string":
+		return describeString(s)
+	case "number", "integer":
+		return describeNumber(s)
+	case "boolean", "null":
+		return s.typ
+	case "object":
+		return describeObject(s)
+	case "array":
+		return describeArray(s)
+	}
+	if s.not != nil {
+		if s.not.typ == "null" && len(s.not.enum) == 0 {
+			return "non-null value"
+		}
+		return "not " + describeSchema(s.not)
+	}
+	return "any value"
+}
+
+func joinSchemas(schemas []*schema, sep string) string {
+	descriptions := make([]string, len(schemas))
+	for i, s := range schemas {
+		descriptions[i] = describeSchema(s)
+	}
+	return strings.Join(descriptions, sep)
+}
+
+func describeLiteral(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func describeString(s *schema) string {
+	if description, ok := formatDescriptions[s.format]; ok {
+		return description
+	}
+	description := "string"
+	if s.pattern != "" {
+		description += fmt.Sprintf(" matching %q", s.pattern)
+	}
+	return description + describeBounds(" of length", s.minLength, s.maxLength)
+}
+
+func describeNumber(s *schema) string {
+	var bounds []string
+	if s.minimum != nil && s.maximum != nil {
+		bounds = append(bounds, fmt.Sprintf("between %s and %s", formatFloat(*s.minimum), formatFloat(*s.maximum)))
+	} else if s.minimum != nil {
+		bounds = append(bounds, "at least "+formatFloat(*s.minimum))
+	} else if s.maximum != nil {
+		bounds = append(bounds, "at most "+formatFloat(*s.maximum))
+	}
+	if s.exclusiveMinimum != nil {
+		bounds = append(bounds, "greater than "+formatFloat(*s.exclusiveMinimum))
+	}
+	if s.exclusiveMaximum != nil {
+		bounds = append(bounds, "less than "+formatFloat(*s.exclusiveMaximum))
+	}
+	if len(bounds) == 0 {
+		return s.typ
+	}
+	return s.typ + " " + strings.Join(bounds, " and ")
+}
+
+func describeObject(s *schema) string {
+	description := "object"
+	if len(s.properties) > 0 {
+		keys := slices.Sorted(maps.Keys(s.properties))
+		for i, key := range keys {
+			if !slices.Contains(s.required, key) {
+				keys[i] += "?"
+			}
+		}
+		if s.closed {
+			description += " with only keys "
+		} else {
+			description += " with keys "
+		}
+		description += strings.Join(keys, ", ")
+	}
+	if s.propertyNames != nil {
+		description += " with keys of " + describeSchema(s.propertyNames)
+	}
+	for _, pattern := range slices.Sorted(maps.Keys(s.patternProperties)) {
+		description += fmt.Sprintf(" with keys matching %q of %s", pattern, describeSchema(s.patternProperties[pattern]))
+	}
+	if s.additional != nil {
+		description += " with values of " + describeSchema(s.additional)
+	}
+	return description + describeBounds(" of size", s.minProperties, s.maxProperties)
+}
+
+func describeArray(s *schema) string {
+	description := "array"
+	switch {
+	case len(s.prefixItems) > 0 && s.maxItems != nil && *s.maxItems == len(s.prefixItems):
+		return "array [" + joinSchemas(s.prefixItems, ", ") + "]"
+	case len(s.prefixItems) > 0:
+		description += " starting with [" + joinSchemas(s.prefixItems, ", ") + "]"
+	default:
+		description += describeBounds(" of length", s.minItems, s.maxItems)
+	}
+	if s.items != nil {
+		description += " of " + describeSchema(s.items)
+	}
+	if s.uniqueItems {
+		description += " without duplicates"
+	}
+	return description
+}
+
+// describeBounds describes optional bounds of a length or size, e.g. " of length 1 to 10".
+func describeBounds(prefix string, min, max *int) string {
+	switch {
+	case min != nil && max != nil && *min == *max:
+		return fmt.Sprintf("%s %d", prefix, *min)
+	case min != nil && max != nil:
+		return fmt.Sprintf("%s %d to %d", prefix, *min, *max)
+	case min != nil && *min > 0:
+		return fmt.Sprintf("%s at least %d", prefix, *min)
+	case max != nil:
+		return fmt.Sprintf("%s at most %d", prefix, *max)
+	}
+	return ""
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,212 @@
+package bodyguard
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// describedUser is a custom matcher describing itself.
+type describedUser struct{}
+
+func (describedUser) Match(path string, value interface{}) error { return nil }
+
+func (describedUser) Describe() string { return "user record" }
+
+func TestDescribe(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var dest string
+	tests := map[string]struct {
+		expected interface{}
+		want     string
+	}{
+		"UUID":                 {expected: UUID(), want: "UUID string"},
+		"UUIDLiteral":          {expected: UUIDLiteral("1b4e28ba-2fa1-11d2-883f-0016d3cca427"), want: "UUID 1b4e28ba-2fa1-11d2-883f-0016d3cca427"},
+		"Email":                {expected: Email(), want: "email address"},
+		"URL":                  {expected: URL(), want: "URL"},
+		"Timestamp":            {expected: Timestamp(), want: "RFC3339 timestamp"},
+		"TimeLiteral":          {expected: TimeLiteral(start), want: "RFC3339 timestamp equal to 2024-01-01T00:00:00Z"},
+		"TimeWithinDuration":   {expected: TimeWithinDuration(start, time.Minute), want: "RFC3339 timestamp within 1m0s of 2024-01-01T00:00:00Z"},
+		"TimeWithinRange":      {expected: TimeWithinRange(start, start.Add(time.Hour)), want: "RFC3339 timestamp between 2024-01-01T00:00:00Z and 2024-01-01T01:00:00Z"},
+		"TimeBefore":           {expected: TimeBefore(start), want: "RFC3339 timestamp before 2024-01-01T00:00:00Z"},
+		"TimeAfter":            {expected: TimeAfter(start), want: "RFC3339 timestamp after 2024-01-01T00:00:00Z"},
+		"Date":                 {expected: Date(), want: "date (YYYY-MM-DD)"},
+		"HTTPDate":             {expected: HTTPDate(), want: "HTTP-date"},
+		"TZName":               {expected: TZName(), want: "IANA time zone name"},
+		"String":               {expected: String(), want: "string"},
+		"Regexp":               {expected: Regexp("^[a-z]+$"), want: `string matching "^[a-z]+$"`},
+		"StringLength":         {expected: StringLength(1, 10), want: "string of length 1 to 10"},
+		"StringWithFormat":     {expected: StringWithFormat(func(string) error { return nil }), want: "string in a custom format"},
+		"DigitsOnly":           {expected: DigitsOnly(4, 6), want: `string matching "^[0-9]*$" of length 4 to 6`},
+		"PaddedNumericString":  {expected: PaddedNumericString(8), want: `string matching "^[0-9]{8}$"`},
+		"MinEntropy":           {expected: MinEntropy(3.5), want: "string of at least 3.5 bits of entropy per character"},
+		"NotSequential":        {expected: NotSequential(), want: "string other than a run of repeated or consecutive characters"},
+		"WellFormedUnicode":    {expected: WellFormedUnicode(), want: "string of well-formed unicode"},
+		"StringAsInt":          {expected: StringAsInt(Positive()), want: `string matching "^[-+]?[0-9]+$"`},
+		"StringAsFloat":        {expected: StringAsFloat(), want: "string holding a number"},
+		"GzipBase64JSON":       {expected: GzipBase64JSON(Object(map[string]any{"id": UUID()})), want: "base64 string of gzip-compressed object with keys id"},
+		"Integer":              {expected: Integer(), want: "integer"},
+		"Number":               {expected: Number(), want: "number"},
+		"NumberWithinRange":    {expected: NumberWithinRange(0, 1.5), want: "number between 0 and 1.5"},
+		"NumberWithinDelta":    {expected: NumberWithinDelta(10, 0.5), want: "number between 9.5 and 10.5"},
+		"NumberGreater":        {expected: NumberGreater(3), want: "number greater than 3"},
+		"NumberSmaller":        {expected: NumberSmaller(3), want: "number less than 3"},
+		"Positive":             {expected: Positive(), want: "number greater than 0"},
+		"Negative":             {expected: Negative(), want: "number less than 0"},
+		"Bool":                 {expected: Bool(), want: "boolean"},
+		"Null":                 {expected: Null(), want: "null"},
+		"NotNull":              {expected: NotNull(), want: "non-null value"},
+		"Anything":             {expected: Anything(), want: "any value"},
+		"OneOf":                {expected: OneOf("a", "b"), want: `one of "a", "b"`},
+		"Literal":              {expected: "open", want: `"open"`},
+		"Any":                  {expected: Any(nil, UUID()), want: "null or UUID string"},
+		"All":                  {expected: All(String(), StringLength(1, 3)), want: "string and string of length 1 to 3"},
+		"Not":                  {expected: Not("admin"), want: `not "admin"`},
+		"Object":               {expected: Object(map[string]any{"id": UUID(), "name": Optional(String())}), want: "object with keys id, name?"},
+		"StrictObject":         {expected: StrictObject(map[string]any{"id": UUID()}), want: "object with only keys id"},
+		"MapOf":                {expected: MapOf(String(), Integer()), want: "object with keys of string with values of integer"},
+		"KeysMatching":         {expected: KeysMatching("^x-", String()), want: `object with keys matching "^x-" of string`},
+		"ObjectSize":           {expected: ObjectSize(1, 3), want: "object of size 1 to 3"},
+		"FromStruct":           {expected: FromStruct(orderDTO{}, nil), want: "object with only keys created_at, id, total"},
+		"FromTaggedStruct":     {expected: FromTaggedStruct[taggedAccount](), want: "object with keys active, age, created_at, deleted_at?, email, id, labels, nick, parent, role, score, slug, tags"},
+		"Template":             {expected: Template(`{"id": "${id}"}`, Vars{"id": 1}), want: "template of object with only keys id"},
+		"Each":                 {expected: Each(UUID()), want: "array of UUID string"},
+		"EachOneOf":            {expected: EachOneOf("a", "b"), want: `array of one of "a", "b"`},
+		"Array":                {expected: Array("a", Integer()), want: `array ["a", integer]`},
+		"ArrayOf":              {expected: ArrayOf(1, 2), want: "array [1, 2]"},
+		"ArrayPrefix":          {expected: ArrayPrefix("a"), want: `array starting with ["a"]`},
+		"ArrayStartsWith":      {expected: ArrayStartsWith("a"), want: `array starting with ["a"]`},
+		"ArrayMatchingPattern": {expected: ArrayMatchingPattern([]any{"a"}, Integer()), want: `array starting with ["a"], followed by elements of integer`},
+		"Indexed":              {expected: Indexed(map[int]any{2: Integer(), 0: "a"}), want: `array with [0] "a" and [2] integer`},
+		"ArrayLength":          {expected: ArrayLength(1, 3), want: "array of length 1 to 3"},
+		"ArrayLengthExact":     {expected: ArrayLengthExact(2), want: "array of length 2"},
+		"UnorderedArray":       {expected: UnorderedArray("a", Integer()), want: `array of length 2 of "a" or integer`},
+		"Multiset":             {expected: Multiset("a", "b"), want: `array of length 2 of "a" or "b"`},
+		"ArraySuperset":        {expected: ArraySuperset("a"), want: "array of length at least 1"},
+		"ArraySubset":          {expected: ArraySubset("a", "b"), want: `array of length at most 2 of "a" or "b"`},
+		"Unique":               {expected: Unique(), want: "array without duplicates"},
+		"UniqueBy":             {expected: UniqueBy("id"), want: `array of objects with distinct "id" values`},
+		"SortedStrings":        {expected: SortedStrings(ByteOrder), want: "array of sorted strings"},
+		"Sorted":               {expected: Sorted(Desc), want: "array sorted in descending order"},
+		"SortedBy":             {expected: SortedBy("id", Asc), want: `array of objects sorted by "id" in ascending order`},
+		"Optional":             {expected: Optional(UUID()), want: "optional UUID string"},
+		"Absent":               {expected: Absent(), want: "absent key"},
+		"OnlyInEnv":            {expected: OnlyInEnv("CI", UUID()), want: "UUID string in environment CI"},
+		"SkipInEnv":            {expected: SkipInEnv("CI", UUID()), want: "UUID string except in environment CI"},
+		"Label":                {expected: Label("order id", UUID()), want: "UUID string"},
+		"Empty":                {expected: Empty(), want: "empty string, array or object"},
+		"NotEmpty":             {expected: NotEmpty(), want: "non-empty string, array or object"},
+		"ExpiresAfterCreation": {expected: ExpiresAfterCreation("created_at", "expires_at", time.Hour, time.Second), want: "object whose expires_at is 1h0m0s after created_at"},
+		"Wrap":                 {expected: Wrap(UUID(), nil, nil), want: "UUID string"},
+		"Capture":              {expected: Capture("id"), want: `any value, captured as "id"`},
+		"CaptureAs":            {expected: CaptureAs(&dest), want: "value captured as string"},
+		"SameAs":               {expected: SameAs("id"), want: `value equal to the one captured as "id"`},
+		"ConsistentValues":     {expected: ConsistentValues("tenant"), want: `value equal to every other of class "tenant"`},
+		"ValidUTF8":            {expected: ValidUTF8(), want: "valid UTF-8 body"},
+		"NoBOM":                {expected: NoBOM(), want: "body without byte order mark"},
+		"MaxBodyBytes":         {expected: MaxBodyBytes(1024), want: "body of at most 1024 bytes"},
+		"PlainNumberFormat":    {expected: PlainNumberFormat(), want: "body with numbers without exponent"},
+		"EmptyBody":            {expected: EmptyBody(), want: "empty body"},
+		"ByContentType":        {expected: ByContentType(map[string]interface{}{"application/json": UUID()}), want: "body of content type application/json"},
+		"Lines":                {expected: Lines(UUID(), Integer()), want: "newline-delimited JSON body of 2 lines"},
+		"EachLine":             {expected: EachLine(Integer()), want: "newline-delimited JSON body, each line integer"},
+		"Custom Describer":     {expected: describedUser{}, want: "user record"},
+		"Custom Matcher":       {expected: MatcherFunc(func(string, interface{}) error { return nil }), want: "any value"},
+		"Nil Matcher":          {expected: MatcherFunc(nil), want: "invalid expectation: nil Matcher"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := Describe(tt.expected); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	// Every exported constructor of a Matcher, BodyMatcher or *ObjectMatcher needs a case above named after it,
+	// and a description that says more than the JSON type it accepts.
+	generic := map[string]bool{"any value": true, "string": true, "number": true, "array": true, "object": true}
+	for _, name := range matcherConstructors(t) {
+		tt, ok := tests[name]
+		if !ok {
+			t.Errorf("Expected a TestDescribe case for %s", name)
+			continue
+		}
+		if generic[tt.want] && name != "String" && name != "Number" && name != "Anything" {
+			t.Errorf("Expected %s to describe what it checks, got %q", name, tt.want)
+		}
+	}
+}
+
+// matcherConstructors returns the names of the exported functions of the package returning a Matcher,
+// BodyMatcher or *ObjectMatcher.
+func matcherConstructors(t *testing.T) []string {
+	t.Helper()
+	paths, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("Expected no error listing the package, got %v", err)
+	}
+
+	var names []string
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			t.Fatalf("Expected no error parsing %s, got %v", path, err)
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || !fn.Name.IsExported() || fn.Type.Results == nil || len(fn.Type.Results.List) != 1 {
+				continue
+			}
+			switch result := fn.Type.Results.List[0].Type.(type) {
+			case *ast.Ident:
+				if result.Name == "Matcher" || result.Name == "BodyMatcher" {
+					names = append(names, fn.Name.Name)
+				}
+			case *ast.StarExpr:
+				if ident, ok := result.X.(*ast.Ident); ok && ident.Name == "ObjectMatcher" {
+					names = append(names, fn.Name.Name)
+				}
+			}
+		}
+	}
+	if len(names) == 0 {
+		t.Fatal("Expected to find matcher constructors")
+	}
+	return names
+}
+
+func TestDescribeInTooling(t *testing.T) {
+	err := isMatch(`["x"]`, UnorderedArray(UUID()))
+	if want := "expected element UUID string (index 0) not found"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected error containing %q, got %v", want, err)
+	}
+
+	err = isMatch(`"1b4e28ba-2fa1-11d2-883f-0016d3cca427"`, Not(UUID()))
+	if want := "at $: expected not to match UUID string, got"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected error containing %q, got %v", want, err)
+	}
+
+	doc, err := ToJSONSchema(Object(map[string]any{"user": describedUser{}}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := `"description": "user record"`; !strings.Contains(string(doc), want) {
+		t.Errorf("Expected schema containing %q, got %s", want, doc)
+	}
+
+	if got := summarize(Object(map[string]any{"user": describedUser{}}).AllowExtra()); got != "object{user}" {
+		t.Errorf("Expected manifest summary object{user}, got %q", got)
+	}
+	if got := summarize(describedUser{}); got != "user record" {
+		t.Errorf("Expected manifest summary of a custom matcher to use its description, got %q", got)
+	}
+}
@@ -0,0 +1,158 @@
+package bodyguard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// renderDiff renders the actual document with every mismatch of err marked on the line of its value:
+//
+//	  {
+//	✗   "age": "x",  // expected number, got string
+//	    "name": "jdoe"
+//	  }
+//
+// Elements of arrays found at another index than expected are marked as moved, without their mismatches.
+func renderDiff(actual interface{}, err error, moves map[string]string) string {
+	messages := map[string][]string{}
+	var format PathFormat
+	var mErr *MatchError
+	if errors.As(err, &mErr) {
+		format = mErr.format
+		for _, leaf := range mErr.Leaves() {
+			msg := strings.TrimPrefix(leaf.Error(), format.renderMessage("at "+leaf.path)+": ")
+			messages[leaf.path] = append(messages[leaf.path], strings.ReplaceAll(msg, "\n", " "))
+		}
+	}
+
+	r := &diffRenderer{messages: messages, moves: moves, format: format}
+	r.value("$", 0, "", actual, "")
+
+	// mismatches at paths not found in the document are listed last
+	for _, path := range slices.Sorted(maps.Keys(r.messages)) {
+		r.b.WriteString(fmt.Sprintf("✗ %s  // %s\n", format.render(path), strings.Join(r.messages[path], "; ")))
+	}
+	return strings.TrimSuffix(r.b.String(), "\n")
+}
+
+type diffRenderer struct {
+	b        strings.Builder
+	messages map[string][]string
+	moves    map[string]string
+	format   PathFormat
+}
+
+// line writes one line of the rendering, marked when the value at path has mismatches or was moved.
+func (r *diffRenderer) line(path string, text string) {
+	if from, moved := r.moves[path]; moved {
+		r.b.WriteString("↕ " + text + "  // moved from " + r.format.render(from) + "\n")
+		return
+	}
+	msgs, failed := r.messages[path]
+	if !failed {
+		r.b.WriteString("  " + text + "\n")
+		return
+	}
+	delete(r.messages, path)
+	r.b.WriteString("✗ " + text + "  // " + strings.Join(msgs, "; ") + "\n")
+}
+
+// value renders the value at path, indented depth levels, prefixed with its key and followed by suffix, such as a comma.
+func (r *diffRenderer) value(path string, depth int, prefix string, value interface{}, suffix string) {
+	indent := strings.Repeat("  ", depth)
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := slices.DeleteFunc(slices.Sorted(maps.Keys(v)), func(key string) bool {
+			_, ignored := v[key].(ignoredValue)
+			return ignored
+		})
+		if len(keys) == 0 {
+			r.line(path, indent+prefix+"{}"+suffix)
+			return
+		}
+		r.line(path, indent+prefix+"{")
+		for i, key := range keys {
+			name, _ := json.Marshal(key)
+			r.value(fmt.Sprintf("%s.%s", path, key), depth+1, string(name)+": ", v[key], comma(i, len(keys)))
+		}
+		r.b.WriteString("  " + indent + "}" + suffix + "\n")
+	case []interface{}:
+		elements, indices := arrayElements(v)
+		if len(elements) == 0 {
+			r.line(path, indent+prefix+"[]"+suffix)
+			return
+		}
+		r.line(path, indent+prefix+"[")
+		for i, element := range elements {
+			childPath := elementPath(path, indices[i], element)
+			if _, moved := r.moves[childPath]; moved {
+				r.dropMessages(childPath)
+			}
+			r.value(childPath, depth+1, "", element, comma(i, len(elements)))
+		}
+		r.b.WriteString("  " + indent + "]" + suffix + "\n")
+	default:
+		text, err := json.Marshal(v)
+		if err != nil {
+			text = []byte(fmt.Sprint(v))
+		}
+		r.line(path, indent+prefix+string(text)+suffix)
+	}
+}
+
+// dropMessages removes the mismatches of the value at path and of every value below it.
+func (r *diffRenderer) dropMessages(path string) {
+	maps.DeleteFunc(r.messages, func(p string, _ []string) bool {
+		return p == path || strings.HasPrefix(p, path+".") || strings.HasPrefix(p, path+"[")
+	})
+}
+
+func comma(i, n int) string {
+	if i < n-1 {
+		return ","
+	}
+	return ""
+}
+
+// detectMove records the i-th element of actual, reported at childPath, as moved when an element expected at another index has the
+// same identity and matches it, so the diff reports an ordering change rather than a changed element.
+func (st *matchState) detectMove(path, childPath string, i int, expected, actual []interface{}) {
+	id, ok := elementID(actual[i])
+	if !ok {
+		return
+	}
+	for j, e := range expected {
+		if want, ok := expectedID(e); !ok || j == i || fmt.Sprint(want) != fmt.Sprint(id) {
+			continue
+		}
+		probe := st.fork()
+		probe.beforeMatch, probe.afterMatch, probe.moves = nil, nil, nil
+		if match(probe, e, childPath, actual[i]) == nil {
+			st.moves[childPath] = fmt.Sprintf("%s[%d]", path, j)
+			return
+		}
+	}
+}
+
+// expectedID returns the literal "id" field of an expected object.
+func expectedID(expected interface{}) (interface{}, bool) {
+	var fields map[string]interface{}
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		fields = e
+	case *ObjectMatcher:
+		fields = e.expected
+	default:
+		return nil, false
+	}
+	id, ok := fields["id"]
+	if _, isMatcher := id.(Matcher); !ok || isMatcher || id == nil {
+		return nil, false
+	}
+	return id, true
+}
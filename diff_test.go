@@ -0,0 +1,85 @@
+package bodyguard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithDiff(t *testing.T) {
+	body := `{"name": "jdoe", "age": "x", "roles": ["user", 1], "tags": [], "meta": {}}`
+	expected := Object(map[string]any{
+		"name":  "jdoe",
+		"age":   Number(),
+		"roles": Array("admin", String()),
+		"email": Email(),
+	})
+
+	err := defaultEngine.with([]Option{WithDiff()}).isMatch(body, expected)
+	if err == nil {
+		t.Fatal("Expected mismatches")
+	}
+
+	want := `actual document:
+✗ {  // missing key "email"
+✗   "age": "x",  // expected number, got string
+    "meta": {},
+    "name": "jdoe",
+    "roles": [
+✗     "user",  // expected admin (string), got user (string)
+✗     1  // expected string, got float64
+    ],
+    "tags": []
+  }`
+	if !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("Expected diff\n%s\ngot\n%s", want, err.Error())
+	}
+	if !strings.HasPrefix(err.Error(), "at $.age: expected number, got string\nat $: missing key \"email\"\n") {
+		t.Errorf("Expected the mismatches before the diff, got %q", err.Error())
+	}
+
+	if err := isMatch(body, expected); strings.Contains(err.Error(), "actual document") {
+		t.Errorf("Expected no diff without the option, got %q", err.Error())
+	}
+}
+
+func TestWithDiffMoves(t *testing.T) {
+	body := `{"items": [{"id": 2, "name": "b"}, {"id": 1, "name": "a"}, {"id": 3, "name": "x"}]}`
+	expected := Object(map[string]any{
+		"items": Array(
+			Object(map[string]any{"id": 1, "name": "a"}),
+			map[string]any{"id": float64(2), "name": "b"},
+			Object(map[string]any{"id": 3, "name": "c"}),
+		),
+	})
+
+	err := defaultEngine.with([]Option{WithDiff()}).isMatch(body, expected)
+	if err == nil {
+		t.Fatal("Expected mismatches")
+	}
+
+	want := `actual document:
+  {
+    "items": [
+↕     {  // moved from $.items[1]
+        "id": 2,
+        "name": "b"
+      },
+↕     {  // moved from $.items[0]
+        "id": 1,
+        "name": "a"
+      },
+      {
+        "id": 3,
+✗       "name": "x"  // expected c (string), got x (string)
+      }
+    ]
+  }`
+	if !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("Expected diff\n%s\ngot\n%s", want, err.Error())
+	}
+
+	err = defaultEngine.with([]Option{WithDiff(), WithPathFormat(JSONPointer)}).isMatch(body, expected)
+	if err == nil || !strings.Contains(err.Error(), "// moved from /items/1") {
+		t.Errorf("Expected the move in the path format, got %v", err)
+	}
+}
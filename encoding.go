@@ -0,0 +1,81 @@
+package bodyguard
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// contentEncodings maps a lower-case Content-Encoding token to a func(io.Reader) (io.Reader, error).
+var contentEncodings sync.Map
+
+func init() {
+	newGzipReader := func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }
+	RegisterContentEncoding("gzip", newGzipReader)
+	RegisterContentEncoding("x-gzip", newGzipReader)
+	RegisterContentEncoding("deflate", newDeflateReader)
+}
+
+// RegisterContentEncoding registers how bodies with the given Content-Encoding are decompressed
+// before they are matched. gzip, x-gzip and deflate are registered by default; other encodings,
+// such as brotli, can be added with a third-party decoder, e.g.
+//
+//	bodyguard.RegisterContentEncoding("br", func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil })
+//
+// Registering an encoding again replaces its decoder. It is safe for concurrent use.
+func RegisterContentEncoding(name string, newReader func(r io.Reader) (io.Reader, error)) {
+	contentEncodings.Store(strings.ToLower(name), newReader)
+}
+
+// newDeflateReader reads a deflate body, which is zlib-wrapped per RFC 9110 although some servers
+// send raw deflate data.
+func newDeflateReader(r io.Reader) (io.Reader, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if zr, err := zlib.NewReader(bytes.NewReader(data)); err == nil {
+		return zr, nil
+	}
+	return flate.NewReader(bytes.NewReader(data)), nil
+}
+
+// Decompress decodes a body according to its Content-Encoding header, undoing every listed encoding
+// in reverse order. An empty or identity encoding returns the body unchanged.
+func Decompress(contentEncoding string, body []byte) ([]byte, error) {
+	return decompress(contentEncoding, body, 0)
+}
+
+// decompress is Decompress with a limit on the size of the decompressed body, when limit is positive.
+func decompress(contentEncoding string, body []byte, limit int64) ([]byte, error) {
+	codings := strings.Split(contentEncoding, ",")
+	for i := len(codings) - 1; i >= 0; i-- {
+		coding := strings.ToLower(strings.TrimSpace(codings[i]))
+		if coding == "" || coding == "identity" {
+			continue
+		}
+		newReader, ok := contentEncodings.Load(coding)
+		if !ok {
+			return nil, fmt.Errorf("unsupported Content-Encoding %q", coding)
+		}
+		r, err := newReader.(func(io.Reader) (io.Reader, error))(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s body: %w", coding, err)
+		}
+		if limit > 0 {
+			r = io.LimitReader(r, limit+1)
+		}
+		if body, err = io.ReadAll(r); err != nil {
+			return nil, fmt.Errorf("invalid %s body: %w", coding, err)
+		}
+		if limit > 0 && int64(len(body)) > limit {
+			return nil, fmt.Errorf("expected decompressed body of at most %d bytes, got more", limit)
+		}
+	}
+	return body, nil
+}
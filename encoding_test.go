@@ -0,0 +1,192 @@
+package bodyguard
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func compress(t *testing.T, encoding, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "zlib":
+		w = zlib.NewWriter(&buf)
+	case "flate":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	}
+	if _, err := w.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecompress(t *testing.T) {
+	RegisterContentEncoding("X-Reverse", func(r io.Reader) (io.Reader, error) {
+		data, err := io.ReadAll(r)
+		for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+			data[i], data[j] = data[j], data[i]
+		}
+		return bytes.NewReader(data), err
+	})
+
+	tests := map[string]struct {
+		encoding string
+		body     []byte
+		want     string
+		wantErr  string
+	}{
+		"No Encoding": {
+			encoding: "",
+			body:     []byte(`{"id": 1}`),
+			want:     `{"id": 1}`,
+		},
+		"Identity": {
+			encoding: "identity",
+			body:     []byte(`{"id": 1}`),
+			want:     `{"id": 1}`,
+		},
+		"Gzip": {
+			encoding: "gzip",
+			body:     compress(t, "gzip", `{"id": 1}`),
+			want:     `{"id": 1}`,
+		},
+		"X-Gzip": {
+			encoding: "x-gzip",
+			body:     compress(t, "gzip", `{"id": 1}`),
+			want:     `{"id": 1}`,
+		},
+		"Deflate": {
+			encoding: "Deflate",
+			body:     compress(t, "zlib", `{"id": 1}`),
+			want:     `{"id": 1}`,
+		},
+		"Raw Deflate": {
+			encoding: "deflate",
+			body:     compress(t, "flate", `{"id": 1}`),
+			want:     `{"id": 1}`,
+		},
+		"Registered Encoding": {
+			encoding: "x-reverse",
+			body:     []byte(`}1 :"di"{`),
+			want:     `{"id": 1}`,
+		},
+		"Several Encodings": {
+			encoding: "x-reverse, gzip",
+			body:     compress(t, "gzip", `}1 :"di"{`),
+			want:     `{"id": 1}`,
+		},
+		"Unsupported Encoding": {
+			encoding: "compress",
+			body:     []byte(`{"id": 1}`),
+			wantErr:  "unsupported Content-Encoding \"compress\"",
+		},
+		"Invalid Gzip": {
+			encoding: "gzip",
+			body:     []byte(`{"id": 1, "name": "jdoe"}`),
+			wantErr:  "invalid gzip body: gzip: invalid header",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Decompress(tt.encoding, tt.body)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				} else if string(got) != tt.want {
+					t.Errorf("Expected %q, got %q", tt.want, got)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestDoAndMatchDecompresses(t *testing.T) {
+	large := `{"data": "` + strings.Repeat("x", 1000) + `"}`
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /gzip", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(compress(t, "gzip", `{"id": 1}`))
+	})
+	mux.HandleFunc("GET /deflate", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "deflate")
+		_, _ = w.Write(compress(t, "zlib", `{"id": 1}`))
+	})
+	mux.HandleFunc("GET /large", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(compress(t, "gzip", large))
+	})
+	mux.HandleFunc("GET /unsupported", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "compress")
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := map[string]struct {
+		path    string
+		opts    []Option
+		wantErr string
+	}{
+		"Gzip Pass": {
+			path:    "/gzip",
+			wantErr: "",
+		},
+		"Deflate Pass": {
+			path:    "/deflate",
+			wantErr: "",
+		},
+		"Decompressed Too Large": {
+			path:    "/large",
+			opts:    []Option{MaxBodySize(100)},
+			wantErr: "GET " + server.URL + "/large: cannot decode response body: expected decompressed body of at most 100 bytes, got more",
+		},
+		"Unsupported Encoding": {
+			path:    "/unsupported",
+			wantErr: "GET " + server.URL + "/unsupported: cannot decode response body: unsupported Content-Encoding \"compress\"",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, server.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// an explicit Accept-Encoding stops the transport from decompressing gzip itself
+			req.Header.Set("Accept-Encoding", "gzip, deflate")
+
+			_, err = defaultEngine.with(tt.opts).doAndMatch(server.Client(), req, http.StatusOK, Object(map[string]any{"id": 1}))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
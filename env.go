@@ -0,0 +1,65 @@
+package bodyguard
+
+import (
+	"os"
+	"strings"
+)
+
+// EnvironmentVariable names the variable holding the environment tests run in, such as "CI" or "local",
+// when the engine's Environment is not set.
+const EnvironmentVariable = "BODYGUARD_ENV"
+
+// envMatcher is returned by OnlyInEnv and SkipInEnv.
+type envMatcher struct {
+	environment string
+	expected    interface{}
+	only        bool // applies only in the environment, rather than everywhere but in it
+}
+
+func (m envMatcher) Match(path string, value interface{}) error {
+	return matchRoot(newMatchState(), m, path, value)
+}
+
+func (m envMatcher) matchState(st *matchState, path string, value interface{}) error {
+	if !m.active(st) {
+		return nil
+	}
+	return match(st, m.expected, path, value)
+}
+
+// keyPresence lets the key be missing wherever the expectation does not apply. Without a match run the
+// environment is unknown, so the key may always be missing.
+func (m envMatcher) keyPresence(st *matchState) keyPresence {
+	if st == nil || !m.active(st) {
+		return keyOptional
+	}
+	return presenceOf(st, m.expected)
+}
+
+// active reports whether the expectation applies in the environment of the match run.
+func (m envMatcher) active(st *matchState) bool {
+	return strings.EqualFold(st.environment, m.environment) == m.only
+}
+
+// OnlyInEnv applies expected only when tests run in the named environment, and accepts any value,
+// or a missing object key, elsewhere. This lets checks that only hold against real backends live in the
+// expectation used with local fakes:
+//
+//	"region": bodyguard.OnlyInEnv("CI", bodyguard.OneOf("eu-west-1", "us-east-1")),
+//
+// The environment is the engine's Environment or, when that is not set, the BODYGUARD_ENV variable.
+// It is compared case-insensitively.
+func OnlyInEnv(environment string, expected interface{}) Matcher {
+	return envMatcher{environment: environment, expected: expected, only: true}
+}
+
+// SkipInEnv applies expected except when tests run in the named environment, where it accepts any
+// value or a missing object key. See OnlyInEnv.
+func SkipInEnv(environment string, expected interface{}) Matcher {
+	return envMatcher{environment: environment, expected: expected}
+}
+
+// currentEnvironment returns the environment set in the BODYGUARD_ENV variable.
+func currentEnvironment() string {
+	return os.Getenv(EnvironmentVariable)
+}
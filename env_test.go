@@ -0,0 +1,89 @@
+package bodyguard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnvMatchers(t *testing.T) {
+	expected := Object(map[string]any{
+		"id":     Integer(),
+		"region": OnlyInEnv("CI", OneOf("eu-west-1", "us-east-1")),
+		"host":   SkipInEnv("local", Regexp(`\.example\.com$`)),
+	})
+
+	tests := map[string]struct {
+		environment string
+		body        string
+		wantErr     string
+	}{
+		"CI Pass": {
+			environment: "CI",
+			body:        `{"id": 1, "region": "eu-west-1", "host": "api.example.com"}`,
+			wantErr:     "",
+		},
+		"CI Fail": {
+			environment: "ci",
+			body:        `{"id": 1, "region": "fake", "host": "api.example.com"}`,
+			wantErr:     "at $.region: expected one of [eu-west-1 us-east-1], got \"fake\"",
+		},
+		"CI Missing Key": {
+			environment: "CI",
+			body:        `{"id": 1, "host": "api.example.com"}`,
+			wantErr:     "at $: missing key \"region\"",
+		},
+		"Local Skips Checks": {
+			environment: "local",
+			body:        `{"id": 1, "region": "fake", "host": "localhost"}`,
+			wantErr:     "",
+		},
+		"Local Skips Missing Keys": {
+			environment: "local",
+			body:        `{"id": 1}`,
+			wantErr:     "",
+		},
+		"Local Still Checks Others": {
+			environment: "local",
+			body:        `{"id": "1"}`,
+			wantErr:     "at $.id: expected number, got string",
+		},
+		"No Environment": {
+			environment: "",
+			body:        `{"id": 1, "host": "localhost"}`,
+			wantErr:     "at $.host: expected to match \"\\\\.example\\\\.com$\", got \"localhost\"",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(EnvironmentVariable, "")
+			err := defaultEngine.with([]Option{InEnvironment(tt.environment)}).isMatch(tt.body, expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestEnvironmentVariable(t *testing.T) {
+	t.Setenv(EnvironmentVariable, "local")
+	m := SkipInEnv("local", Integer())
+
+	if err := isMatch(`"x"`, m); err != nil {
+		t.Errorf("Expected the check to be skipped, got %v", err)
+	}
+	if err := m.Match("$", "x"); err != nil {
+		t.Errorf("Expected the check to be skipped by Match, got %v", err)
+	}
+	if err := defaultEngine.with([]Option{InEnvironment("CI")}).isMatch(`"x"`, m); err == nil {
+		t.Error("Expected the engine's environment to override the variable")
+	}
+}
@@ -0,0 +1,110 @@
+package bodyguard
+
+import "errors"
+
+// MatchError is the error returned when a value does not match its expectation.
+// Failed assertions return a *MatchError for the root of the document, whose children
+// are the mismatches found below it, so reports can be built and filtered programmatically:
+//
+//	var mErr *bodyguard.MatchError
+//	if errors.As(err, &mErr) {
+//		for _, leaf := range mErr.Leaves() { ... }
+//	}
+//
+// Its message is the same as the plain text reported by Assert.
+type MatchError struct {
+	path     string
+	expected interface{}
+	actual   interface{}
+	err      error
+	format   PathFormat
+}
+
+// Path returns the path of the mismatched value, e.g. "$.data[0].id", or "/data/0/id"
+// when the JSONPointer format was requested.
+func (e *MatchError) Path() string {
+	return e.format.render(e.path)
+}
+
+// Expected returns the literal or matcher the value was matched against.
+func (e *MatchError) Expected() interface{} {
+	return e.expected
+}
+
+// Actual returns the decoded value found at Path.
+func (e *MatchError) Actual() interface{} {
+	return e.actual
+}
+
+// Children returns the mismatches found below this value, such as the failing keys of an object.
+// Several mismatches of the value itself, such as missing keys, are also returned as children.
+// It is empty for a single mismatch of the value itself.
+func (e *MatchError) Children() []*MatchError {
+	children := e.childErrors(e.err)
+	if e.format != JSONPath {
+		for i, child := range children {
+			formatted := *child
+			formatted.format = e.format
+			children[i] = &formatted
+		}
+	}
+	return children
+}
+
+// childErrors returns the outermost match errors wrapped by err. Parts of a joined error
+// that are not match errors are attributed to e's own path.
+func (e *MatchError) childErrors(err error) []*MatchError {
+	switch t := err.(type) {
+	case *MatchError:
+		return []*MatchError{t}
+	case interface{ Unwrap() []error }:
+		parts := t.Unwrap()
+		if len(parts) == 1 {
+			return e.childErrors(parts[0])
+		}
+		var children []*MatchError
+		for _, part := range parts {
+			found := e.childErrors(part)
+			if len(found) == 0 {
+				found = []*MatchError{{path: e.path, expected: e.expected, actual: e.actual, err: part}}
+			}
+			children = append(children, found...)
+		}
+		return children
+	}
+	if wrapped := errors.Unwrap(err); wrapped != nil {
+		return e.childErrors(wrapped)
+	}
+	return nil
+}
+
+// Leaves returns the mismatches without children found at or below this value.
+func (e *MatchError) Leaves() []*MatchError {
+	children := e.Children()
+	if len(children) == 0 {
+		return []*MatchError{e}
+	}
+
+	var leaves []*MatchError
+	for _, child := range children {
+		leaves = append(leaves, child.Leaves()...)
+	}
+	return leaves
+}
+
+func (e *MatchError) Error() string {
+	return e.format.renderMessage(e.err.Error())
+}
+
+func (e *MatchError) Unwrap() error {
+	return e.err
+}
+
+// newMatchError records the expectation and value of a mismatch found at path.
+// Errors already recorded for the same path are returned unchanged.
+func newMatchError(path string, expected, actual interface{}, err error) error {
+	if mErr, ok := err.(*MatchError); ok && mErr.path == path {
+		return err
+	}
+	return &MatchError{path: path, expected: expected, actual: actual, err: err}
+}
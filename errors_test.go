@@ -0,0 +1,81 @@
+package bodyguard
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMatchError(t *testing.T) {
+	expected := Object(map[string]any{
+		"name":  "jdoe",
+		"roles": Array("admin", String()),
+		"age":   Number(),
+	})
+	err := isMatch(`{"name": "jdoe", "roles": ["user", 1], "age": "x"}`, expected)
+
+	var mErr *MatchError
+	if !errors.As(err, &mErr) {
+		t.Fatalf("Expected a *MatchError, got %T", err)
+	}
+	if mErr.Path() != "$" || mErr.Expected() != expected {
+		t.Errorf("Expected the root error, got path %q and expected %v", mErr.Path(), mErr.Expected())
+	}
+
+	var childPaths []string
+	for _, child := range mErr.Children() {
+		childPaths = append(childPaths, child.Path())
+	}
+	if want := []string{"$.age", "$.roles"}; !reflect.DeepEqual(childPaths, want) {
+		t.Errorf("Expected children %v, got %v", want, childPaths)
+	}
+
+	leaves := mErr.Leaves()
+	var leafPaths []string
+	for _, leaf := range leaves {
+		leafPaths = append(leafPaths, leaf.Path())
+		if len(leaf.Children()) != 0 {
+			t.Errorf("Expected leaf %s to have no children", leaf.Path())
+		}
+	}
+	if want := []string{"$.age", "$.roles[0]", "$.roles[1]"}; !reflect.DeepEqual(leafPaths, want) {
+		t.Errorf("Expected leaves %v, got %v", want, leafPaths)
+	}
+	if leaves[1].Expected() != "admin" || leaves[1].Actual() != "user" {
+		t.Errorf("Expected admin and user, got %v and %v", leaves[1].Expected(), leaves[1].Actual())
+	}
+
+	want := "at $.age: expected number, got string\n" +
+		"at $.roles[0]: expected admin (string), got user (string)\n" +
+		"at $.roles[1]: expected string, got float64"
+	if err.Error() != want {
+		t.Errorf("Expected message %q, got %q", want, err.Error())
+	}
+}
+
+func TestMatchErrorOwnMismatches(t *testing.T) {
+	err := isMatch(`{"b": 1, "x": 2}`, StrictObject(map[string]any{"a": 1, "b": 2}))
+
+	var mErr *MatchError
+	if !errors.As(err, &mErr) {
+		t.Fatalf("Expected a *MatchError, got %T", err)
+	}
+
+	var got []string
+	for _, leaf := range mErr.Leaves() {
+		got = append(got, leaf.Path()+" "+leaf.Error())
+	}
+	want := []string{
+		"$ at $: unexpected key \"x\"",
+		"$ at $: missing key \"a\"",
+		"$.b at $.b: expected 2 (int), got 1 (float64)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected leaves %q, got %q", want, got)
+	}
+
+	single := isMatch(`{}`, Object(map[string]any{"a": 1}))
+	if !errors.As(single, &mErr) || len(mErr.Children()) != 0 {
+		t.Errorf("Expected a single mismatch without children, got %v", single)
+	}
+}
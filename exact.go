@@ -0,0 +1,102 @@
+package bodyguard
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// diffContext is the number of bytes shown on each side of the first difference.
+const diffContext = 20
+
+// AssertExactJSON checks that body is byte-for-byte identical to expectedRaw, both given as a string or []byte.
+// When canonicalize is true both documents are first rewritten in canonical form (compact, with sorted
+// object keys) so whitespace and key order are ignored. Number spellings still have to match, but strings are
+// compared by value, so escapes such as "\u0041" equal "A".
+// It fails the test with the position of the first difference.
+func AssertExactJSON(t *testing.T, expectedRaw interface{}, body interface{}, canonicalize bool) {
+	t.Helper()
+	if err := isExactMatch(expectedRaw, body, canonicalize); err != nil {
+		t.Error(err)
+	}
+}
+
+func isExactMatch(expectedRaw interface{}, body interface{}, canonicalize bool) error {
+	expectedBytes, err := bodyBytes(expectedRaw)
+	if err != nil {
+		return fmt.Errorf("expected %w", err)
+	}
+	actualBytes, err := bodyBytes(body)
+	if err != nil {
+		return err
+	}
+
+	if canonicalize {
+		if expectedBytes, err = canonicalJSON(expectedBytes); err != nil {
+			return fmt.Errorf("invalid expected json: %w", err)
+		}
+		if actualBytes, err = canonicalJSON(actualBytes); err != nil {
+			return fmt.Errorf("invalid json: %w", err)
+		}
+	}
+
+	return diffBytes(expectedBytes, actualBytes)
+}
+
+// canonicalJSON re-encodes data compactly with sorted object keys, preserving number literals.
+// Strings are re-encoded from their decoded value, so their escapes are normalized.
+func canonicalJSON(data []byte) ([]byte, error) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unexpected data after top-level value")
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// diffBytes reports the line, column and surroundings of the first byte where expected and actual differ.
+func diffBytes(expected, actual []byte) error {
+	if bytes.Equal(expected, actual) {
+		return nil
+	}
+
+	offset := 0
+	for offset < len(expected) && offset < len(actual) && expected[offset] == actual[offset] {
+		offset++
+	}
+
+	line, column := 1, 1
+	for _, b := range expected[:offset] {
+		if b == '\n' {
+			line, column = line+1, 1
+		} else {
+			column++
+		}
+	}
+
+	return fmt.Errorf("json differs at line %d, column %d (offset %d):\n  expected: %q\n  actual:   %q",
+		line, column, offset, snippet(expected, offset), snippet(actual, offset))
+}
+
+func snippet(data []byte, offset int) string {
+	start := max(offset-diffContext, 0)
+	end := min(offset+diffContext, len(data))
+	if start > len(data) {
+		return ""
+	}
+	return string(data[start:end])
+}
@@ -0,0 +1,82 @@
+package bodyguard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsExactMatch(t *testing.T) {
+	tests := map[string]struct {
+		expected     string
+		body         string
+		canonicalize bool
+		wantErr      string
+	}{
+		"Raw Pass": {
+			expected: `{"a": 1, "b": [true, null]}`,
+			body:     `{"a": 1, "b": [true, null]}`,
+			wantErr:  "",
+		},
+		"Raw Whitespace Fail": {
+			expected: `{"a": 1}`,
+			body:     `{"a":1}`,
+			wantErr:  "json differs at line 1, column 6 (offset 5)",
+		},
+		"Raw Line And Column": {
+			expected: "{\n  \"a\": 1,\n  \"b\": 2\n}",
+			body:     "{\n  \"a\": 1,\n  \"b\": 3\n}",
+			wantErr:  "json differs at line 3, column 8",
+		},
+		"Raw Truncated": {
+			expected: `[1, 2]`,
+			body:     `[1, 2`,
+			wantErr:  "offset 5",
+		},
+		"Canonical Pass": {
+			expected:     `{"b": 2, "a": {"y": 1, "x": "<&>"}}`,
+			body:         "{\n  \"a\": {\"x\": \"<&>\", \"y\": 1},\n  \"b\": 2\n}",
+			canonicalize: true,
+			wantErr:      "",
+		},
+		"Canonical Number Spelling Fail": {
+			expected:     `{"price": 1.0}`,
+			body:         `{"price": 1}`,
+			canonicalize: true,
+			wantErr:      "json differs at line 1, column 11 (offset 10)",
+		},
+		"Canonical String Escapes Pass": {
+			expected:     `{"name": "\u0041\/b"}`,
+			body:         `{"name": "A/b"}`,
+			canonicalize: true,
+			wantErr:      "",
+		},
+		"Raw String Escapes Fail": {
+			expected: `{"name": "\u0041"}`,
+			body:     `{"name": "A"}`,
+			wantErr:  "json differs at line 1, column 11 (offset 10)",
+		},
+		"Canonical Invalid Body": {
+			expected:     `{}`,
+			body:         `{`,
+			canonicalize: true,
+			wantErr:      "invalid json",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := isExactMatch(tt.expected, tt.body, tt.canonicalize)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,573 @@
+package bodyguard
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ExportZod renders the expectation as a TypeScript module declaring a zod schema, and the type inferred
+// from it, under the given name, so frontend code can validate the same contract:
+//
+//	import { z } from "zod";
+//
+//	export const User = z.object({
+//	  id: z.string().uuid(),
+//	}).passthrough();
+//
+//	export type User = z.infer<typeof User>;
+//
+// Literals become zod literals, and matchers are rendered from the constraints they check on a value's type,
+// format, pattern, length and range. Matchers that relate several values, such as Capture or Sorted, and
+// custom matchers are rendered as z.any() or as their container type.
+func ExportZod(name string, expected interface{}) string {
+	var b strings.Builder
+	b.WriteString("import { z } from \"zod\";\n\n")
+	fmt.Fprintf(&b, "export const %s = %s;\n\n", name, zodType(schemaOf(expected), 0))
+	fmt.Fprintf(&b, "export type %s = z.infer<typeof %s>;\n", name, name)
+	return b.String()
+}
+
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func zodType(s *schema, depth int) string {
+	if len(s.enum) == 1 {
+		return fmt.Sprintf("z.literal(%s)", jsonLiteral(s.enum[0]))
+	}
+	if len(s.enum) > 1 {
+		literals := make([]string, len(s.enum))
+		for i, v := range s.enum {
+			literals[i] = jsonLiteral(v)
+		}
+		if s.typ == "string" {
+			return fmt.Sprintf("z.enum([%s])", strings.Join(literals, ", "))
+		}
+		for i := range literals {
+			literals[i] = "z.literal(" + literals[i] + ")"
+		}
+		return fmt.Sprintf("z.union([%s])", strings.Join(literals, ", "))
+	}
+
+	if len(s.anyOf) > 0 {
+		var nullable bool
+		var alternatives []string
+		for _, alternative := range s.anyOf {
+			if alternative.typ == "null" && len(alternative.enum) == 0 {
+				nullable = true
+				continue
+			}
+			alternatives = append(alternatives, zodType(alternative, depth))
+		}
+		t := "z.null()"
+		switch len(alternatives) {
+		case 0:
+			nullable = false
+		case 1:
+			t = alternatives[0]
+		default:
+			t = fmt.Sprintf("z.union([%s])", strings.Join(alternatives, ", "))
+		}
+		if nullable {
+			t += ".nullable()"
+		}
+		return t
+	}
+	if len(s.allOf) > 0 {
+		t := zodType(s.allOf[0], depth)
+		for _, part := range s.allOf[1:] {
+			t += fmt.Sprintf(".and(%s)", zodType(part, depth))
+		}
+		return t
+	}
+
+	switch s.typ {
+	case "null":
+		return "z.null()"
+	case "boolean":
+		return "z.boolean()"
+	case "string":
+		t := "z.string()"
+		switch s.format {
+		case "uuid":
+			t += ".uuid()"
+		case "email":
+			t += ".email()"
+		case "uri":
+			t += ".url()"
+		case "date-time":
+			t += ".datetime({ offset: true })"
+		case "date":
+			t += ".date()"
+		}
+		if s.pattern != "" {
+			t += fmt.Sprintf(".regex(/%s/)", strings.ReplaceAll(s.pattern, "/", `\/`))
+		}
+		if s.minLength != nil {
+			t += fmt.Sprintf(".min(%d)", *s.minLength)
+		}
+		if s.maxLength != nil {
+			t += fmt.Sprintf(".max(%d)", *s.maxLength)
+		}
+		return t
+	case "number", "integer":
+		t := "z.number()"
+		if s.typ == "integer" {
+			t += ".int()"
+		}
+		for _, bound := range []struct {
+			method string
+			value  *float64
+		}{{"gte", s.minimum}, {"lte", s.maximum}, {"gt", s.exclusiveMinimum}, {"lt", s.exclusiveMaximum}} {
+			if bound.value != nil {
+				t += fmt.Sprintf(".%s(%s)", bound.method, formatNumber(*bound.value))
+			}
+		}
+		return t
+	case "object":
+		return zodObject(s, depth)
+	case "array":
+		return zodArray(s, depth)
+	}
+	return "z.any()"
+}
+
+func zodObject(s *schema, depth int) string {
+	if len(s.properties) == 0 {
+		if s.additional != nil {
+			return fmt.Sprintf("z.record(z.string(), %s)", zodType(s.additional, depth))
+		}
+		for _, p := range s.patternProperties {
+			return fmt.Sprintf("z.record(z.string(), %s)", zodType(p, depth))
+		}
+		if s.closed {
+			return "z.object({}).strict()"
+		}
+		return "z.record(z.string(), z.any())"
+	}
+
+	indent := strings.Repeat("  ", depth+1)
+	var b strings.Builder
+	b.WriteString("z.object({\n")
+	for _, key := range slices.Sorted(maps.Keys(s.properties)) {
+		name := key
+		if !identifierRegex.MatchString(key) {
+			name = jsonLiteral(key)
+		}
+		t := zodType(s.properties[key], depth+1)
+		if !slices.Contains(s.required, key) {
+			t += ".optional()"
+		}
+		fmt.Fprintf(&b, "%s%s: %s,\n", indent, name, t)
+	}
+	b.WriteString(strings.Repeat("  ", depth) + "})")
+	if s.closed {
+		b.WriteString(".strict()")
+	} else {
+		b.WriteString(".passthrough()")
+	}
+	return b.String()
+}
+
+func zodArray(s *schema, depth int) string {
+	if len(s.prefixItems) > 0 {
+		elements := make([]string, len(s.prefixItems))
+		for i, item := range s.prefixItems {
+			elements[i] = zodType(item, depth)
+		}
+		t := fmt.Sprintf("z.tuple([%s])", strings.Join(elements, ", "))
+		if s.maxItems == nil || *s.maxItems != len(s.prefixItems) {
+			t += ".rest(z.any())"
+		}
+		return t
+	}
+
+	items := "z.any()"
+	if s.items != nil {
+		items = zodType(s.items, depth)
+	}
+	t := fmt.Sprintf("z.array(%s)", items)
+	if s.minItems != nil {
+		t += fmt.Sprintf(".min(%d)", *s.minItems)
+	}
+	if s.maxItems != nil {
+		t += fmt.Sprintf(".max(%d)", *s.maxItems)
+	}
+	return t
+}
+
+// ExportPydantic renders the expectation as a Python module declaring pydantic (v2) models under the given
+// name, so backend code can validate the same contract. Nested objects become models named after their
+// parent and key, e.g. UserAddress. Matchers are rendered as in ExportZod, with Any for values that
+// cannot be described.
+func ExportPydantic(name string, expected interface{}) string {
+	p := &pydanticRenderer{}
+	root := schemaOf(expected)
+	if root.typ == "object" && len(root.properties) > 0 && len(root.enum) == 0 {
+		p.model(name, root)
+	} else {
+		p.models = append(p.models, fmt.Sprintf("class %s(RootModel[%s]):\n    pass\n", name, p.typ(name, root)))
+	}
+
+	var b strings.Builder
+	b.WriteString(`from __future__ import annotations
+
+from datetime import date, datetime
+from typing import Annotated, Any, Dict, List, Literal, Optional, Tuple, Union
+from uuid import UUID
+
+from pydantic import BaseModel, ConfigDict, Field, RootModel
+`)
+	for _, model := range p.models {
+		b.WriteString("\n\n" + model)
+	}
+	return b.String()
+}
+
+// pydanticRenderer collects the models of an exported expectation, nested models first.
+type pydanticRenderer struct {
+	models []string
+}
+
+var pythonKeywords = []string{
+	"False", "None", "True", "and", "as", "assert", "async", "await", "break", "class", "continue", "def",
+	"del", "elif", "else", "except", "finally", "for", "from", "global", "if", "import", "in", "is", "lambda",
+	"nonlocal", "not", "or", "pass", "raise", "return", "try", "while", "with", "yield",
+}
+
+var nonIdentifierRegex = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+func (p *pydanticRenderer) model(name string, s *schema) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "class %s(BaseModel):\n", name)
+	if s.closed {
+		b.WriteString("    model_config = ConfigDict(extra=\"forbid\", populate_by_name=True)\n")
+	} else {
+		b.WriteString("    model_config = ConfigDict(extra=\"allow\", populate_by_name=True)\n")
+	}
+	if len(s.properties) > 0 {
+		b.WriteString("\n")
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(s.properties)) {
+		field := nonIdentifierRegex.ReplaceAllString(key, "_")
+		if field == "" || (field[0] >= '0' && field[0] <= '9') || slices.Contains(pythonKeywords, field) || strings.HasPrefix(field, "model_") {
+			field = "field_" + field
+		}
+
+		t := p.typ(name+pascalCase(key), s.properties[key])
+		var args []string
+		if !slices.Contains(s.required, key) {
+			t = fmt.Sprintf("Optional[%s]", t)
+			args = append(args, "default=None")
+		}
+		if field != key {
+			args = append(args, fmt.Sprintf("alias=%s", jsonLiteral(key)))
+		}
+
+		switch {
+		case len(args) == 1 && args[0] == "default=None":
+			fmt.Fprintf(&b, "    %s: %s = None\n", field, t)
+		case len(args) > 0:
+			fmt.Fprintf(&b, "    %s: %s = Field(%s)\n", field, t, strings.Join(args, ", "))
+		default:
+			fmt.Fprintf(&b, "    %s: %s\n", field, t)
+		}
+	}
+	p.models = append(p.models, b.String())
+}
+
+// typ returns the Python type of the schema, declaring the models of nested objects under name.
+func (p *pydanticRenderer) typ(name string, s *schema) string {
+	if len(s.enum) > 0 {
+		literals := make([]string, len(s.enum))
+		for i, v := range s.enum {
+			literals[i] = pythonLiteral(v)
+		}
+		return fmt.Sprintf("Literal[%s]", strings.Join(literals, ", "))
+	}
+
+	if len(s.anyOf) > 0 {
+		var nullable bool
+		var nonNull []*schema
+		for _, alternative := range s.anyOf {
+			if alternative.typ == "null" && len(alternative.enum) == 0 {
+				nullable = true
+				continue
+			}
+			nonNull = append(nonNull, alternative)
+		}
+		alternatives := make([]string, len(nonNull))
+		for i, alternative := range nonNull {
+			if len(nonNull) == 1 {
+				alternatives[i] = p.typ(name, alternative)
+			} else {
+				alternatives[i] = p.typ(fmt.Sprintf("%s%d", name, i+1), alternative)
+			}
+		}
+		t := "None"
+		switch len(alternatives) {
+		case 0:
+			nullable = false
+		case 1:
+			t = alternatives[0]
+		default:
+			t = fmt.Sprintf("Union[%s]", strings.Join(alternatives, ", "))
+		}
+		if nullable {
+			t = fmt.Sprintf("Optional[%s]", t)
+		}
+		return t
+	}
+	if len(s.allOf) > 0 {
+		// Python types cannot express intersections; the first constraint is the most general one
+		return p.typ(name, s.allOf[0])
+	}
+
+	var constraints []string
+	t := "Any"
+	switch s.typ {
+	case "null":
+		return "None"
+	case "boolean":
+		return "bool"
+	case "string":
+		t = "str"
+		switch s.format {
+		case "uuid":
+			t = "UUID"
+		case "date-time":
+			t = "datetime"
+		case "date":
+			t = "date"
+		}
+		if s.pattern != "" {
+			constraints = append(constraints, fmt.Sprintf("pattern=%s", pythonRawString(s.pattern)))
+		}
+		if s.minLength != nil {
+			constraints = append(constraints, fmt.Sprintf("min_length=%d", *s.minLength))
+		}
+		if s.maxLength != nil {
+			constraints = append(constraints, fmt.Sprintf("max_length=%d", *s.maxLength))
+		}
+	case "number", "integer":
+		t = "float"
+		if s.typ == "integer" {
+			t = "int"
+		}
+		for _, bound := range []struct {
+			arg   string
+			value *float64
+		}{{"ge", s.minimum}, {"le", s.maximum}, {"gt", s.exclusiveMinimum}, {"lt", s.exclusiveMaximum}} {
+			if bound.value != nil {
+				constraints = append(constraints, fmt.Sprintf("%s=%s", bound.arg, formatNumber(*bound.value)))
+			}
+		}
+	case "object":
+		switch {
+		case len(s.properties) > 0:
+			p.model(name, s)
+			return name
+		case s.additional != nil:
+			return fmt.Sprintf("Dict[str, %s]", p.typ(name+"Value", s.additional))
+		}
+		for _, v := range s.patternProperties {
+			return fmt.Sprintf("Dict[str, %s]", p.typ(name+"Value", v))
+		}
+		return "Dict[str, Any]"
+	case "array":
+		if len(s.prefixItems) > 0 && s.maxItems != nil && *s.maxItems == len(s.prefixItems) {
+			elements := make([]string, len(s.prefixItems))
+			for i, item := range s.prefixItems {
+				elements[i] = p.typ(fmt.Sprintf("%sItem%d", name, i+1), item)
+			}
+			return fmt.Sprintf("Tuple[%s]", strings.Join(elements, ", "))
+		}
+		items := "Any"
+		if s.items != nil {
+			items = p.typ(name+"Item", s.items)
+		}
+		t = fmt.Sprintf("List[%s]", items)
+		if s.minItems != nil {
+			constraints = append(constraints, fmt.Sprintf("min_length=%d", *s.minItems))
+		}
+		if s.maxItems != nil {
+			constraints = append(constraints, fmt.Sprintf("max_length=%d", *s.maxItems))
+		}
+	}
+
+	if len(constraints) > 0 {
+		return fmt.Sprintf("Annotated[%s, Field(%s)]", t, strings.Join(constraints, ", "))
+	}
+	return t
+}
+
+// jsonSchemaDialect is the JSON Schema version ToJSONSchema produces.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// ToJSONSchema converts the expectation into an equivalent JSON Schema (draft 2020-12) document, so the
+// contracts tests encode can be published. Literals become const or enum keywords, and matchers are
+// converted from the constraints they check, as in ExportZod; those that cannot be described accept any
+// value. It fails for an expectation with construction mistakes, as reported by ValidateExpected.
+func ToJSONSchema(expected interface{}) ([]byte, error) {
+	if err := ValidateExpected(expected); err != nil {
+		return nil, err
+	}
+	document := jsonSchema(schemaOf(expected))
+	document["$schema"] = jsonSchemaDialect
+	return json.MarshalIndent(document, "", "  ")
+}
+
+// jsonSchema returns the JSON Schema keywords of s.
+func jsonSchema(s *schema) map[string]interface{} {
+	doc := map[string]interface{}{}
+	set := func(keyword string, v interface{}) {
+		switch v := v.(type) {
+		case string:
+			if v == "" {
+				return
+			}
+		case *float64:
+			if v == nil {
+				return
+			}
+		case *int:
+			if v == nil {
+				return
+			}
+		case *schema:
+			if v == nil {
+				return
+			}
+			doc[keyword] = jsonSchema(v)
+			return
+		}
+		doc[keyword] = v
+	}
+
+	set("description", s.description)
+	set("type", s.typ)
+	set("format", s.format)
+	set("pattern", s.pattern)
+	switch len(s.enum) {
+	case 0:
+	case 1:
+		doc["const"] = s.enum[0]
+	default:
+		doc["enum"] = s.enum
+	}
+	set("minimum", s.minimum)
+	set("maximum", s.maximum)
+	set("exclusiveMinimum", s.exclusiveMinimum)
+	set("exclusiveMaximum", s.exclusiveMaximum)
+	set("minLength", s.minLength)
+	set("maxLength", s.maxLength)
+
+	if len(s.properties) > 0 {
+		properties := map[string]interface{}{}
+		for key, property := range s.properties {
+			properties[key] = jsonSchema(property)
+		}
+		doc["properties"] = properties
+	}
+	if len(s.required) > 0 {
+		doc["required"] = s.required
+	}
+	if s.closed && s.additional == nil {
+		doc["additionalProperties"] = false
+	}
+	set("additionalProperties", s.additional)
+	set("propertyNames", s.propertyNames)
+	if len(s.patternProperties) > 0 {
+		patternProperties := map[string]interface{}{}
+		for pattern, property := range s.patternProperties {
+			patternProperties[pattern] = jsonSchema(property)
+		}
+		doc["patternProperties"] = patternProperties
+	}
+	set("minProperties", s.minProperties)
+	set("maxProperties", s.maxProperties)
+
+	var allOf []interface{}
+	if len(s.prefixItems) > 0 {
+		doc["prefixItems"] = jsonSchemas(s.prefixItems)
+		if s.items != nil {
+			// items only applies after prefixItems, so the schema of every element moves to allOf
+			allOf = append(allOf, map[string]interface{}{"items": jsonSchema(s.items)})
+		}
+	} else {
+		set("items", s.items)
+	}
+	set("minItems", s.minItems)
+	set("maxItems", s.maxItems)
+	if s.uniqueItems {
+		doc["uniqueItems"] = true
+	}
+
+	if len(s.anyOf) > 0 {
+		doc["anyOf"] = jsonSchemas(s.anyOf)
+	}
+	allOf = append(allOf, jsonSchemas(s.allOf)...)
+	if len(allOf) > 0 {
+		doc["allOf"] = allOf
+	}
+	set("not", s.not)
+	return doc
+}
+
+// jsonSchemas returns the JSON Schema of every schema.
+func jsonSchemas(schemas []*schema) []interface{} {
+	docs := make([]interface{}, len(schemas))
+	for i, s := range schemas {
+		docs[i] = jsonSchema(s)
+	}
+	return docs
+}
+
+func jsonLiteral(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func pythonLiteral(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if t {
+			return "True"
+		}
+		return "False"
+	}
+	return jsonLiteral(v)
+}
+
+// pythonRawString quotes a regular expression as a Python raw string where possible.
+func pythonRawString(s string) string {
+	if !strings.Contains(s, `"`) && !strings.HasSuffix(s, `\`) {
+		return `r"` + s + `"`
+	}
+	return jsonLiteral(s)
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// pascalCase turns an object key such as "billing_address" into "BillingAddress".
+func pascalCase(key string) string {
+	var b strings.Builder
+	for _, word := range nonIdentifierRegex.Split(strings.ReplaceAll(key, "_", " "), -1) {
+		if word == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,155 @@
+package bodyguard
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var exportedUser = StrictObject(map[string]any{
+	"id":           UUID(),
+	"name":         StringLength(1, 50),
+	"age":          Optional(Integer()),
+	"score":        NumberWithinRange(0, 1),
+	"status":       OneOf("active", "pending"),
+	"created_at":   Timestamp(),
+	"tags":         Each(String()),
+	"kind":         "user",
+	"manager":      Any(nil, Object(map[string]any{"id": UUID()})),
+	"password":     Absent(),
+	"content-type": Regexp(`^a/b$`),
+	"pair":         Array(1, "x"),
+})
+
+func TestExportZod(t *testing.T) {
+	want := `import { z } from "zod";
+
+export const User = z.object({
+  age: z.number().int().optional(),
+  "content-type": z.string().regex(/^a\/b$/),
+  created_at: z.string().datetime({ offset: true }),
+  id: z.string().uuid(),
+  kind: z.literal("user"),
+  manager: z.object({
+    id: z.string().uuid(),
+  }).passthrough().nullable(),
+  name: z.string().min(1).max(50),
+  pair: z.tuple([z.literal(1), z.literal("x")]),
+  score: z.number().gte(0).lte(1),
+  status: z.enum(["active", "pending"]),
+  tags: z.array(z.string()),
+}).strict();
+
+export type User = z.infer<typeof User>;
+`
+	if got := ExportZod("User", exportedUser); got != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, got)
+	}
+
+	if got, want := zodType(schemaOf(Any(Capture("id"), MapOf(String(), Number()))), 0), "z.union([z.any(), z.record(z.string(), z.number())])"; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestExportPydantic(t *testing.T) {
+	want := `from __future__ import annotations
+
+from datetime import date, datetime
+from typing import Annotated, Any, Dict, List, Literal, Optional, Tuple, Union
+from uuid import UUID
+
+from pydantic import BaseModel, ConfigDict, Field, RootModel
+
+
+class UserManager(BaseModel):
+    model_config = ConfigDict(extra="allow", populate_by_name=True)
+
+    id: UUID
+
+
+class User(BaseModel):
+    model_config = ConfigDict(extra="forbid", populate_by_name=True)
+
+    age: Optional[int] = None
+    content_type: Annotated[str, Field(pattern=r"^a/b$")] = Field(alias="content-type")
+    created_at: datetime
+    id: UUID
+    kind: Literal["user"]
+    manager: Optional[UserManager]
+    name: Annotated[str, Field(min_length=1, max_length=50)]
+    pair: Tuple[Literal[1], Literal["x"]]
+    score: Annotated[float, Field(ge=0, le=1)]
+    status: Literal["active", "pending"]
+    tags: List[str]
+`
+	if got := ExportPydantic("User", exportedUser); got != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, got)
+	}
+
+	if got, want := ExportPydantic("Ids", All(ArrayLength(1, 10), Each(UUID()))), "class Ids(RootModel[Annotated[List[UUID], Field(min_length=1, max_length=10)]]):\n    pass\n"; !strings.HasSuffix(got, want) {
+		t.Errorf("Expected a root model %q, got %q", want, got)
+	}
+}
+
+func TestToJSONSchema(t *testing.T) {
+	tests := map[string]struct {
+		expected interface{}
+		want     string
+		wantErr  string
+	}{
+		"Object": {
+			expected: exportedUser,
+			want: `{"$schema":"https://json-schema.org/draft/2020-12/schema","additionalProperties":false,"properties":{` +
+				`"age":{"type":"integer"},"content-type":{"pattern":"^a/b$","type":"string"},` +
+				`"created_at":{"format":"date-time","type":"string"},"id":{"format":"uuid","type":"string"},` +
+				`"kind":{"const":"user","type":"string"},` +
+				`"manager":{"anyOf":[{"type":"null"},{"properties":{"id":{"format":"uuid","type":"string"}},"required":["id"],"type":"object"}]},` +
+				`"name":{"maxLength":50,"minLength":1,"type":"string"},` +
+				`"pair":{"maxItems":2,"minItems":2,"prefixItems":[{"const":1,"type":"integer"},{"const":"x","type":"string"}],"type":"array"},` +
+				`"score":{"maximum":1,"minimum":0,"type":"number"},"status":{"enum":["active","pending"],"type":"string"},` +
+				`"tags":{"items":{"type":"string"},"type":"array"}},` +
+				`"required":["content-type","created_at","id","kind","manager","name","pair","score","status","tags"],"type":"object"}`,
+		},
+		"Map And Unique Array": {
+			expected: All(Unique(), Each(MapOf(Regexp(`^[a-z]+$`), NumberGreater(0)))),
+			want: `{"$schema":"https://json-schema.org/draft/2020-12/schema","items":{"additionalProperties":{"exclusiveMinimum":0,"type":"number"},` +
+				`"propertyNames":{"pattern":"^[a-z]+$","type":"string"},"type":"object"},"type":"array","uniqueItems":true}`,
+		},
+		"Prefix And Every Element": {
+			expected: All(ArrayPrefix("head"), Each(String())),
+			want: `{"$schema":"https://json-schema.org/draft/2020-12/schema","allOf":[{"items":{"type":"string"}}],` +
+				`"minItems":1,"prefixItems":[{"const":"head","type":"string"}],"type":"array"}`,
+		},
+		"Not Null": {
+			expected: NotNull(),
+			want:     `{"$schema":"https://json-schema.org/draft/2020-12/schema","not":{"type":"null"}}`,
+		},
+		"Invalid Expectation": {
+			expected: Object(map[string]any{"name": Regexp(`[a-z`)}),
+			wantErr:  "at $.name: invalid pattern",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ToJSONSchema(tt.expected)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, got); err != nil {
+				t.Fatal(err)
+			}
+			if compact.String() != tt.want {
+				t.Errorf("Expected\n%s\ngot\n%s", tt.want, compact.String())
+			}
+		})
+	}
+}
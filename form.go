@@ -0,0 +1,42 @@
+package bodyguard
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+// AssertForm checks that an application/x-www-form-urlencoded body (as a string, []byte or url.Values),
+// such as a webhook payload, matches expected. The form is matched as an object of strings, using arrays
+// of strings for fields sent several times, so Object, Array and string matchers apply as for JSON:
+//
+//	bodyguard.AssertForm(t, bodyguard.Object(map[string]any{"event": "charge", "amount": bodyguard.StringAsInt()}), body)
+//
+// Options adjust the behaviour of this assertion only. It fails the test if there is a mismatch.
+func AssertForm(t *testing.T, expected interface{}, body interface{}, opts ...Option) {
+	t.Helper()
+	defaultEngine.AssertForm(t, expected, body, opts...)
+}
+
+// AssertForm checks that a form body matches expected. See the package-level AssertForm.
+func (e *Engine) AssertForm(t *testing.T, expected interface{}, body interface{}, opts ...Option) {
+	t.Helper()
+	e = e.with(opts)
+	e.report(t, "AssertForm", expected, e.isFormMatch(body, expected))
+}
+
+func (e *Engine) isFormMatch(body interface{}, expected interface{}) error {
+	if values, ok := body.(url.Values); ok {
+		body = values.Encode()
+	}
+	data, err := bodyBytes(body)
+	if err != nil {
+		return err
+	}
+
+	form, err := decodeForm(data)
+	if err != nil {
+		return fmt.Errorf("invalid form body: %w", err)
+	}
+	return e.matchValue(expected, form)
+}
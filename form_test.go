@@ -0,0 +1,73 @@
+package bodyguard
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestIsFormMatch(t *testing.T) {
+	tests := map[string]struct {
+		body     interface{}
+		expected interface{}
+		wantErr  string
+	}{
+		"Pass": {
+			body:     `event=charge.succeeded&amount=1200&currency=usd`,
+			expected: Object(map[string]any{"event": "charge.succeeded", "amount": StringAsInt(Positive())}),
+			wantErr:  "",
+		},
+		"Repeated Keys Pass": {
+			body:     []byte(`tag=a&tag=b&id=1`),
+			expected: StrictObject(map[string]any{"id": "1", "tag": UnorderedArray("b", "a")}),
+			wantErr:  "",
+		},
+		"URL Values Pass": {
+			body:     url.Values{"name": {"j doe"}},
+			expected: Object(map[string]any{"name": "j doe"}),
+			wantErr:  "",
+		},
+		"Escaped Values Pass": {
+			body:     `name=j+doe&email=jdoe%40example.com`,
+			expected: Object(map[string]any{"name": "j doe", "email": Email()}),
+			wantErr:  "",
+		},
+		"Value Mismatch": {
+			body:     `amount=twelve`,
+			expected: Object(map[string]any{"amount": StringAsInt()}),
+			wantErr:  "at $.amount: expected integer string, got \"twelve\"",
+		},
+		"Missing Field": {
+			body:     `event=charge`,
+			expected: Object(map[string]any{"signature": String()}),
+			wantErr:  "at $: missing key \"signature\"",
+		},
+		"Invalid Form": {
+			body:     `amount=%zz`,
+			expected: Anything(),
+			wantErr:  "invalid form body: invalid URL escape \"%zz\"",
+		},
+		"Invalid Body Type": {
+			body:     42,
+			expected: Anything(),
+			wantErr:  "body must be string, []byte or io.Reader, got int",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := defaultEngine.isFormMatch(tt.body, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
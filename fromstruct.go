@@ -0,0 +1,140 @@
+package bodyguard
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// FromStruct returns an expectation built from the JSON encoding of v, typically a response DTO, so large
+// payloads need no hand-written Object literals. Objects must match exactly, as with StrictObject, and
+// arrays in order.
+//
+// overrides replaces the values of dynamic fields, keyed by path in the syntax of AssertFlat with the
+// leading "$" optional, e.g.
+//
+//	bodyguard.FromStruct(user, map[string]any{"id": bodyguard.UUID(), "orders[*].created_at": bodyguard.Timestamp()})
+//
+// A path may name a key missing from the encoding, such as a field dropped by omitempty. When v cannot be
+// encoded or an override path selects nothing, the expectation fails every match and ValidateExpected
+// reports why.
+func FromStruct(v interface{}, overrides map[string]any) Matcher {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return invalidMatcher{fmt.Errorf("cannot encode %T: %w", v, err)}
+	}
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return invalidMatcher{fmt.Errorf("cannot decode %T: %w", v, err)}
+	}
+	return withOverrides(document, overrides)
+}
+
+// withOverrides returns the expectation of a decoded document whose values at the paths of overrides are
+// replaced, as described for FromStruct.
+func withOverrides(document interface{}, overrides map[string]any) Matcher {
+	for _, key := range slices.Sorted(maps.Keys(overrides)) {
+		path := key
+		switch {
+		case strings.HasPrefix(key, "["):
+			path = "$" + key
+		case !strings.HasPrefix(key, "$"):
+			path = "$." + key
+		}
+		segments, err := parseJSONPath(path)
+		if err != nil {
+			return invalidMatcher{err}
+		}
+		if document, err = override(document, "$", segments, overrides[key]); err != nil {
+			return invalidMatcher{fmt.Errorf("override %q: %w", key, err)}
+		}
+	}
+
+	expected := literalExpectation(document)
+	if m, ok := expected.(Matcher); ok {
+		return m
+	}
+	return All(expected)
+}
+
+// override replaces the values selected by segments in a decoded document. The last segment may name a
+// missing object key, which is added.
+func override(doc interface{}, path string, segments []pathSegment, value interface{}) (interface{}, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	seg, rest := segments[0], segments[1:]
+	switch {
+	case seg.wildcard:
+		switch t := doc.(type) {
+		case map[string]interface{}:
+			for key, child := range t {
+				updated, err := override(child, path+"."+key, rest, value)
+				if err != nil {
+					return nil, err
+				}
+				t[key] = updated
+			}
+			return t, nil
+		case []interface{}:
+			for i, child := range t {
+				updated, err := override(child, fmt.Sprintf("%s[%d]", path, i), rest, value)
+				if err != nil {
+					return nil, err
+				}
+				t[i] = updated
+			}
+			return t, nil
+		}
+		return nil, fmt.Errorf("no object or array at %s", path)
+	case seg.isIndex:
+		arr, ok := doc.([]interface{})
+		if !ok || seg.index >= len(arr) {
+			return nil, fmt.Errorf("no value at %s[%d]", path, seg.index)
+		}
+		updated, err := override(arr[seg.index], fmt.Sprintf("%s[%d]", path, seg.index), rest, value)
+		if err != nil {
+			return nil, err
+		}
+		arr[seg.index] = updated
+		return arr, nil
+	default:
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("no object at %s", path)
+		}
+		child, ok := obj[seg.key]
+		if !ok && len(rest) > 0 {
+			return nil, fmt.Errorf("no value at %s.%s", path, seg.key)
+		}
+		updated, err := override(child, path+"."+seg.key, rest, value)
+		if err != nil {
+			return nil, err
+		}
+		obj[seg.key] = updated
+		return obj, nil
+	}
+}
+
+// literalExpectation converts the objects and arrays of a decoded document to StrictObject and Array
+// matchers, so matchers placed inside them are applied.
+func literalExpectation(doc interface{}) interface{} {
+	switch t := doc.(type) {
+	case map[string]interface{}:
+		expected := make(map[string]any, len(t))
+		for key, child := range t {
+			expected[key] = literalExpectation(child)
+		}
+		return StrictObject(expected)
+	case []interface{}:
+		elements := make([]interface{}, len(t))
+		for i, child := range t {
+			elements[i] = literalExpectation(child)
+		}
+		return Array(elements...)
+	}
+	return doc
+}
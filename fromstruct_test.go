@@ -0,0 +1,132 @@
+package bodyguard
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type orderDTO struct {
+	ID        string    `json:"id"`
+	Total     float64   `json:"total"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type userDTO struct {
+	ID     string     `json:"id"`
+	Name   string     `json:"name"`
+	Email  string     `json:"email,omitempty"`
+	Tags   []string   `json:"tags"`
+	Orders []orderDTO `json:"orders"`
+}
+
+func TestFromStruct(t *testing.T) {
+	user := userDTO{
+		Name:   "jdoe",
+		Tags:   []string{"admin", "beta"},
+		Orders: []orderDTO{{Total: 9.5}, {Total: 20}},
+	}
+	dynamic := map[string]any{
+		"id":                   UUID(),
+		"orders[*].id":         UUID(),
+		"orders[*].created_at": Timestamp(),
+	}
+	body := `{
+		"id": "1b4e28ba-2fa1-11d2-883f-0016d3cca427",
+		"name": "jdoe",
+		"tags": ["admin", "beta"],
+		"orders": [
+			{"id": "6fa459ea-ee8a-3ca4-894e-db77e160355e", "total": 9.5, "created_at": "2024-01-02T15:04:05Z"},
+			{"id": "886313e1-3b8a-5372-9b90-0c9aee199e5d", "total": 20, "created_at": "2024-01-03T15:04:05Z"}
+		]
+	}`
+
+	tests := map[string]struct {
+		body      string
+		v         interface{}
+		overrides map[string]any
+		wantErr   string
+	}{
+		"Pass": {
+			body:      body,
+			v:         user,
+			overrides: dynamic,
+			wantErr:   "",
+		},
+		"Literal Mismatch": {
+			body:      strings.Replace(body, `"total": 20`, `"total": 25`, 1),
+			v:         user,
+			overrides: dynamic,
+			wantErr:   "at $.orders[1](id=886313e1-3b8a-5372-9b90-0c9aee199e5d).total: expected 20 (float64), got 25 (float64)",
+		},
+		"Override Mismatch": {
+			body:      strings.Replace(body, `"2024-01-03T15:04:05Z"`, `"yesterday"`, 1),
+			v:         user,
+			overrides: dynamic,
+			wantErr:   "at $.orders[1](id=886313e1-3b8a-5372-9b90-0c9aee199e5d).created_at: parsing time \"yesterday\"",
+		},
+		"Extra Key": {
+			body:      strings.Replace(body, `"name": "jdoe",`, `"name": "jdoe", "role": "admin",`, 1),
+			v:         user,
+			overrides: dynamic,
+			wantErr:   "at $: unexpected key \"role\"",
+		},
+		"Override Adds Omitted Key": {
+			body:      strings.Replace(body, `"name": "jdoe",`, `"name": "jdoe", "email": "jdoe@example.com",`, 1),
+			v:         user,
+			overrides: map[string]any{"id": UUID(), "email": Email(), "$.orders": Each(Anything())},
+			wantErr:   "",
+		},
+		"Top-level Array": {
+			body:      `["admin", "beta"]`,
+			v:         user.Tags,
+			overrides: map[string]any{"[1]": Regexp(`^b`)},
+			wantErr:   "",
+		},
+		"Top-level Scalar": {
+			body:    `"jdoe"`,
+			v:       "jdoe",
+			wantErr: "",
+		},
+		"Missing Override Path": {
+			body:      body,
+			v:         user,
+			overrides: map[string]any{"profile.avatar": URL()},
+			wantErr:   "at $: override \"profile.avatar\": no value at $.profile",
+		},
+		"Index Out Of Range": {
+			body:      body,
+			v:         user,
+			overrides: map[string]any{"orders[2].id": UUID()},
+			wantErr:   "at $: override \"orders[2].id\": no value at $.orders[2]",
+		},
+		"Invalid Override Path": {
+			body:      body,
+			v:         user,
+			overrides: map[string]any{"orders[x]": UUID()},
+			wantErr:   "at $: invalid path \"$.orders[x]\"",
+		},
+		"Unencodable Value": {
+			body:    `{}`,
+			v:       map[string]any{"f": func() {}},
+			wantErr: "at $: cannot encode map[string]interface {}: json: unsupported type: func()",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := isMatch(tt.body, FromStruct(tt.v, tt.overrides))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
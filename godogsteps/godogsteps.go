@@ -0,0 +1,157 @@
+// Package godogsteps provides godog step definitions asserting HTTP responses with bodyguard, so BDD suites
+// share the matcher engine of the Go tests without writing glue:
+//
+//	func InitializeScenario(sc *godog.ScenarioContext) {
+//		steps := godogsteps.New(server.URL)
+//		steps.Vars["uuid"] = bodyguard.UUID()
+//		steps.Register(sc)
+//	}
+//
+// The registered steps are:
+//
+//	When I send a GET request to "/users/${id}"
+//	When I send a POST request to "/users" with body:
+//	  """
+//	  {"name": "jdoe"}
+//	  """
+//	Then the response status should be 201
+//	Then the response body should match:
+//	  """
+//	  {"id": "${uuid}", "name": "jdoe"}
+//	  """
+//	Then the response body should match the file "testdata/user.json"
+//
+// Expected bodies are bodyguard templates: objects must match exactly and arrays in order, and each ${name}
+// placeholder is replaced by its value in Vars, which may be a literal or a matcher. A placeholder without a
+// value captures the actual value, for use by later steps of the scenario, including in request paths.
+package godogsteps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/dedalusj/bodyguard"
+)
+
+// placeholderRegex matches a ${name} placeholder in a request path.
+var placeholderRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_.-]*)\}`)
+
+// Steps holds the state of one scenario: its variables and the last response received.
+// Create one Steps per scenario, in the godog ScenarioInitializer.
+type Steps struct {
+	// BaseURL is prefixed to the paths of requests sent by the steps, e.g. an httptest.Server URL.
+	BaseURL string
+	// Client sends requests. When nil, http.DefaultClient is used.
+	Client *http.Client
+	// Engine matches bodies. When nil, the default bodyguard configuration is used.
+	Engine *bodyguard.Engine
+	// Vars holds the values of template placeholders and the values captured by earlier steps.
+	Vars bodyguard.Vars
+
+	status int
+	body   []byte
+}
+
+// New returns the steps for one scenario, sending requests to baseURL.
+func New(baseURL string) *Steps {
+	return &Steps{BaseURL: baseURL, Vars: bodyguard.Vars{}}
+}
+
+// Register adds the steps to the scenario.
+func (s *Steps) Register(sc *godog.ScenarioContext) {
+	sc.Step(`^I send an? (GET|HEAD|DELETE|OPTIONS) request to "([^"]*)"$`, s.sendRequest)
+	sc.Step(`^I send an? (POST|PUT|PATCH|DELETE) request to "([^"]*)" with body:$`, s.sendRequestWithBody)
+	sc.Step(`^the response status should be (\d+)$`, s.statusShouldBe)
+	sc.Step(`^the response body should match:$`, s.bodyShouldMatch)
+	sc.Step(`^the response body should match the file "([^"]*)"$`, s.bodyShouldMatchFile)
+}
+
+// SetResponse records resp as the last response, for steps of the application that send requests
+// themselves. It reads and closes the body.
+func (s *Steps) SetResponse(resp *http.Response) error {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("cannot read response body: %w", err)
+	}
+	s.status, s.body = resp.StatusCode, body
+	return nil
+}
+
+func (s *Steps) sendRequest(ctx context.Context, method, path string) error {
+	return s.send(ctx, method, path, nil)
+}
+
+func (s *Steps) sendRequestWithBody(ctx context.Context, method, path string, body *godog.DocString) error {
+	return s.send(ctx, method, path, []byte(body.Content))
+}
+
+func (s *Steps) send(ctx context.Context, method, path string, body []byte) error {
+	url := s.BaseURL + placeholderRegex.ReplaceAllStringFunc(path, func(placeholder string) string {
+		name := placeholderRegex.FindStringSubmatch(placeholder)[1]
+		if value, ok := s.Vars[name]; ok {
+			return fmt.Sprint(value)
+		}
+		return placeholder
+	})
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	return s.SetResponse(resp)
+}
+
+func (s *Steps) statusShouldBe(status int) error {
+	if s.status != status {
+		return fmt.Errorf("expected status %d, got %d with body %q", status, s.status, s.body)
+	}
+	return nil
+}
+
+func (s *Steps) bodyShouldMatch(expected *godog.DocString) error {
+	return s.match(strings.TrimSpace(expected.Content))
+}
+
+func (s *Steps) bodyShouldMatchFile(filename string) error {
+	expected, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return s.match(string(expected))
+}
+
+// match checks the last response body against a template, capturing its unset placeholders into Vars.
+func (s *Steps) match(template string) error {
+	if s.body == nil {
+		return fmt.Errorf("no response received yet")
+	}
+	engine := s.Engine
+	if engine == nil {
+		engine = &bodyguard.Engine{}
+	}
+	if s.Vars == nil {
+		s.Vars = bodyguard.Vars{}
+	}
+	return engine.Evaluate(bodyguard.Template(template, s.Vars), s.body).Err
+}
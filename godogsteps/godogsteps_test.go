@@ -0,0 +1,137 @@
+package godogsteps
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cucumber/godog"
+	"github.com/dedalusj/bodyguard"
+)
+
+func newServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /users", func(w http.ResponseWriter, r *http.Request) {
+		var user map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&user)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "3f2504e0-4f89-11d3-9a0c-0305e82c3301", "name": user["name"]})
+	})
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": r.PathValue("id"), "name": "jdoe", "roles": []string{"admin"}})
+	})
+	return httptest.NewServer(mux)
+}
+
+func runFeature(t *testing.T, server *httptest.Server, feature string) int {
+	t.Helper()
+	return godog.TestSuite{
+		ScenarioInitializer: func(sc *godog.ScenarioContext) {
+			steps := New(server.URL)
+			steps.Vars["uuid"] = bodyguard.UUID()
+			steps.Register(sc)
+		},
+		Options: &godog.Options{
+			Format:          "progress",
+			Output:          &strings.Builder{},
+			FeatureContents: []godog.Feature{{Name: "users.feature", Contents: []byte(feature)}},
+			Strict:          true,
+		},
+	}.Run()
+}
+
+func TestSteps(t *testing.T) {
+	server := newServer()
+	defer server.Close()
+
+	golden := filepath.Join(t.TempDir(), "user.json")
+	if err := os.WriteFile(golden, []byte(`{"id": "${id}", "name": "jdoe", "roles": ["admin"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		scenario   string
+		wantStatus int
+	}{
+		"Pass": {
+			scenario: `
+    When I send a POST request to "/users" with body:
+      """
+      {"name": "jdoe"}
+      """
+    Then the response status should be 201
+    And the response body should match:
+      """
+      {"id": "${id}", "name": "jdoe"}
+      """
+    When I send a GET request to "/users/${id}"
+    Then the response status should be 200
+    And the response body should match the file "` + golden + `"`,
+			wantStatus: 0,
+		},
+		"Matcher Placeholder Pass": {
+			scenario: `
+    When I send a POST request to "/users" with body:
+      """
+      {"name": "jdoe"}
+      """
+    Then the response body should match:
+      """
+      {"id": "${uuid}", "name": "jdoe"}
+      """`,
+			wantStatus: 0,
+		},
+		"Status Mismatch": {
+			scenario: `
+    When I send a GET request to "/users/1"
+    Then the response status should be 404`,
+			wantStatus: 1,
+		},
+		"Body Mismatch": {
+			scenario: `
+    When I send a GET request to "/users/1"
+    Then the response body should match:
+      """
+      {"id": "1", "name": "other", "roles": ["admin"]}
+      """`,
+			wantStatus: 1,
+		},
+		"No Response": {
+			scenario: `
+    Then the response body should match:
+      """
+      {}
+      """`,
+			wantStatus: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			feature := "Feature: users\n  Scenario: " + name + tt.scenario + "\n"
+			if status := runFeature(t, server, feature); status != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, status)
+			}
+		})
+	}
+}
+
+func TestStepsMatch(t *testing.T) {
+	s := New("")
+	s.body = []byte(`{"id": 1, "name": "jdoe"}`)
+
+	err := s.match(`{"id": "${id}", "name": "other"}`)
+	if err == nil || !strings.Contains(err.Error(), "at $.name: expected other (string), got jdoe (string)") {
+		t.Errorf("Expected a body mismatch, got %v", err)
+	}
+	if err := s.match(`{"id": "${id}", "name": "jdoe"}`); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if s.Vars["id"] != 1.0 {
+		t.Errorf("Expected the id to be captured, got %v", s.Vars["id"])
+	}
+}
@@ -0,0 +1,142 @@
+package bodyguard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+)
+
+// maxStatusBodyBytes caps how much of the body is quoted when the status code is unexpected.
+const maxStatusBodyBytes = 512
+
+// DoAndAssert sends req with client and checks that the response has wantStatus and a body
+// matching expected. Failures are labelled with the request method and URL.
+// It returns the decoded body for follow-up assertions, or nil when the body isn't valid JSON.
+// Use ByContentType as expected to decode the body according to its Content-Type.
+// A nil client uses http.DefaultClient. Options adjust the behaviour of this request only,
+// e.g. MaxBodySize or ReadTimeout.
+func DoAndAssert(t *testing.T, client *http.Client, req *http.Request, wantStatus int, expected interface{}, opts ...Option) interface{} {
+	t.Helper()
+	return defaultEngine.DoAndAssert(t, client, req, wantStatus, expected, opts...)
+}
+
+// DoAndAssert sends req with client and checks that the response has wantStatus and a body
+// matching expected, decoding it with the engine's Unmarshal function. See the package-level DoAndAssert.
+func (e *Engine) DoAndAssert(t *testing.T, client *http.Client, req *http.Request, wantStatus int, expected interface{}, opts ...Option) interface{} {
+	t.Helper()
+	e = e.with(opts)
+	body, err := e.doAndMatch(client, req, wantStatus, expected)
+	e.report(t, req.Method+" "+req.URL.String(), expected, err)
+	return body
+}
+
+// AssertRequest checks that the body of r, such as a request received by an httptest.Server handler,
+// matches expected. The body is restored afterwards, so the handler can still read it.
+// Options adjust the behaviour of this assertion only. It fails the test if there is a mismatch.
+func AssertRequest(t *testing.T, r *http.Request, expected interface{}, opts ...Option) {
+	t.Helper()
+	defaultEngine.AssertRequest(t, r, expected, opts...)
+}
+
+// AssertRequest checks that the body of r matches expected, decoding it with the engine's Unmarshal function.
+// See the package-level AssertRequest.
+func (e *Engine) AssertRequest(t *testing.T, r *http.Request, expected interface{}, opts ...Option) {
+	t.Helper()
+	e = e.with(opts)
+	e.report(t, "AssertRequest "+r.Method+" "+r.URL.String(), expected, e.requestMatch(r, expected))
+}
+
+func (e *Engine) requestMatch(r *http.Request, expected interface{}) error {
+	label := fmt.Sprintf("%s %s", r.Method, r.URL)
+
+	var body []byte
+	if r.Body != nil {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("%s: cannot read request body: %w", label, err)
+		}
+		if body, err = Decompress(r.Header.Get("Content-Encoding"), body); err != nil {
+			return fmt.Errorf("%s: cannot decode request body: %w", label, err)
+		}
+	}
+
+	if err := e.matchMessage(r.Header.Get("Content-Type"), body, expected); err != nil {
+		return fmt.Errorf("%s: %w", label, err)
+	}
+	return nil
+}
+
+func doAndMatch(client *http.Client, req *http.Request, wantStatus int, expected interface{}) (interface{}, error) {
+	return defaultEngine.doAndMatch(client, req, wantStatus, expected)
+}
+
+func (e *Engine) doAndMatch(client *http.Client, req *http.Request, wantStatus int, expected interface{}) (interface{}, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	label := fmt.Sprintf("%s %s", req.Method, req.URL)
+
+	if e.ReadTimeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), e.ReadTimeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%s: no response within %v", label, e.ReadTimeout)
+		}
+		return nil, fmt.Errorf("%s: %w", label, err)
+	}
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+	if e.MaxBodySize > 0 {
+		body = io.LimitReader(resp.Body, e.MaxBodySize+1)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%s: response body not complete within %v, got %d bytes", label, e.ReadTimeout, len(data))
+		}
+		return nil, fmt.Errorf("%s: cannot read response body: %w", label, err)
+	}
+	if e.MaxBodySize > 0 && int64(len(data)) > e.MaxBodySize {
+		return nil, fmt.Errorf("%s: expected response body of at most %d bytes, got more", label, e.MaxBodySize)
+	}
+	if data, err = decompress(resp.Header.Get("Content-Encoding"), data, e.MaxBodySize); err != nil {
+		return nil, fmt.Errorf("%s: cannot decode response body: %w", label, err)
+	}
+
+	var errs []error
+	if resp.StatusCode != wantStatus {
+		errs = append(errs, fmt.Errorf("%s: expected status %d, got %d with body %q", label, wantStatus, resp.StatusCode, truncate(data, maxStatusBodyBytes)))
+	}
+	if byType, ok := expected.(contentTypeMatcher); ok {
+		decoded, err := e.matchContent(resp.Header.Get("Content-Type"), data, byType)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", label, err))
+		}
+		return decoded, errors.Join(errs...)
+	}
+	decoded, err := e.matchBodyValue(data, expected)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", label, err))
+	}
+	return decoded, errors.Join(errs...)
+}
+
+func truncate(data []byte, n int) string {
+	if len(data) <= n {
+		return string(data)
+	}
+	return string(data[:n]) + "..."
+}
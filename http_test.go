@@ -0,0 +1,242 @@
+package bodyguard
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDoAndMatch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 1, "name": "jdoe"}`))
+	})
+	mux.HandleFunc("GET /users/2", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "not found"}`))
+	})
+	mux.HandleFunc("DELETE /users/1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := map[string]struct {
+		method     string
+		path       string
+		wantStatus int
+		expected   interface{}
+		wantBody   interface{}
+		wantErr    string
+	}{
+		"Pass": {
+			method:     http.MethodGet,
+			path:       "/users/1",
+			wantStatus: http.StatusOK,
+			expected:   Object(map[string]any{"id": 1}),
+			wantBody:   map[string]any{"id": 1.0, "name": "jdoe"},
+			wantErr:    "",
+		},
+		"Error Body Pass": {
+			method:     http.MethodGet,
+			path:       "/users/2",
+			wantStatus: http.StatusNotFound,
+			expected:   Object(map[string]any{"error": String()}),
+			wantBody:   map[string]any{"error": "not found"},
+			wantErr:    "",
+		},
+		"No Content Pass": {
+			method:     http.MethodDelete,
+			path:       "/users/1",
+			wantStatus: http.StatusNoContent,
+			expected:   EmptyBody(),
+			wantBody:   nil,
+			wantErr:    "",
+		},
+		"Status Mismatch": {
+			method:     http.MethodGet,
+			path:       "/users/2",
+			wantStatus: http.StatusOK,
+			expected:   Object(map[string]any{"error": String()}),
+			wantBody:   map[string]any{"error": "not found"},
+			wantErr:    "GET " + server.URL + "/users/2: expected status 200, got 404 with body \"{\\\"error\\\": \\\"not found\\\"}\"",
+		},
+		"Body Mismatch": {
+			method:     http.MethodGet,
+			path:       "/users/1",
+			wantStatus: http.StatusOK,
+			expected:   Object(map[string]any{"name": "other"}),
+			wantBody:   map[string]any{"id": 1.0, "name": "jdoe"},
+			wantErr:    "GET " + server.URL + "/users/1: at $.name: expected other (string), got jdoe (string)",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body, err := doAndMatch(server.Client(), req, tt.wantStatus, tt.expected)
+			if !reflect.DeepEqual(body, tt.wantBody) {
+				t.Errorf("Expected returned body %v, got %v", tt.wantBody, body)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestDoAndMatchLimits(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /large", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": "` + strings.Repeat("x", 1000) + `"}`))
+	})
+	mux.HandleFunc("GET /stream", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[`))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	mux.HandleFunc("GET /hang", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := map[string]struct {
+		path    string
+		opts    []Option
+		wantErr string
+	}{
+		"Within Size Pass": {
+			path:    "/large",
+			opts:    []Option{MaxBodySize(2000)},
+			wantErr: "",
+		},
+		"Too Large": {
+			path:    "/large",
+			opts:    []Option{MaxBodySize(100)},
+			wantErr: "GET " + server.URL + "/large: expected response body of at most 100 bytes, got more",
+		},
+		"Streaming Body": {
+			path:    "/stream",
+			opts:    []Option{ReadTimeout(50 * time.Millisecond)},
+			wantErr: "GET " + server.URL + "/stream: response body not complete within 50ms, got 1 bytes",
+		},
+		"No Response": {
+			path:    "/hang",
+			opts:    []Option{ReadTimeout(50 * time.Millisecond)},
+			wantErr: "GET " + server.URL + "/hang: no response within 50ms",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, server.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = defaultEngine.with(tt.opts).doAndMatch(server.Client(), req, http.StatusOK, Anything())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestDoAndMatchDecodedBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"amount": 10.10, "request_id": "abc"}`))
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := defaultEngine.with([]Option{DecimalNumbers(), Ignoring("request_id")})
+	body, err := e.doAndMatch(server.Client(), req, http.StatusOK, Object(map[string]any{"amount": 10.1, "request_id": "xyz"}))
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	want := map[string]any{"amount": json.Number("10.10"), "request_id": "abc"}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("Expected returned body %v, got %v", want, body)
+	}
+}
+
+func TestAssertRequest(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected interface{}
+		wantErr  string
+	}{
+		"Pass": {
+			body:     `{"name": "jdoe"}`,
+			expected: Object(map[string]any{"name": String()}),
+			wantErr:  "",
+		},
+		"Mismatch": {
+			body:     `{"name": 1}`,
+			expected: Object(map[string]any{"name": String()}),
+			wantErr:  "POST /users: at $.name: expected string, got float64",
+		},
+		"Empty Body": {
+			body:     ``,
+			expected: Object(map[string]any{"name": String()}),
+			wantErr:  "POST /users: expected JSON body, got empty body",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var handled string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := defaultEngine.requestMatch(r, tt.expected); tt.wantErr == "" && err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				} else if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				body, _ := io.ReadAll(r.Body)
+				handled = string(body)
+			}))
+			defer server.Close()
+
+			resp, err := http.Post(server.URL+"/users", "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if handled != tt.body {
+				t.Errorf("Expected the body to be restored for the handler, got %q", handled)
+			}
+		})
+	}
+}
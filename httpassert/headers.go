@@ -0,0 +1,122 @@
+package httpassert
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dedalusj/bodyguard"
+)
+
+// AssertHeader checks a header of a request or response. The name is matched case-insensitively.
+// The expected value is one of:
+//   - a string, compared exactly with the values joined with ", "
+//   - a []string, compared exactly with the values in order, for headers sent several times such as Set-Cookie
+//   - a bodyguard.Matcher applied to the values joined with ", ", such as bodyguard.Regexp
+//   - Values or Tokens, to match the individual values with array matchers
+//   - Present() or bodyguard.Absent(), to only check whether the header is set
+func AssertHeader(t *testing.T, header http.Header, name string, expected interface{}) {
+	t.Helper()
+	if err := matchHeader(header, name, expected); err != nil {
+		t.Error(err)
+	}
+}
+
+// Present asserts a header is set, with any value.
+func Present() bodyguard.Matcher {
+	return bodyguard.Anything()
+}
+
+// headerList is returned by Values and Tokens.
+type headerList struct {
+	expected interface{}
+	tokens   bool
+}
+
+func (m headerList) Match(path string, value interface{}) error {
+	return fmt.Errorf("at %s: Values and Tokens can only be used as header expectations", path)
+}
+
+// list returns the values to match, splitting comma-separated lists when matching tokens.
+func (m headerList) list(values []string) []interface{} {
+	list := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		if !m.tokens {
+			list = append(list, value)
+			continue
+		}
+		for _, token := range strings.Split(value, ",") {
+			if token = strings.TrimSpace(token); token != "" {
+				list = append(list, token)
+			}
+		}
+	}
+	return list
+}
+
+// Values matches the values of a header sent several times, such as Set-Cookie, as an array of
+// strings, one per header line, e.g. Values(bodyguard.ArraySuperset(bodyguard.Regexp(`^session=`))).
+func Values(expected interface{}) bodyguard.Matcher {
+	return headerList{expected: expected}
+}
+
+// Tokens matches the elements of a comma-separated list header, such as Vary or Allow, as an array of
+// strings, wherever they were split across header lines, e.g. Tokens(bodyguard.UnorderedArray("Accept", "Origin")).
+func Tokens(expected interface{}) bodyguard.Matcher {
+	return headerList{expected: expected, tokens: true}
+}
+
+// headerValues returns the values of the header named name in any case. Headers built by the
+// net/http package are canonical, but maps populated directly may not be.
+func headerValues(header http.Header, name string) (string, []string, bool) {
+	canonical := http.CanonicalHeaderKey(name)
+	if values, ok := header[canonical]; ok {
+		return canonical, values, true
+	}
+	var values []string
+	present := false
+	for key, v := range header {
+		if strings.EqualFold(key, name) {
+			values = append(values, v...)
+			present = true
+		}
+	}
+	return canonical, values, present
+}
+
+func matchHeader(header http.Header, name string, expected interface{}) error {
+	name, values, present := headerValues(header, name)
+	path := "header " + name
+	value := strings.Join(values, ", ")
+
+	if expected == bodyguard.Absent() {
+		if present {
+			return fmt.Errorf("at %s: expected header to be absent, got %q", path, value)
+		}
+		return nil
+	}
+	if !present {
+		return fmt.Errorf("at %s: missing header", path)
+	}
+
+	switch e := expected.(type) {
+	case headerList:
+		return bodyguard.All(e.expected).Match(path, e.list(values))
+	case bodyguard.Matcher:
+		return e.Match(path, value)
+	case string:
+		if value != e {
+			return fmt.Errorf("at %s: expected %q, got %q", path, e, value)
+		}
+		return nil
+	case []string:
+		if !slices.Equal(values, e) {
+			return fmt.Errorf("at %s: expected values %q, got %q", path, e, values)
+		}
+		return nil
+	default:
+		return fmt.Errorf("at %s: expected value must be a string, []string or bodyguard.Matcher, got %T", path, expected)
+	}
+}
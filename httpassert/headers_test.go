@@ -0,0 +1,107 @@
+package httpassert
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dedalusj/bodyguard"
+)
+
+func TestMatchHeader(t *testing.T) {
+	header := http.Header{}
+	header.Add("Set-Cookie", "session=abc; Path=/; Expires=Wed, 21 Oct 2026 07:28:00 GMT")
+	header.Add("Set-Cookie", "theme=dark")
+	header.Add("Vary", "Accept-Encoding, Origin")
+	header.Add("Vary", "Accept")
+	header["x-request-id"] = []string{"req-1"}
+
+	tests := map[string]struct {
+		name     string
+		expected interface{}
+		wantErr  string
+	}{
+		"Exact Pass": {
+			name:     "vary",
+			expected: "Accept-Encoding, Origin, Accept",
+			wantErr:  "",
+		},
+		"Regexp Pass": {
+			name:     "Set-Cookie",
+			expected: bodyguard.Regexp(`^session=`),
+			wantErr:  "",
+		},
+		"Non Canonical Key Pass": {
+			name:     "X-Request-Id",
+			expected: "req-1",
+			wantErr:  "",
+		},
+		"Present Pass": {
+			name:     "x-request-id",
+			expected: Present(),
+			wantErr:  "",
+		},
+		"Present Fail": {
+			name:     "Location",
+			expected: Present(),
+			wantErr:  "at header Location: missing header",
+		},
+		"Absent Fail": {
+			name:     "set-cookie",
+			expected: bodyguard.Absent(),
+			wantErr:  "at header Set-Cookie: expected header to be absent",
+		},
+		"Exact Values Pass": {
+			name:     "Set-Cookie",
+			expected: []string{"session=abc; Path=/; Expires=Wed, 21 Oct 2026 07:28:00 GMT", "theme=dark"},
+			wantErr:  "",
+		},
+		"Exact Values Fail": {
+			name:     "Set-Cookie",
+			expected: []string{"theme=dark"},
+			wantErr:  "at header Set-Cookie: expected values [\"theme=dark\"], got [",
+		},
+		"Values Pass": {
+			name:     "Set-Cookie",
+			expected: Values(bodyguard.ArraySuperset(bodyguard.Regexp(`^session=abc;`))),
+			wantErr:  "",
+		},
+		"Values Fail": {
+			name:     "Set-Cookie",
+			expected: Values(bodyguard.Array(bodyguard.Regexp(`^session=`), bodyguard.Regexp(`^theme=light`))),
+			wantErr:  "at header Set-Cookie[1]: expected to match \"^theme=light\", got \"theme=dark\"",
+		},
+		"Tokens Pass": {
+			name:     "Vary",
+			expected: Tokens(bodyguard.UnorderedArray("Accept", "Origin", "Accept-Encoding")),
+			wantErr:  "",
+		},
+		"Tokens Fail": {
+			name:     "Vary",
+			expected: Tokens(bodyguard.Array("Accept-Encoding", "Accept", "Origin")),
+			wantErr:  "at header Vary[1]: expected Accept (string), got Origin (string)",
+		},
+		"Unsupported Expectation": {
+			name:     "Vary",
+			expected: 1,
+			wantErr:  "at header Vary: expected value must be a string, []string or bodyguard.Matcher, got int",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := matchHeader(header, tt.name, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Package httpassert checks complete HTTP responses, status, headers and JSON body, with bodyguard matchers.
+package httpassert
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/dedalusj/bodyguard"
+)
+
+// Expect describes the expected response. Zero fields are not checked.
+type Expect struct {
+	// Status is the expected status code.
+	Status int
+
+	// Headers maps header names, in any case, to the expected value, as for AssertHeader.
+	Headers map[string]interface{}
+
+	// Body is the expected JSON body, as passed to bodyguard.Assert.
+	Body interface{}
+}
+
+// AssertResponse checks that resp matches want, then closes its body. It returns the body, decompressed
+// according to its Content-Encoding, for follow-up assertions. Options adjust how the body is matched,
+// as for bodyguard.Assert.
+// It fails the test with every mismatch found.
+func AssertResponse(t *testing.T, resp *http.Response, want Expect, opts ...bodyguard.Option) []byte {
+	t.Helper()
+	body, err := checkResponse(resp, want, opts...)
+	if err != nil {
+		t.Error(err)
+	}
+	return body
+}
+
+func checkResponse(resp *http.Response, want Expect, opts ...bodyguard.Option) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read response body: %w", err)
+	}
+	if body, err = bodyguard.Decompress(resp.Header.Get("Content-Encoding"), body); err != nil {
+		return nil, fmt.Errorf("cannot decode response body: %w", err)
+	}
+
+	var errs []error
+	if want.Status != 0 && resp.StatusCode != want.Status {
+		errs = append(errs, fmt.Errorf("expected status %d, got %d", want.Status, resp.StatusCode))
+	}
+
+	names := make([]string, 0, len(want.Headers))
+	for name := range want.Headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if err := matchHeader(resp.Header, name, want.Headers[name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if want.Body != nil {
+		if err := bodyguard.Evaluate(want.Body, body, opts...).Err; err != nil {
+			errs = append(errs, fmt.Errorf("body: %w", err))
+		}
+	}
+	return body, errors.Join(errs...)
+}
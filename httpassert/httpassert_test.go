@@ -0,0 +1,92 @@
+package httpassert
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dedalusj/bodyguard"
+)
+
+func TestCheckResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Add("Vary", "Accept")
+		w.Header().Add("Vary", "Origin")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 1, "name": "jdoe"}`))
+	}))
+	defer server.Close()
+
+	tests := map[string]struct {
+		want    Expect
+		wantErr string
+	}{
+		"Pass": {
+			want: Expect{
+				Status: http.StatusCreated,
+				Headers: map[string]interface{}{
+					"content-type": bodyguard.Regexp(`^application/json`),
+					"Vary":         "Accept, Origin",
+					"Location":     bodyguard.Absent(),
+				},
+				Body: bodyguard.Object(map[string]any{"id": bodyguard.Integer()}),
+			},
+			wantErr: "",
+		},
+		"Unchecked Fields Pass": {
+			want:    Expect{},
+			wantErr: "",
+		},
+		"Status Mismatch": {
+			want:    Expect{Status: http.StatusOK},
+			wantErr: "expected status 200, got 201",
+		},
+		"Header Mismatch": {
+			want:    Expect{Headers: map[string]interface{}{"Vary": "Accept"}},
+			wantErr: "at header Vary: expected \"Accept\", got \"Accept, Origin\"",
+		},
+		"Missing Header": {
+			want:    Expect{Headers: map[string]interface{}{"Location": bodyguard.String()}},
+			wantErr: "at header Location: missing header",
+		},
+		"Unexpected Header": {
+			want:    Expect{Headers: map[string]interface{}{"Content-Type": bodyguard.Absent()}},
+			wantErr: "at header Content-Type: expected header to be absent, got \"application/json; charset=utf-8\"",
+		},
+		"Body Mismatch": {
+			want:    Expect{Body: bodyguard.Object(map[string]any{"name": "other"})},
+			wantErr: "body: at $.name: expected other (string), got jdoe (string)",
+		},
+		"Every Mismatch": {
+			want:    Expect{Status: http.StatusOK, Body: bodyguard.Object(map[string]any{"id": "1"})},
+			wantErr: "expected status 200, got 201\nbody: at $.id",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := http.Get(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body, err := checkResponse(resp, tt.want)
+			if string(body) != `{"id": 1, "name": "jdoe"}` {
+				t.Errorf("Expected the raw body to be returned, got %q", body)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package bodyguard
+
+import (
+	"errors"
+	"strings"
+)
+
+// Label names the business rule an expectation stands for, so failures below it say which rule failed,
+// e.g. "at $.data[0]: order summary: expected ...". It suits matchers shared across a suite through
+// helper functions. Nested labels are reported outermost first. Labeled Optional, Absent and OnlyInEnv
+// keys of an Object may be missing as they would be unlabeled.
+func Label(name string, expected interface{}) Matcher {
+	return describeWrapper(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		return labelErrors(match(st, expected, path, value), name, path)
+	}), expected)
+}
+
+// labelErrors prefixes the message of every mismatch of err with the label, keeping the structure
+// of match errors so each of them still reports its own path.
+func labelErrors(err error, label, path string) error {
+	switch t := err.(type) {
+	case nil:
+		return nil
+	case *MatchError:
+		labeled := *t
+		labeled.err = labelErrors(t.err, label, t.path)
+		return &labeled
+	case interface{ Unwrap() []error }:
+		parts := t.Unwrap()
+		labeled := make([]error, len(parts))
+		for i, part := range parts {
+			labeled[i] = labelErrors(part, label, path)
+		}
+		return errors.Join(labeled...)
+	}
+	return &labeledError{label: label, path: path, err: err}
+}
+
+// labeledError is a mismatch reported with the label of its expectation.
+type labeledError struct {
+	label string
+	path  string
+	err   error
+}
+
+func (e *labeledError) Error() string {
+	msg := e.err.Error()
+	prefix := "at " + e.path + ": "
+	if !strings.HasPrefix(msg, prefix) {
+		// mismatches reported below the path of their matcher
+		location, _, ok := strings.Cut(msg, ": ")
+		if !ok || !strings.HasPrefix(location, "at ") {
+			return e.label + ": " + msg
+		}
+		prefix = location + ": "
+	}
+	return prefix + e.label + ": " + msg[len(prefix):]
+}
+
+func (e *labeledError) Unwrap() error {
+	return e.err
+}
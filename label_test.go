@@ -0,0 +1,127 @@
+package bodyguard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLabel(t *testing.T) {
+	orderSummary := Label("order summary", Object(map[string]any{
+		"currency": OneOf("EUR", "USD"),
+		"total":    Positive(),
+	}))
+
+	tests := map[string]struct {
+		body     string
+		expected interface{}
+		wantErr  string
+	}{
+		"Pass": {
+			body:     `{"data": [{"currency": "EUR", "total": 3}]}`,
+			expected: Object(map[string]any{"data": Each(orderSummary)}),
+			wantErr:  "",
+		},
+		"Value Mismatch": {
+			body:     `{"data": [{"currency": "EUR", "total": -3}]}`,
+			expected: Object(map[string]any{"data": Each(orderSummary)}),
+			wantErr:  "at $.data[0].total: order summary: expected number greater than 0",
+		},
+		"Every Mismatch Labeled": {
+			body:     `{"data": [{"currency": "GBP", "total": -3}]}`,
+			expected: Object(map[string]any{"data": Each(orderSummary)}),
+			wantErr:  "at $.data[0].currency: order summary: expected one of [EUR USD], got \"GBP\"\nat $.data[0].total: order summary: expected number greater than 0",
+		},
+		"Missing Key": {
+			body:     `{"data": [{"total": 3}]}`,
+			expected: Object(map[string]any{"data": Each(orderSummary)}),
+			wantErr:  "at $.data[0]: order summary: missing key \"currency\"",
+		},
+		"Type Mismatch": {
+			body:     `{"data": ["x"]}`,
+			expected: Object(map[string]any{"data": Each(orderSummary)}),
+			wantErr:  "at $.data[0]: order summary: expected object, got string",
+		},
+		"Literal": {
+			body:     `{"status": "closed"}`,
+			expected: Object(map[string]any{"status": Label("open orders only", "open")}),
+			wantErr:  "at $.status: open orders only: expected open (string), got closed (string)",
+		},
+		"Labeled Absent Key Missing Pass": {
+			body:     `{"id": 1}`,
+			expected: Object(map[string]any{"id": 1, "password": Label("gone", Absent())}),
+			wantErr:  "",
+		},
+		"Labeled Absent Key Present": {
+			body:     `{"id": 1, "password": "secret"}`,
+			expected: Object(map[string]any{"id": 1, "password": Label("gone", Absent())}),
+			wantErr:  "at $.password: gone: expected absent value, got secret",
+		},
+		"Labeled Optional Key Missing Pass": {
+			body:     `{"id": 1}`,
+			expected: Object(map[string]any{"id": 1, "nickname": Label("profile", Optional(String()))}),
+			wantErr:  "",
+		},
+		"Labeled Optional Key Mismatch": {
+			body:     `{"id": 1, "nickname": 2}`,
+			expected: Object(map[string]any{"id": 1, "nickname": Label("profile", Optional(String()))}),
+			wantErr:  "at $.nickname: profile: expected string, got float64",
+		},
+		"Labeled Inactive OnlyInEnv Key Missing Pass": {
+			body:     `{"id": 1}`,
+			expected: Object(map[string]any{"id": 1, "region": Label("deployment", OnlyInEnv("bodyguard-test-env", String()))}),
+			wantErr:  "",
+		},
+		"Wrapped Optional Key Missing Pass": {
+			body:     `{"id": 1}`,
+			expected: Object(map[string]any{"id": 1, "nickname": Wrap(Optional(String()), nil, nil)}),
+			wantErr:  "",
+		},
+		"Nested Labels": {
+			body:     `{"order": {"total": -3}}`,
+			expected: Label("checkout", Object(map[string]any{"order": Label("order total", Object(map[string]any{"total": Positive()}))})),
+			wantErr:  "at $.order.total: checkout: order total: expected number greater than 0",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := isMatch(tt.body, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+
+	err := isMatch(`{"currency": "GBP", "total": -3}`, orderSummary)
+	var mErr *MatchError
+	if !errors.As(err, &mErr) {
+		t.Fatalf("Expected a MatchError, got %v", err)
+	}
+	leaves := mErr.Leaves()
+	if len(leaves) != 2 || leaves[0].Path() != "$.currency" || leaves[1].Path() != "$.total" {
+		t.Fatalf("Expected labeled mismatches to keep their paths, got %v", leaves)
+	}
+	if want := "at $.total: order summary: expected number greater than 0, got -3"; leaves[1].Error() != want {
+		t.Errorf("Expected leaf %q, got %q", want, leaves[1].Error())
+	}
+}
+
+func TestLabelDescribe(t *testing.T) {
+	expected := Object(map[string]any{
+		"id":       Integer(),
+		"nickname": Label("profile", Optional(String())),
+		"password": Label("gone", Absent()),
+	})
+	if got, want := Describe(expected), "object with keys id, nickname?"; got != want {
+		t.Errorf("Expected description %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,91 @@
+package bodyguard
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// AssertLines checks that the body (as a string, []byte or io.Reader) holds newline-delimited JSON (NDJSON or
+// JSON Lines) whose documents match expectations in order, one per line. Mismatches are reported with their
+// line number, e.g. "line 3: at $.id". Options adjust the behaviour of this assertion only. It fails the
+// test if there is a mismatch.
+func AssertLines(t *testing.T, expectations []interface{}, body interface{}, opts ...Option) {
+	t.Helper()
+	defaultEngine.AssertLines(t, expectations, body, opts...)
+}
+
+// AssertLines checks that the body holds newline-delimited JSON whose documents match expectations in order,
+// decoding every line with the engine's Unmarshal function. See the package-level AssertLines.
+func (e *Engine) AssertLines(t *testing.T, expectations []interface{}, body interface{}, opts ...Option) {
+	t.Helper()
+	e = e.with(opts)
+	e.report(t, "AssertLines", expectations, e.isMatch(body, Lines(expectations...)))
+}
+
+// Lines asserts the body holds newline-delimited JSON whose documents match expectations in order,
+// one per line. Blank lines are skipped. Every mismatching line is reported with its line number,
+// e.g. "line 3: at $.id". Lines are decoded and matched with the options of the assertion.
+func Lines(expectations ...interface{}) BodyMatcher {
+	return describeBody(engineBodyMatcherFunc(func(e *Engine, body []byte) error {
+		lines, err := e.decodeLines(body)
+		if err != nil {
+			return err
+		}
+		if len(lines) != len(expectations) {
+			return fmt.Errorf("expected %d lines, got %d", len(expectations), len(lines))
+		}
+
+		var errs []error
+		for i, line := range lines {
+			if err := e.matchValue(expectations[i], line.doc); err != nil {
+				errs = append(errs, fmt.Errorf("line %d: %w", line.number, err))
+			}
+		}
+		return errors.Join(errs...)
+	}), fmt.Sprintf("newline-delimited JSON body of %d lines", len(expectations)))
+}
+
+// EachLine asserts every line of a newline-delimited JSON body matches expected, as for a stream
+// of events of one shape. Blank lines are skipped and every mismatching line is reported.
+func EachLine(expected interface{}) BodyMatcher {
+	return describeBody(engineBodyMatcherFunc(func(e *Engine, body []byte) error {
+		lines, err := e.decodeLines(body)
+		if err != nil {
+			return err
+		}
+
+		var errs []error
+		for _, line := range lines {
+			if err := e.matchValue(expected, line.doc); err != nil {
+				errs = append(errs, fmt.Errorf("line %d: %w", line.number, err))
+			}
+		}
+		return errors.Join(errs...)
+	}), "newline-delimited JSON body, each line "+Describe(expected))
+}
+
+// jsonLine is a document of a newline-delimited JSON body.
+type jsonLine struct {
+	number int // 1-based, counting blank lines
+	doc    interface{}
+}
+
+// decodeLines decodes the documents of a newline-delimited JSON body with the engine's Unmarshal
+// function, skipping blank lines.
+func (e *Engine) decodeLines(data []byte) ([]jsonLine, error) {
+	var lines []jsonLine
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var doc interface{}
+		if err := e.unmarshal(line, &doc); err != nil {
+			return nil, fmt.Errorf("line %d: invalid json: %w", i+1, err)
+		}
+		lines = append(lines, jsonLine{number: i + 1, doc: doc})
+	}
+	return lines, nil
+}
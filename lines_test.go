@@ -0,0 +1,93 @@
+package bodyguard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	body := "{\"type\": \"start\"}\n{\"type\": \"data\", \"value\": 1}\r\n\n{\"type\": \"end\"}\n"
+
+	tests := map[string]struct {
+		body     string
+		expected interface{}
+		wantErr  string
+	}{
+		"Lines Pass": {
+			body: body,
+			expected: Lines(
+				Object(map[string]any{"type": "start"}),
+				Object(map[string]any{"value": Number()}),
+				Object(map[string]any{"type": "end"}),
+			),
+			wantErr: "",
+		},
+		"Lines Mismatch Reports Line Number": {
+			body:     body,
+			expected: Lines(Object(map[string]any{"type": "start"}), Object(map[string]any{"value": String()}), Object(map[string]any{"type": "stop"})),
+			wantErr:  "line 2: at $.value: expected string, got float64\nline 4: at $.type: expected stop (string), got end (string)",
+		},
+		"Lines Count Mismatch": {
+			body:     body,
+			expected: Lines(Anything()),
+			wantErr:  "expected 1 lines, got 3",
+		},
+		"Invalid Line": {
+			body:     "{\"a\": 1}\n{\"a\": \n",
+			expected: Lines(Anything(), Anything()),
+			wantErr:  "line 2: invalid json",
+		},
+		"EachLine Pass": {
+			body:     body,
+			expected: EachLine(Object(map[string]any{"type": OneOf("start", "data", "end")})),
+			wantErr:  "",
+		},
+		"EachLine Mismatch": {
+			body:     body,
+			expected: EachLine(Object(map[string]any{"value": Number()})),
+			wantErr:  "line 1: at $: missing key \"value\"\nline 4: at $: missing key \"value\"",
+		},
+		"EachLine Empty Body": {
+			body:     "\n",
+			expected: EachLine(Object(map[string]any{"value": Number()})),
+			wantErr:  "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := isMatch(tt.body, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestAssertLines(t *testing.T) {
+	AssertLines(t, []interface{}{Object(map[string]any{"id": 1}), Object(map[string]any{"id": 2})}, "{\"id\": 1}\n{\"id\": 2}\n")
+}
+
+func TestLinesOptions(t *testing.T) {
+	e := defaultEngine.with([]Option{WithPathFormat(JSONPointer), DecimalNumbers()})
+
+	err := e.isMatch("{\"id\": 1}\n{\"id\": \"2\"}\n", EachLine(Object(map[string]any{"id": Number()})))
+	want := "line 2: at /id: expected number, got string"
+	if err == nil || err.Error() != want {
+		t.Errorf("Expected error %q, got %v", want, err)
+	}
+
+	err = e.isMatch("{\"amount\": 0.1000000000000000000001}\n", Lines(Object(map[string]any{"amount": 0.1})))
+	want = "line 1: at /amount: expected 0.1 (float64), got 0.1000000000000000000001 (json.Number)"
+	if err == nil || err.Error() != want {
+		t.Errorf("Expected error %q, got %v", want, err)
+	}
+}
@@ -0,0 +1,109 @@
+package bodyguard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// ManifestEntry is one assertion listed in the manifest written by WriteManifest.
+type ManifestEntry struct {
+	// Test is the name of the test that ran the assertion.
+	Test string `json:"test"`
+	// Assertion names the assertion and its target, e.g. "Assert" or "GET http://127.0.0.1:8080/users/1".
+	Assertion string `json:"assertion"`
+	// Expected summarizes the expectation, e.g. "object{id, name, tags?}".
+	Expected string `json:"expected"`
+	// Passed reports whether the assertion passed.
+	Passed bool `json:"passed"`
+	// Mismatches is the number of mismatches found.
+	Mismatches int `json:"mismatches"`
+}
+
+// report fails t with err, if any, and lists the assertion in the engine's manifest.
+func (e *Engine) report(t *testing.T, assertion string, expected interface{}, err error) {
+	t.Helper()
+	if err != nil {
+		t.Error(err)
+	}
+	if e.ManifestFile == "" {
+		return
+	}
+
+	if err := appendManifest(e.ManifestFile, newManifestEntry(t.Name(), assertion, expected, err)); err != nil {
+		t.Error(err)
+	}
+}
+
+func newManifestEntry(test, assertion string, expected interface{}, err error) ManifestEntry {
+	entry := ManifestEntry{Test: test, Assertion: assertion, Expected: summarize(expected), Passed: err == nil}
+	var mErr *MatchError
+	if errors.As(err, &mErr) {
+		entry.Mismatches = len(mErr.Leaves())
+	} else if err != nil {
+		entry.Mismatches = 1
+	}
+	return entry
+}
+
+// appendManifest appends entry to file as one line of JSON.
+func appendManifest(file string, entry ManifestEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("cannot write assertion manifest: %w", err)
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("cannot write assertion manifest: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("cannot write assertion manifest: %w", err)
+	}
+	return f.Close()
+}
+
+// summarize describes an expectation in one line, listing the keys of objects but not their values.
+func summarize(expected interface{}) string {
+	return summarizeSchema(schemaOf(expected))
+}
+
+func summarizeSchema(s *schema) string {
+	switch {
+	case s.invalid != nil:
+		return "invalid"
+	case len(s.enum) == 1:
+		literal, _ := json.Marshal(s.enum[0])
+		return string(literal)
+	case len(s.anyOf) > 0:
+		alternatives := make([]string, len(s.anyOf))
+		for i, alternative := range s.anyOf {
+			alternatives[i] = summarizeSchema(alternative)
+		}
+		return strings.Join(alternatives, " | ")
+	case s.typ == "object" && len(s.properties) > 0:
+		keys := slices.Sorted(maps.Keys(s.properties))
+		for i, key := range keys {
+			if !slices.Contains(s.required, key) {
+				keys[i] += "?"
+			}
+		}
+		return "object{" + strings.Join(keys, ", ") + "}"
+	case s.typ == "array" && s.items != nil:
+		return "array of " + summarizeSchema(s.items)
+	case s.typ != "" && s.format != "":
+		return s.typ + "(" + s.format + ")"
+	case s.typ != "":
+		return s.typ
+	case s.not != nil && s.not.typ == "null":
+		return "not null"
+	case s.description != "":
+		return s.description
+	}
+	return "any"
+}
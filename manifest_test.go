@@ -0,0 +1,76 @@
+package bodyguard
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	tests := map[string]struct {
+		expected interface{}
+		want     string
+	}{
+		"Object":      {expected: Object(map[string]any{"name": String(), "id": UUID(), "tags": Optional(Each(String()))}), want: "object{id, name, tags?}"},
+		"Array":       {expected: Each(Integer()), want: "array of integer"},
+		"Format":      {expected: Timestamp(), want: "string(date-time)"},
+		"Literal":     {expected: "active", want: `"active"`},
+		"Alternative": {expected: Any(Null(), Number()), want: "null | number"},
+		"Not Null":    {expected: NotNull(), want: "not null"},
+		"Custom":      {expected: MatcherFunc(func(string, interface{}) error { return nil }), want: "any"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := summarize(tt.expected); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewManifestEntry(t *testing.T) {
+	expected := Object(map[string]any{"id": Integer(), "name": String()})
+	err := isMatch(`{"id": "x"}`, expected)
+
+	got := newManifestEntry("TestUsers", "GET /users/1", expected, err)
+	want := ManifestEntry{Test: "TestUsers", Assertion: "GET /users/1", Expected: "object{id, name}", Passed: false, Mismatches: 2}
+	if got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	got = newManifestEntry("TestUsers", "Assert", expected, errors.New("body must be string, []byte or io.Reader, got int"))
+	if got.Passed || got.Mismatches != 1 {
+		t.Errorf("Expected one failure for a non-match error, got %+v", got)
+	}
+}
+
+func TestWriteManifest(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "manifest.jsonl")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	Assert(t, Object(map[string]any{"id": 1}), `{"id": 1}`, WriteManifest(file))
+	AssertGET(t, server.URL+"/users/1", http.StatusOK, Object(map[string]any{"id": Integer()}), WriteManifest(file))
+	Assert(t, Anything(), `{}`)
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	want := []string{
+		`{"test":"TestWriteManifest","assertion":"Assert","expected":"object{id}","passed":true,"mismatches":0}`,
+		`{"test":"TestWriteManifest","assertion":"GET ` + server.URL + `/users/1","expected":"object{id}","passed":true,"mismatches":0}`,
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("Expected manifest\n%s\ngot\n%s", strings.Join(want, "\n"), data)
+	}
+}
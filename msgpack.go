@@ -0,0 +1,168 @@
+package bodyguard
+
+import (
+	"fmt"
+	"math"
+)
+
+// msgpackReader decodes MessagePack data into the generic values of encoding/json.
+type msgpackReader struct {
+	data   []byte
+	offset int
+}
+
+// decodeMsgpack decodes a single MessagePack value. Numbers become float64, binary data becomes a string,
+// and map keys are converted to strings. Extension types are not supported.
+func decodeMsgpack(data []byte) (interface{}, error) {
+	r := &msgpackReader{data: data}
+	v, err := r.value()
+	if err != nil {
+		return nil, err
+	}
+	if r.offset != len(data) {
+		return nil, fmt.Errorf("unexpected data after value at offset %d", r.offset)
+	}
+	return v, nil
+}
+
+func (r *msgpackReader) next(n int) ([]byte, error) {
+	if n < 0 || len(r.data)-r.offset < n {
+		return nil, fmt.Errorf("unexpected end of data at offset %d", r.offset)
+	}
+	b := r.data[r.offset : r.offset+n]
+	r.offset += n
+	return b, nil
+}
+
+// uint reads a big-endian unsigned integer of n bytes.
+func (r *msgpackReader) uint(n int) (uint64, error) {
+	b, err := r.next(n)
+	if err != nil {
+		return 0, err
+	}
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u, nil
+}
+
+// length reads a length of n bytes.
+func (r *msgpackReader) length(n int) (int, error) {
+	u, err := r.uint(n)
+	if err != nil {
+		return 0, err
+	}
+	if u > uint64(len(r.data)) {
+		return 0, fmt.Errorf("unexpected end of data at offset %d", r.offset)
+	}
+	return int(u), nil
+}
+
+func (r *msgpackReader) value() (interface{}, error) {
+	b, err := r.next(1)
+	if err != nil {
+		return nil, err
+	}
+
+	c := b[0]
+	switch {
+	case c <= 0x7f:
+		return float64(c), nil
+	case c >= 0xe0:
+		return float64(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return r.object(int(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return r.array(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		return r.string(int(c & 0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6: // bin 8, 16, 32
+		n, err := r.length(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		return r.string(n)
+	case 0xca:
+		u, err := r.uint(4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case 0xcb:
+		u, err := r.uint(8)
+		return math.Float64frombits(u), err
+	case 0xcc, 0xcd, 0xce, 0xcf: // uint 8, 16, 32, 64
+		u, err := r.uint(1 << (c - 0xcc))
+		return float64(u), err
+	case 0xd0, 0xd1, 0xd2, 0xd3: // int 8, 16, 32, 64
+		bits := 8 << (c - 0xd0)
+		u, err := r.uint(bits / 8)
+		return float64(int64(u<<(64-bits)) >> (64 - bits)), err
+	case 0xd9, 0xda, 0xdb: // str 8, 16, 32
+		n, err := r.length(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return r.string(n)
+	case 0xdc, 0xdd: // array 16, 32
+		n, err := r.length(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return r.array(n)
+	case 0xde, 0xdf: // map 16, 32
+		n, err := r.length(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return r.object(n)
+	}
+	return nil, fmt.Errorf("unsupported type 0x%02x at offset %d", c, r.offset-1)
+}
+
+func (r *msgpackReader) string(n int) (interface{}, error) {
+	b, err := r.next(n)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (r *msgpackReader) array(n int) (interface{}, error) {
+	elements := make([]interface{}, 0, min(n, len(r.data)-r.offset))
+	for range n {
+		v, err := r.value()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, v)
+	}
+	return elements, nil
+}
+
+func (r *msgpackReader) object(n int) (interface{}, error) {
+	object := make(map[string]interface{}, min(n, len(r.data)-r.offset))
+	for range n {
+		key, err := r.value()
+		if err != nil {
+			return nil, err
+		}
+		v, err := r.value()
+		if err != nil {
+			return nil, err
+		}
+		name, ok := key.(string)
+		if !ok {
+			name = fmt.Sprint(key)
+		}
+		object[name] = v
+	}
+	return object, nil
+}
@@ -0,0 +1,70 @@
+package bodyguard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeMsgpack(t *testing.T) {
+	tests := map[string]struct {
+		data     string
+		expected interface{}
+		wantErr  string
+	}{
+		"Scalars": {
+			data:     "\x96\xc0\xc2\xc3\x7f\xff\xa1a",
+			expected: []any{nil, false, true, 127.0, -1.0, "a"},
+			wantErr:  "",
+		},
+		"Sized Integers": {
+			data:     "\x94\xcc\xff\xcd\x01\x00\xd0\x80\xd1\xff\x00",
+			expected: []any{255.0, 256.0, -128.0, -256.0},
+			wantErr:  "",
+		},
+		"Floats": {
+			data:     "\x92\xca\x3f\xc0\x00\x00\xcb\x40\x09\x21\xfb\x54\x44\x2d\x18",
+			expected: []any{1.5, 3.141592653589793},
+			wantErr:  "",
+		},
+		"Nested": {
+			data:     "\x81\xa4tags\x92\xd9\x01a\xc4\x01b",
+			expected: map[string]any{"tags": []any{"a", "b"}},
+			wantErr:  "",
+		},
+		"Truncated": {
+			data:     "\x92\x01",
+			expected: nil,
+			wantErr:  "unexpected end of data at offset 2",
+		},
+		"Trailing Data": {
+			data:     "\x01\x02",
+			expected: nil,
+			wantErr:  "unexpected data after value at offset 1",
+		},
+		"Extension": {
+			data:     "\xd4\x01\x00",
+			expected: nil,
+			wantErr:  "unsupported type 0xd4 at offset 0",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			v, err := decodeMsgpack([]byte(tt.data))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				} else if !reflect.DeepEqual(v, tt.expected) {
+					t.Errorf("Expected %v, got %v", tt.expected, v)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
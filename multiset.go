@@ -0,0 +1,156 @@
+package bodyguard
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// Multiset asserts that the value is an array holding exactly the specified elements, in any order,
+// counting repeated elements: Multiset("a", "a", "b") matches ["b", "a", "a"] but not ["a", "b", "b"].
+// It is UnorderedArray under the name of the semantics it implements.
+//
+// Literal strings, numbers, booleans and nulls are paired with equal actual values through a hash map,
+// so large literal arrays are matched in linear time; only the remaining elements are probed against each other.
+// Under WithEpsilon, numbers are always probed, as values within the tolerance are not interchangeable.
+func Multiset(elements ...interface{}) Matcher {
+	return UnorderedArray(elements...)
+}
+
+// assignUnordered pairs each expected element with a distinct actual element it matches, as assignElements,
+// and returns the indices of the expected elements that could not be paired.
+func assignUnordered(st *matchState, path string, expected, actual []interface{}, indices []int) []int {
+	restExpected, restActual := pairLiterals(st, path, expected, actual, indices)
+	if len(restExpected) == 0 {
+		return nil
+	}
+
+	probe := probeElements(st, path, expected, actual, indices)
+	paired, unmatched := assignElements(len(restExpected), len(restActual), func(i, j int) bool {
+		return probe(restExpected[i], restActual[j])
+	})
+	unpaired := make([]int, len(unmatched))
+	for k, i := range unmatched {
+		unpaired[k] = restExpected[i]
+	}
+
+	pairs := make([]elementPair, 0, len(paired))
+	for i, j := range paired {
+		if j >= 0 {
+			pairs = append(pairs, elementPair{expected: restExpected[i], actual: restActual[j]})
+		}
+	}
+	for _, pair := range commitElements(st, path, expected, actual, indices, pairs) {
+		unpaired = append(unpaired, pair.expected)
+	}
+	slices.Sort(unpaired)
+	return unpaired
+}
+
+// pairLiterals pairs expected scalar literals with equal actual values. Equal values are interchangeable,
+// so pairing them first never prevents a valid assignment of the other elements. It returns the indices of
+// the expected and actual elements left to probe, including the literals without an equal actual value,
+// which may still match under a looser comparison. indices holds the index in the document of every actual element.
+func pairLiterals(st *matchState, path string, expected, actual []interface{}, indices []int) (restExpected, restActual []int) {
+	available := map[string][]int{}
+	for j := len(actual) - 1; j >= 0; j-- {
+		if key, ok := literalKey(st, actual[j], false); ok {
+			available[key] = append(available[key], j)
+		}
+	}
+
+	paired := make([]bool, len(actual))
+	for i, e := range expected {
+		key, ok := literalKey(st, e, true)
+		if !ok {
+			restExpected = append(restExpected, i)
+			continue
+		}
+		candidates := available[key]
+		if len(candidates) == 0 {
+			restExpected = append(restExpected, i)
+			continue
+		}
+		j := candidates[len(candidates)-1]
+		available[key] = candidates[:len(candidates)-1]
+		paired[j] = true
+
+		// literal pairs skip matching, but hooks still see them
+		childPath := elementPath(path, indices[j], actual[j])
+		if st.beforeMatch != nil {
+			st.beforeMatch(childPath, actual[j])
+		}
+		if st.afterMatch != nil {
+			st.afterMatch(childPath, actual[j], nil)
+		}
+	}
+
+	for j := range actual {
+		if !paired[j] {
+			restActual = append(restActual, j)
+		}
+	}
+	return restExpected, restActual
+}
+
+// literalKey returns a key identifying a scalar value, equal for an expected literal and the actual values
+// matchNode considers equal to it. Other values, such as matchers, containers, expected json.Number
+// literals, which are compared by spelling, literals with a registered comparator and numbers compared
+// within a tolerance, have no key.
+func literalKey(st *matchState, v interface{}, expected bool) (string, bool) {
+	if st.epsilon > 0 {
+		if _, ok := literalFloat64(v); ok {
+			return "", false
+		}
+	}
+	if expected && v != nil {
+		if _, ok := comparators.Load(reflect.TypeOf(v)); ok {
+			return "", false
+		}
+	}
+
+	var n int64
+	switch v := v.(type) {
+	case nil:
+		return "null", true
+	case string:
+		return "s" + v, true
+	case bool:
+		return "b" + strconv.FormatBool(v), true
+	case json.Number:
+		if st.decimalNumbers {
+			r, ok := new(big.Rat).SetString(v.String())
+			return "r" + r.RatString(), ok
+		}
+		if expected {
+			return "", false
+		}
+		f, err := v.Float64()
+		return "n" + strconv.FormatFloat(f, 'g', -1, 64), err == nil
+	case float64:
+		if st.decimalNumbers {
+			r, ok := decimalValue(v)
+			return "r" + r.RatString(), ok
+		}
+		return "n" + strconv.FormatFloat(v, 'g', -1, 64), true
+	case int:
+		n = int64(v)
+	case int8:
+		n = int64(v)
+	case int16:
+		n = int64(v)
+	case int32:
+		n = int64(v)
+	case int64:
+		n = v
+	default:
+		return "", false
+	}
+
+	if st.decimalNumbers {
+		return "r" + new(big.Rat).SetInt64(n).RatString(), true
+	}
+	return "n" + strconv.FormatFloat(float64(n), 'g', -1, 64), true
+}
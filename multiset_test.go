@@ -0,0 +1,133 @@
+package bodyguard
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMultiset(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected interface{}
+		wantErr  string
+	}{
+		"Duplicates Pass": {
+			body:     `["b", "a", "a"]`,
+			expected: Multiset("a", "a", "b"),
+			wantErr:  "",
+		},
+		"Duplicates Count": {
+			body:     `["a", "b", "b"]`,
+			expected: Multiset("a", "a", "b"),
+			wantErr:  "at $: expected element a (index 1) not found in remaining actual elements",
+		},
+		"Numbers Pass": {
+			body:     `[2, 1.5, 2]`,
+			expected: Multiset(2, 2.0, 1.5),
+			wantErr:  "",
+		},
+		"Literals And Matchers Pass": {
+			body:     `["apple", "banana", null, {"id": 1}]`,
+			expected: Multiset(String(), nil, "apple", Object(map[string]any{"id": 1})),
+			wantErr:  "",
+		},
+		"Repeated Matchers Pass": {
+			body:     `[1, "x", 2]`,
+			expected: Multiset(Number(), Number(), "x"),
+			wantErr:  "",
+		},
+		"Repeated Matchers Fail": {
+			body:     `[1, "x", "y"]`,
+			expected: Multiset(Number(), Number(), "x"),
+			wantErr:  "at $: expected element number (index 1) not found in remaining actual elements",
+		},
+		"Literal Not Matched By Other Types": {
+			body:     `["1", true]`,
+			expected: Multiset(1, "true"),
+			wantErr:  "at $: expected elements 1 (index 0), true (index 1) not found in remaining actual elements",
+		},
+		"Length Mismatch": {
+			body:     `["a"]`,
+			expected: Multiset("a", "a"),
+			wantErr:  "at $: expected array length 2, got 1",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := isMatch(tt.body, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestMultisetLargeLiteralArray(t *testing.T) {
+	const n = 20000
+	expected := make([]interface{}, n)
+	actual := make([]string, n)
+	for i := range n {
+		expected[i] = fmt.Sprintf("item-%d", i%1000)
+		actual[n-1-i] = fmt.Sprintf("%q", expected[i])
+	}
+	body := "[" + strings.Join(actual, ",") + "]"
+
+	start := time.Now()
+	if err := isMatch(body, Multiset(expected...)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected literals to be paired without probing, took %v", elapsed)
+	}
+}
+
+func TestMultisetDecimalNumbers(t *testing.T) {
+	engine := defaultEngine.with([]Option{DecimalNumbers()})
+	if err := engine.isMatch(`[0.1, 12345678901234567.89]`, Multiset(json.Number("12345678901234567.89"), 0.1)); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := engine.isMatch(`[12345678901234567.88]`, Multiset(json.Number("12345678901234567.89"))); err == nil {
+		t.Error("Expected decimals to be compared exactly")
+	}
+}
+
+func TestMultisetEpsilon(t *testing.T) {
+	engine := defaultEngine.with([]Option{WithEpsilon(1e-9)})
+	if err := engine.isMatch(`[0.30000000000000004]`, UnorderedArray(0.3)); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := engine.isMatch(`[0.2, 0.30000000000000004, 0.1]`, Multiset(0.1, 0.3, 0.2)); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	// pairing the exactly equal 1.0 first would leave 0.95 without a partner
+	engine = defaultEngine.with([]Option{WithEpsilon(0.06)})
+	if err := engine.isMatch(`[1.0, 1.04]`, UnorderedArray(1.0, 0.95)); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := engine.isMatch(`[1.0, 1.2]`, UnorderedArray(1.0, 0.95)); err == nil {
+		t.Error("Expected numbers outside the tolerance not to match")
+	}
+}
+
+func TestMultisetCountsLiteralsAsMatched(t *testing.T) {
+	r := Evaluate(Object(map[string]any{"tags": Multiset("a", "b", String())}), `{"tags": ["c", "b", "a"]}`)
+	if r.Err != nil {
+		t.Fatalf("Expected no error, got %v", r.Err)
+	}
+	if r.Matched != 5 {
+		t.Errorf("Expected every element to count as matched, got %d", r.Matched)
+	}
+}
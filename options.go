@@ -0,0 +1,265 @@
+package bodyguard
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Option adjusts the Engine used for a single assertion.
+type Option func(*Engine)
+
+// with returns a copy of the engine with opts applied.
+func (e *Engine) with(opts []Option) *Engine {
+	if len(opts) == 0 {
+		return e
+	}
+	c := *e
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return &c
+}
+
+// Ignoring skips the given paths in both the expected and actual documents, so volatile fields
+// such as request ids or timestamps can be excluded without changing the matcher tree.
+// Paths are dot-separated and relative to the document root; a "*" segment matches any
+// object key or array index, e.g. Ignoring("meta.request_id", "data.*.updated_at").
+// Paths starting with "$" are JSONPath expressions, as with WithIgnorePaths.
+// Ignored keys are neither required by Object, literal objects or Absent, nor reported as unexpected
+// by StrictObject. Ignored array elements are left out of the array, so Array, literal arrays and
+// length checks only see the remaining elements.
+func Ignoring(paths ...string) Option {
+	return func(e *Engine) {
+		e.IgnorePaths = append(slices.Clip(e.IgnorePaths), paths...)
+	}
+}
+
+// WithIgnorePaths skips the values at the given JSONPath expressions, such as fields injected by
+// middleware, e.g. WithIgnorePaths("$.meta.request_id", "$.data[*].updated_at"). Paths use the syntax
+// of AssertPath. Like Ignoring, ignored keys are neither required by Object nor reported as unexpected
+// by StrictObject.
+func WithIgnorePaths(paths ...string) Option {
+	return Ignoring(paths...)
+}
+
+// UnorderedArrays matches every array of the expected tree regardless of order, for backends that
+// guarantee no ordering anywhere: Array and literal arrays behave like UnorderedArray.
+func UnorderedArrays() Option {
+	return func(e *Engine) {
+		e.UnorderedArrays = true
+	}
+}
+
+// WithStrictObjects reports extra keys in every object of the expected tree, as if Object were StrictObject.
+// Objects built with AllowExtra still ignore extra keys.
+func WithStrictObjects() Option {
+	return func(e *Engine) {
+		e.StrictObjects = true
+	}
+}
+
+// WithEpsilon compares number literals within the given tolerance rather than exactly,
+// e.g. WithEpsilon(1e-9) for values computed with floating point arithmetic.
+func WithEpsilon(epsilon float64) Option {
+	return func(e *Engine) {
+		e.Epsilon = epsilon
+	}
+}
+
+// WithTimeLayouts accepts timestamps in the given layouts, in the format of time.Parse, in addition to
+// RFC3339 for Timestamp and the time matchers, e.g. WithTimeLayouts("2006-01-02 15:04:05").
+func WithTimeLayouts(layouts ...string) Option {
+	return func(e *Engine) {
+		e.TimeLayouts = append(slices.Clip(e.TimeLayouts), layouts...)
+	}
+}
+
+// WithMaxErrors caps how many mismatches a failed assertion reports, e.g. WithMaxErrors(1)
+// to report only the first one.
+func WithMaxErrors(n int) Option {
+	return func(e *Engine) {
+		e.MaxErrors = n
+	}
+}
+
+// WithDiff appends the actual document to failures, with each mismatch marked on the line of its value,
+// so failures of large payloads are readable at a glance.
+func WithDiff() Option {
+	return func(e *Engine) {
+		e.Diff = true
+	}
+}
+
+// WithPathFormat reports the paths of failures in the given notation, e.g. WithPathFormat(JSONPointer)
+// for "/data/0/id" instead of "$.data[0].id".
+func WithPathFormat(format PathFormat) Option {
+	return func(e *Engine) {
+		e.PathFormat = format
+	}
+}
+
+// DecimalNumbers compares number literals as arbitrary-precision decimals rather than float64,
+// for payloads such as financial amounts with 17 or more significant digits.
+func DecimalNumbers() Option {
+	return func(e *Engine) {
+		e.DecimalNumbers = true
+	}
+}
+
+// MaxBodySize fails DoAndAssert when the response body is larger than n bytes, without reading the rest of it.
+func MaxBodySize(n int64) Option {
+	return func(e *Engine) {
+		e.MaxBodySize = n
+	}
+}
+
+// ReadTimeout fails DoAndAssert when the complete response is not received within d.
+func ReadTimeout(d time.Duration) Option {
+	return func(e *Engine) {
+		e.ReadTimeout = d
+	}
+}
+
+// WithClient sends the requests of AssertGET and its siblings with client, e.g. WithClient(srv.Client())
+// for an httptest.NewTLSServer. The default 30 second timeout is not applied when client has a Timeout.
+func WithClient(client *http.Client) Option {
+	return func(e *Engine) {
+		e.Client = client
+	}
+}
+
+// RecordFailedPaths appends the path of every mismatch of a failed assertion to file, one per line,
+// so a later run can re-assert only those paths with OnlyPaths.
+func RecordFailedPaths(file string) Option {
+	return func(e *Engine) {
+		e.FailedPathsFile = file
+	}
+}
+
+// OnlyPaths restricts assertions to the paths listed in file, as written by RecordFailedPaths, and
+// the values below them, which speeds up iterating on a few failures of a large contract suite.
+// Values elsewhere in the document are skipped. Without the file, or when it is empty, every path is asserted.
+func OnlyPaths(file string) Option {
+	return func(e *Engine) {
+		e.OnlyPathsFile = file
+	}
+}
+
+// InEnvironment sets the environment tests run in, for OnlyInEnv and SkipInEnv, overriding the BODYGUARD_ENV variable.
+func InEnvironment(name string) Option {
+	return func(e *Engine) {
+		e.Environment = name
+	}
+}
+
+// WriteManifest lists every assertion in file, one JSON ManifestEntry per line with the test name, the
+// assertion, a summary of the expectation and whether it passed, to audit which contracts a run verifies.
+// Entries are appended, so one file can collect the assertions of several packages.
+func WriteManifest(file string) Option {
+	return func(e *Engine) {
+		e.ManifestFile = file
+	}
+}
+
+// ignoredValue replaces values at ignored paths. Matching always succeeds against it.
+type ignoredValue struct{}
+
+// ignorePaths replaces every value of the decoded document found at one of paths with ignoredValue,
+// and returns the segments of paths, so matchers can skip expected keys the document lacks.
+func ignorePaths(actual interface{}, paths []string) (interface{}, [][]string, error) {
+	patterns := make([][]string, len(paths))
+	for i, p := range paths {
+		segments, err := ignoredSegments(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		actual = ignorePath(actual, segments)
+		patterns[i] = segments
+	}
+	return actual, patterns, nil
+}
+
+// ignores reports whether path, as reported by matchers, lies at or below one of the ignored paths.
+func (st *matchState) ignores(path string) bool {
+	if len(st.ignored) == 0 {
+		return false
+	}
+	segments := splitPath(path)
+	for _, pattern := range st.ignored {
+		if len(pattern) > len(segments) {
+			continue
+		}
+		matched := true
+		for i, segment := range pattern {
+			if segment != "*" && segment != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoredSegments splits an ignored path into the segments of ignorePath. Paths starting with "$" are
+// JSONPath expressions, others are dot-separated.
+func ignoredSegments(p string) ([]string, error) {
+	if !strings.HasPrefix(p, "$") {
+		return strings.Split(p, "."), nil
+	}
+	parsed, err := parseJSONPath(p)
+	if err != nil {
+		return nil, err
+	}
+	segments := make([]string, len(parsed))
+	for i, seg := range parsed {
+		switch {
+		case seg.wildcard:
+			segments[i] = "*"
+		case seg.isIndex:
+			segments[i] = strconv.Itoa(seg.index)
+		default:
+			segments[i] = seg.key
+		}
+	}
+	return segments, nil
+}
+
+// ignorePath returns value with the values at segments replaced by ignoredValue. Containers are copied
+// rather than modified, so the decoded document can still be handed back to the caller.
+func ignorePath(value interface{}, segments []string) interface{} {
+	if len(segments) == 0 {
+		return ignoredValue{}
+	}
+
+	segment, rest := segments[0], segments[1:]
+	switch v := value.(type) {
+	case map[string]any:
+		v = maps.Clone(v)
+		if segment == "*" {
+			for key, child := range v {
+				v[key] = ignorePath(child, rest)
+			}
+		} else if child, ok := v[segment]; ok {
+			v[segment] = ignorePath(child, rest)
+		}
+		return v
+	case []interface{}:
+		v = slices.Clone(v)
+		if segment == "*" {
+			for i, child := range v {
+				v[i] = ignorePath(child, rest)
+			}
+		} else if i, err := strconv.Atoi(segment); err == nil && i >= 0 && i < len(v) {
+			v[i] = ignorePath(v[i], rest)
+		}
+		return v
+	}
+	return value
+}
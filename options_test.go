@@ -0,0 +1,469 @@
+package bodyguard
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestIgnoring(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected interface{}
+		opts     []Option
+		wantErr  string
+	}{
+		"Ignored Value Mismatch Pass": {
+			body: `{"meta": {"request_id": "abc", "page": 1}}`,
+			expected: Object(map[string]any{
+				"meta": Object(map[string]any{"request_id": "xyz", "page": 1}),
+			}),
+			opts:    []Option{Ignoring("meta.request_id")},
+			wantErr: "",
+		},
+		"Ignored Missing Key Pass": {
+			body: `{"meta": {"page": 1}}`,
+			expected: Object(map[string]any{
+				"meta": Object(map[string]any{"request_id": String(), "page": 1}),
+			}),
+			opts:    []Option{Ignoring("meta.request_id")},
+			wantErr: "",
+		},
+		"Ignored Extra Key StrictObject Pass": {
+			body: `{"id": 1, "request_id": "abc"}`,
+			expected: StrictObject(map[string]any{
+				"id": 1,
+			}),
+			opts:    []Option{Ignoring("request_id")},
+			wantErr: "",
+		},
+		"Wildcard Array Pass": {
+			body: `{"data": [{"id": 1, "updated_at": "a"}, {"id": 2, "updated_at": "b"}]}`,
+			expected: Object(map[string]any{
+				"data": Array(
+					StrictObject(map[string]any{"id": 1}),
+					StrictObject(map[string]any{"id": 2, "updated_at": Timestamp()}),
+				),
+			}),
+			opts:    []Option{Ignoring("data.*.updated_at")},
+			wantErr: "",
+		},
+		"Root Prefix Pass": {
+			body:     `{"a": 1}`,
+			expected: Object(map[string]any{"a": 2}),
+			opts:     []Option{Ignoring("$.a")},
+			wantErr:  "",
+		},
+		"Ignored Key Empty Object Pass": {
+			body:     `{"meta": {"request_id": "abc"}}`,
+			expected: Object(map[string]any{"meta": Empty()}),
+			opts:     []Option{Ignoring("meta.request_id")},
+			wantErr:  "",
+		},
+		"Ignored Key Not Counted By ObjectSize": {
+			body:     `{"a": 1, "request_id": "abc"}`,
+			expected: ObjectSize(1, 1),
+			opts:     []Option{Ignoring("request_id")},
+			wantErr:  "",
+		},
+		"Not Ignored Still Fails": {
+			body: `{"meta": {"request_id": "abc", "page": 2}}`,
+			expected: Object(map[string]any{
+				"meta": Object(map[string]any{"page": 1}),
+			}),
+			opts:    []Option{Ignoring("meta.request_id")},
+			wantErr: "at $.meta.page: expected 1 (int), got 2 (float64)",
+		},
+		"JSONPath Wildcard Pass": {
+			body: `{"meta": {"request_id": "abc"}, "data": [{"id": 1, "updated_at": "a"}, {"id": 2, "updated_at": "b"}]}`,
+			expected: StrictObject(map[string]any{
+				"meta": StrictObject(map[string]any{}),
+				"data": Each(StrictObject(map[string]any{"id": Integer()})),
+			}),
+			opts:    []Option{WithIgnorePaths("$.meta.request_id", "$.data[*].updated_at")},
+			wantErr: "",
+		},
+		"JSONPath Index And Bracket Key Pass": {
+			body: `{"data": [{"x-trace": "a", "id": 1}, {"x-trace": "b", "id": 2}]}`,
+			expected: Object(map[string]any{
+				"data": Array(StrictObject(map[string]any{"id": 1}), Object(map[string]any{"x-trace": "b"})),
+			}),
+			opts:    []Option{WithIgnorePaths("$.data[0]['x-trace']")},
+			wantErr: "",
+		},
+		"JSONPath Other Element Fails": {
+			body: `{"data": [{"id": 1, "updated_at": "a"}, {"id": 2, "updated_at": "b"}]}`,
+			expected: Object(map[string]any{
+				"data": Each(StrictObject(map[string]any{"id": Integer()})),
+			}),
+			opts:    []Option{WithIgnorePaths("$.data[0].updated_at")},
+			wantErr: "at $.data[1](id=2): unexpected key \"updated_at\"",
+		},
+		"Invalid JSONPath": {
+			body:     `{"a": 1}`,
+			expected: Object(map[string]any{"a": 1}),
+			opts:     []Option{WithIgnorePaths("$.data[")},
+			wantErr:  "invalid path \"$.data[\": missing ]",
+		},
+		"Literal Object Pass": {
+			body:     `{"id": 1, "meta": {"request_id": "abc", "page": 1}}`,
+			expected: map[string]any{"id": 1, "meta": map[string]any{"request_id": "xyz", "page": 1}},
+			opts:     []Option{Ignoring("meta.request_id")},
+			wantErr:  "",
+		},
+		"Literal Object Without Ignored Key Pass": {
+			body:     `{"id": 1, "request_id": "abc"}`,
+			expected: map[string]any{"id": 1},
+			opts:     []Option{Ignoring("request_id")},
+			wantErr:  "",
+		},
+		"Literal Object Other Key Fails": {
+			body:     `{"id": 2, "request_id": "abc"}`,
+			expected: map[string]any{"id": 1, "request_id": "xyz"},
+			opts:     []Option{Ignoring("request_id")},
+			wantErr:  "at $.id: expected 1 (int), got 2 (float64)",
+		},
+		"Ignored Absent Key Pass": {
+			body:     `{"id": 1}`,
+			expected: Object(map[string]any{"id": 1, "password": Absent()}),
+			opts:     []Option{Ignoring("password")},
+			wantErr:  "",
+		},
+		"Ignored Present Key Skips Absent": {
+			body:     `{"id": 1, "password": "secret"}`,
+			expected: Object(map[string]any{"id": 1, "password": Absent()}),
+			opts:     []Option{Ignoring("password")},
+			wantErr:  "",
+		},
+		"Literal Object Missing Ignored Key Pass": {
+			body:     `{"id": 1}`,
+			expected: map[string]any{"id": 1, "request_id": "xyz"},
+			opts:     []Option{Ignoring("request_id")},
+			wantErr:  "",
+		},
+		"Ignored Element Not Counted By Array": {
+			body:     `{"data": [1, "noise", 3]}`,
+			expected: Object(map[string]any{"data": Array(1, 3)}),
+			opts:     []Option{Ignoring("data.1")},
+			wantErr:  "",
+		},
+		"Ignored Element Keeps Document Index": {
+			body:     `{"data": [1, "noise", 3]}`,
+			expected: Object(map[string]any{"data": Array(1, 4)}),
+			opts:     []Option{Ignoring("data.1")},
+			wantErr:  "at $.data[2]: expected 4 (int), got 3 (float64)",
+		},
+		"Ignored Element Not Counted By Literal Array": {
+			body:     `{"data": [1, "noise", 3]}`,
+			expected: map[string]any{"data": []any{1, 3}},
+			opts:     []Option{Ignoring("data.1")},
+			wantErr:  "",
+		},
+		"Ignored Element Not Counted By ArrayLength": {
+			body:     `{"data": [1, "noise", 3]}`,
+			expected: Object(map[string]any{"data": ArrayLengthExact(2)}),
+			opts:     []Option{Ignoring("data.1")},
+			wantErr:  "",
+		},
+		"Ignored Element Not Counted By UnorderedArray": {
+			body:     `{"data": [3, "noise", 1]}`,
+			expected: Object(map[string]any{"data": UnorderedArray(1, 3)}),
+			opts:     []Option{Ignoring("data.1")},
+			wantErr:  "",
+		},
+		"Ignored Elements Leave Empty Array": {
+			body:     `{"data": [1, 2]}`,
+			expected: Object(map[string]any{"data": Empty()}),
+			opts:     []Option{Ignoring("data.*")},
+			wantErr:  "",
+		},
+		"Without Option Fails": {
+			body: `{"id": 1, "request_id": "abc"}`,
+			expected: StrictObject(map[string]any{
+				"id": 1,
+			}),
+			wantErr: "unexpected key \"request_id\"",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := defaultEngine.with(tt.opts).isMatch(tt.body, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+
+	if len(defaultEngine.IgnorePaths) != 0 {
+		t.Errorf("Expected options not to modify the default engine, got %v", defaultEngine.IgnorePaths)
+	}
+}
+
+func TestUnorderedArrays(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected interface{}
+		wantErr  string
+	}{
+		"Array Pass": {
+			body:     `{"tags": ["b", "a"]}`,
+			expected: Object(map[string]any{"tags": Array("a", "b")}),
+			wantErr:  "",
+		},
+		"Nested Literal Pass": {
+			body: `{"groups": [{"name": "y", "members": [2, 1]}, {"name": "x", "members": [3]}]}`,
+			expected: map[string]any{"groups": []any{
+				map[string]any{"name": "x", "members": []any{3.0}},
+				map[string]any{"name": "y", "members": []any{1.0, 2.0}},
+			}},
+			wantErr: "",
+		},
+		"Literal Extra Key Fail": {
+			body:     `[{"a": 1, "b": 2}]`,
+			expected: []any{map[string]any{"a": 1.0}},
+			wantErr:  "at $: expected element map[a:1] (index 0) not found in remaining actual elements",
+		},
+		"Missing Element Fail": {
+			body:     `["a", "c"]`,
+			expected: Array("a", "b"),
+			wantErr:  "at $: expected element b (index 1) not found in remaining actual elements",
+		},
+		"Length Fail": {
+			body:     `["a"]`,
+			expected: Array("a", "b"),
+			wantErr:  "at $: expected array length 2, got 1",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := defaultEngine.with([]Option{UnorderedArrays()}).isMatch(tt.body, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+
+	if err := isMatch(`["b", "a"]`, Array("a", "b")); err == nil {
+		t.Errorf("Expected arrays to stay ordered without the option")
+	}
+}
+
+func TestWithMaxErrors(t *testing.T) {
+	body := `{"a": 1, "b": 2, "c": [3, 4]}`
+	expected := StrictObject(map[string]any{"a": "x", "b": "y", "c": Array(5, 6)})
+
+	err := isMatch(body, expected)
+	if err == nil {
+		t.Fatal("Expected mismatches")
+	}
+	want := "at $.a: expected x (string), got 1 (float64)\n" +
+		"at $.b: expected y (string), got 2 (float64)\n" +
+		"at $.c[0]: expected 5 (int), got 3 (float64)\n" +
+		"at $.c[1]: expected 6 (int), got 4 (float64)"
+	if err.Error() != want {
+		t.Errorf("Expected every mismatch in key order, got %q", err.Error())
+	}
+
+	err = defaultEngine.with([]Option{WithMaxErrors(2)}).isMatch(body, expected)
+	want = "at $.a: expected x (string), got 1 (float64)\n" +
+		"at $.b: expected y (string), got 2 (float64)\n" +
+		"... and 2 more mismatches"
+	if err == nil || err.Error() != want {
+		t.Errorf("Expected capped mismatches, got %v", err)
+	}
+
+	err = defaultEngine.with([]Option{WithMaxErrors(4)}).isMatch(body, expected)
+	if err == nil || strings.Contains(err.Error(), "more mismatches") {
+		t.Errorf("Expected no cap note when within the limit, got %v", err)
+	}
+}
+
+func TestDecimalNumbers(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected interface{}
+		wantErr  string
+	}{
+		"Long Decimal Pass": {
+			body:     `{"amount": 12345678901234567.89}`,
+			expected: Object(map[string]any{"amount": json.Number("12345678901234567.89")}),
+			wantErr:  "",
+		},
+		"Long Decimal Mismatch": {
+			body:     `{"amount": 12345678901234567.89}`,
+			expected: Object(map[string]any{"amount": json.Number("12345678901234567.88")}),
+			wantErr:  "at $.amount: expected 12345678901234567.88 (json.Number), got 12345678901234567.89 (json.Number)",
+		},
+		"Float Literal Pass": {
+			body:     `{"rate": 0.10}`,
+			expected: Object(map[string]any{"rate": 0.1}),
+			wantErr:  "",
+		},
+		"Large Integer Mismatch": {
+			body:     `{"id": 9007199254740993}`,
+			expected: Object(map[string]any{"id": int64(9007199254740992)}),
+			wantErr:  "at $.id: expected 9007199254740992 (int64), got 9007199254740993 (json.Number)",
+		},
+		"Large Integer Pass": {
+			body:     `{"id": 9007199254740993}`,
+			expected: Object(map[string]any{"id": uint64(9007199254740993)}),
+			wantErr:  "",
+		},
+		"Matchers Still Apply": {
+			body:     `{"id": 1.5}`,
+			expected: Object(map[string]any{"id": Integer()}),
+			wantErr:  "at $.id: expected integer",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := defaultEngine.with([]Option{DecimalNumbers()}).isMatch(tt.body, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+
+	if err := isMatch(`{"id": 9007199254740993}`, Object(map[string]any{"id": int64(9007199254740992)})); err != nil {
+		t.Errorf("Expected float64 comparison without the option, got %v", err)
+	}
+}
+
+func TestWithStrictObjects(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected interface{}
+		wantErr  string
+	}{
+		"Exact Keys Pass": {
+			body:     `{"id": 1, "user": {"name": "a"}}`,
+			expected: Object(map[string]any{"id": 1, "user": Object(map[string]any{"name": "a"})}),
+			wantErr:  "",
+		},
+		"Extra Key Mismatch": {
+			body:     `{"id": 1, "role": "admin"}`,
+			expected: Object(map[string]any{"id": 1}),
+			wantErr:  "at $: unexpected key \"role\"",
+		},
+		"Nested Extra Key Mismatch": {
+			body:     `{"id": 1, "user": {"name": "a", "email": "a@b.c"}}`,
+			expected: Object(map[string]any{"id": 1, "user": Object(map[string]any{"name": "a"})}),
+			wantErr:  "at $.user: unexpected key \"email\"",
+		},
+		"AllowExtra Pass": {
+			body:     `{"id": 1, "user": {"name": "a", "email": "a@b.c"}}`,
+			expected: Object(map[string]any{"id": 1, "user": Object(map[string]any{"name": "a"}).AllowExtra()}),
+			wantErr:  "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := defaultEngine.with([]Option{WithStrictObjects()}).isMatch(tt.body, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+
+	if err := isMatch(`{"id": 1, "role": "admin"}`, Object(map[string]any{"id": 1})); err != nil {
+		t.Errorf("Expected extra keys to be ignored without the option, got %v", err)
+	}
+}
+
+func TestWithEpsilon(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected interface{}
+		opts     []Option
+		wantErr  string
+	}{
+		"Float Within Epsilon Pass": {
+			body:     `{"total": 0.30000000000000004}`,
+			expected: Object(map[string]any{"total": 0.3}),
+			opts:     []Option{WithEpsilon(1e-9)},
+			wantErr:  "",
+		},
+		"Integer Within Epsilon Pass": {
+			body:     `{"count": 2.0001}`,
+			expected: Object(map[string]any{"count": 2}),
+			opts:     []Option{WithEpsilon(0.001)},
+			wantErr:  "",
+		},
+		"Outside Epsilon Mismatch": {
+			body:     `{"total": 0.31}`,
+			expected: Object(map[string]any{"total": 0.3}),
+			opts:     []Option{WithEpsilon(1e-9)},
+			wantErr:  "at $.total: expected 0.3 (float64), got 0.31 (float64)",
+		},
+		"Decimal Numbers Pass": {
+			body:     `{"total": 0.30000000000000004}`,
+			expected: Object(map[string]any{"total": 0.3}),
+			opts:     []Option{DecimalNumbers(), WithEpsilon(1e-9)},
+			wantErr:  "",
+		},
+		"No Epsilon Mismatch": {
+			body:     `{"total": 0.30000000000000004}`,
+			expected: Object(map[string]any{"total": 0.3}),
+			wantErr:  "at $.total: expected 0.3 (float64)",
+		},
+		"Strings Unaffected": {
+			body:     `{"total": "0.3"}`,
+			expected: Object(map[string]any{"total": 0.3}),
+			opts:     []Option{WithEpsilon(1)},
+			wantErr:  "at $.total: expected 0.3 (float64), got 0.3 (string)",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := defaultEngine.with(tt.opts).isMatch(tt.body, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package bodyguard
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// PaginationChecker accumulates item ids across the pages of a paginated listing, spanning several
+// assertions, so duplicated or skipped items are caught. Use Page as the expectation of each page's
+// items array and AssertComplete once every page has been fetched.
+// It is safe for concurrent use.
+type PaginationChecker struct {
+	idField string
+
+	mu    sync.Mutex
+	pages int
+	seen  map[interface{}]seenItem
+}
+
+// seenItem records where an id was first seen.
+type seenItem struct {
+	page int
+	path string
+}
+
+// NewPaginationChecker returns a PaginationChecker identifying items by the named field, e.g. "id".
+func NewPaginationChecker(idField string) *PaginationChecker {
+	return &PaginationChecker{idField: idField, seen: map[interface{}]seenItem{}}
+}
+
+// Page asserts that the value is an array of objects with a string or number id, none of which was seen
+// on this or an earlier page. The ids of a page are only recorded once the whole assertion passes, so
+// pages probed by UnorderedArray, Any or Not, and assertions failing elsewhere, leave the checker unchanged.
+func (p *PaginationChecker) Page() Matcher {
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("at %s: expected array, got %T", path, value)
+		}
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		page := p.pages + 1
+		arr, indices := arrayElements(arr)
+		items := make(map[interface{}]seenItem, len(arr))
+		for i, element := range arr {
+			childPath := elementPath(path, indices[i], element)
+			obj, ok := element.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("at %s: expected object, got %T", childPath, element)
+			}
+
+			id := obj[p.idField]
+			switch id.(type) {
+			case string, float64, json.Number:
+			default:
+				return fmt.Errorf("at %s: expected string or number %q, got %v", childPath, p.idField, id)
+			}
+
+			first, ok := p.seen[id]
+			if !ok {
+				first, ok = items[id]
+			}
+			if ok {
+				return fmt.Errorf("at %s: duplicate %s %v, already seen at %s on page %d", childPath, p.idField, id, first.path, first.page)
+			}
+			items[id] = seenItem{page: page, path: childPath}
+		}
+
+		st.captures = append(st.captures, func() { p.record(items) })
+		return nil
+	}), &schema{typ: "array", description: fmt.Sprintf("page of objects with unseen %s", p.idField)})
+}
+
+// record adds the ids of an accepted page.
+func (p *PaginationChecker) record(items map[interface{}]seenItem) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pages++
+	for id, item := range items {
+		item.page = p.pages
+		p.seen[id] = item
+	}
+}
+
+// AssertComplete checks that exactly total distinct items were seen across all pages,
+// catching items skipped between pages.
+func (p *PaginationChecker) AssertComplete(t *testing.T, total int) {
+	t.Helper()
+	if err := p.complete(total); err != nil {
+		t.Error(err)
+	}
+}
+
+func (p *PaginationChecker) complete(total int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.seen) != total {
+		return fmt.Errorf("expected %d items across %d pages, got %d", total, p.pages, len(p.seen))
+	}
+	return nil
+}
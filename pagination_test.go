@@ -0,0 +1,91 @@
+package bodyguard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPaginationChecker(t *testing.T) {
+	tests := map[string]struct {
+		pages   []string
+		total   int
+		wantErr string
+	}{
+		"Pass": {
+			pages:   []string{`{"data": [{"id": 1}, {"id": 2}]}`, `{"data": [{"id": 3}]}`},
+			total:   3,
+			wantErr: "",
+		},
+		"Duplicate Across Pages": {
+			pages:   []string{`{"data": [{"id": 1}, {"id": 2}]}`, `{"data": [{"id": 2}, {"id": 3}]}`},
+			wantErr: "at $.data[0](id=2): duplicate id 2, already seen at $.data[1](id=2) on page 1",
+		},
+		"Duplicate Within Page": {
+			pages:   []string{`{"data": [{"id": "a"}, {"id": "a"}]}`},
+			wantErr: "at $.data[1](id=a): duplicate id a, already seen at $.data[0](id=a) on page 1",
+		},
+		"Missing Id": {
+			pages:   []string{`{"data": [{"name": "a"}]}`},
+			wantErr: "at $.data[0]: expected string or number \"id\", got <nil>",
+		},
+		"Gap": {
+			pages:   []string{`{"data": [{"id": 1}, {"id": 2}]}`, `{"data": [{"id": 4}]}`},
+			total:   4,
+			wantErr: "expected 4 items across 2 pages, got 3",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			checker := NewPaginationChecker("id")
+			var err error
+			for _, page := range tt.pages {
+				if err = isMatch(page, Object(map[string]any{"data": checker.Page()})); err != nil {
+					break
+				}
+			}
+			if err == nil {
+				err = checker.complete(tt.total)
+			}
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestPaginationCheckerRejectedPage(t *testing.T) {
+	checker := NewPaginationChecker("id")
+	if err := isMatch(`[{"id": 1}, {"id": 1}]`, checker.Page()); err == nil {
+		t.Fatal("Expected duplicate within page to fail")
+	}
+	Assert(t, checker.Page(), `[{"id": 1}]`)
+	checker.AssertComplete(t, 1)
+}
+
+func TestPaginationCheckerFailedAssertion(t *testing.T) {
+	checker := NewPaginationChecker("id")
+	err := isMatch(`{"data": [{"id": 1}], "status": "failed"}`, Object(map[string]any{
+		"data":   checker.Page(),
+		"status": "ok",
+	}))
+	if err == nil {
+		t.Fatal("Expected mismatch")
+	}
+
+	// the page is probed against both expected elements, but only paired with one
+	Assert(t, UnorderedArray(Each(Object(map[string]any{"id": 2})), checker.Page()), `[[{"id": 2}], [{"id": 3}]]`)
+	checker.AssertComplete(t, 1)
+
+	Assert(t, checker.Page(), `[{"id": 1}, {"id": 2}]`)
+	checker.AssertComplete(t, 3)
+}
@@ -0,0 +1,93 @@
+package bodyguard
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PathFormat is the notation of the paths reported by failed assertions.
+type PathFormat int
+
+const (
+	// JSONPath renders paths as JSONPath expressions, e.g. "$.data[0].id". It is the default.
+	JSONPath PathFormat = iota
+	// JSONPointer renders paths as RFC 6901 JSON Pointers, e.g. "/data/0/id".
+	// The document root is the empty pointer, quoted as "" in messages.
+	JSONPointer
+)
+
+// messagePathRegex matches a path reported in a message, e.g. "at $.data[0](id=5).name".
+var messagePathRegex = regexp.MustCompile(`\bat (\$(?:\.[^\s.\[:,()]+|\[\d+\](?:\(id=(?:"(?:[^"\\]|\\.)*"|[^)]*)\))?)*)`)
+
+// render returns path, given as a JSONPath expression, in the format f.
+func (f PathFormat) render(path string) string {
+	if f != JSONPointer {
+		return path
+	}
+
+	var b strings.Builder
+	for _, segment := range splitPath(path) {
+		b.WriteString("/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(segment))
+	}
+	return b.String()
+}
+
+// splitPath returns the object keys and array indices of a reported path, e.g. ["data", "0", "id"]
+// for "$.data[0](id=5).id".
+func splitPath(path string) []string {
+	var segments []string
+	rest := strings.TrimPrefix(path, "$")
+	for rest != "" {
+		var segment string
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			segment, rest = rest[1:end+1], rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			segment, rest = rest[1:end], rest[end+1:]
+			rest = skipElementID(rest)
+		default:
+			segment, rest = rest, ""
+		}
+		segments = append(segments, segment)
+	}
+	return segments
+}
+
+// skipElementID returns rest without the id annotation of an array element it starts with, e.g. "(id=5)"
+// or "(id=\"a)b\")". The id is only a hint for the reader and not part of the path.
+func skipElementID(rest string) string {
+	if !strings.HasPrefix(rest, "(id=") {
+		return rest
+	}
+	id := rest[len("(id="):]
+	if quoted, err := strconv.QuotedPrefix(id); err == nil {
+		return strings.TrimPrefix(id[len(quoted):], ")")
+	}
+	if close := strings.IndexByte(id, ')'); close >= 0 {
+		return id[close+1:]
+	}
+	return rest
+}
+
+// renderMessage rewrites the paths reported in msg in the format f.
+func (f PathFormat) renderMessage(msg string) string {
+	if f != JSONPointer {
+		return msg
+	}
+	return messagePathRegex.ReplaceAllStringFunc(msg, func(match string) string {
+		pointer := f.render(strings.TrimPrefix(match, "at "))
+		if pointer == "" {
+			pointer = `""`
+		}
+		return "at " + pointer
+	})
+}
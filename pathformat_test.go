@@ -0,0 +1,83 @@
+package bodyguard
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPathFormatRender(t *testing.T) {
+	tests := map[string]struct {
+		path     string
+		expected string
+	}{
+		"Root":          {path: "$", expected: ""},
+		"Key":           {path: "$.data", expected: "/data"},
+		"Index":         {path: "$.data[0].id", expected: "/data/0/id"},
+		"Nested Arrays": {path: "$[1][2]", expected: "/1/2"},
+		"Element Id":    {path: "$.data[3](id=42).name", expected: "/data/3/name"},
+		"Quoted Id":     {path: `$.data[3](id="a)b.c[0]").name`, expected: "/data/3/name"},
+		"Escaped Key":   {path: "$.a/b.c~d", expected: "/a~1b/c~0d"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := JSONPointer.render(tt.path); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+			if got := JSONPath.render(tt.path); got != tt.path {
+				t.Errorf("Expected JSONPath to keep %q, got %q", tt.path, got)
+			}
+		})
+	}
+}
+
+func TestWithPathFormat(t *testing.T) {
+	body := `{"data": [{"id": 1, "name": "a"}, {"id": 1, "name": "b"}]}`
+	expected := StrictObject(map[string]any{
+		"data":  All(UniqueBy("id"), Each(Object(map[string]any{"name": "a"}))),
+		"count": Integer(),
+	})
+
+	err := defaultEngine.with([]Option{WithPathFormat(JSONPointer)}).isMatch(body, expected)
+	want := `at "": missing key "count"` + "\n" +
+		"at /data/1/id: duplicate value 1, first seen at index 0"
+	if err == nil || err.Error() != want {
+		t.Fatalf("Expected pointer paths %q, got %v", want, err)
+	}
+
+	err = defaultEngine.with([]Option{WithPathFormat(JSONPointer)}).isMatch(body, Object(map[string]any{"data": Each(Object(map[string]any{"name": "a"}))}))
+	var mErr *MatchError
+	if !errors.As(err, &mErr) {
+		t.Fatalf("Expected a *MatchError, got %T", err)
+	}
+	leaves := mErr.Leaves()
+	if len(leaves) != 1 || leaves[0].Path() != "/data/1/name" || leaves[0].Error() != "at /data/1/name: expected a (string), got b (string)" {
+		t.Errorf("Expected leaf at /data/1/name, got %v", leaves)
+	}
+}
+
+func TestHostileElementID(t *testing.T) {
+	body := `{"data": [{"id": "a)b.c[0]", "name": "b"}]}`
+	expected := Object(map[string]any{"data": Each(Object(map[string]any{"name": "a"}))})
+
+	err := isMatch(body, expected)
+	want := `at $.data[0](id="a)b.c[0]").name: expected a (string), got b (string)`
+	if err == nil || err.Error() != want {
+		t.Fatalf("Expected quoted id %q, got %v", want, err)
+	}
+
+	err = defaultEngine.with([]Option{WithPathFormat(JSONPointer)}).isMatch(body, expected)
+	want = "at /data/0/name: expected a (string), got b (string)"
+	if err == nil || err.Error() != want {
+		t.Errorf("Expected pointer %q, got %v", want, err)
+	}
+
+	var mErr *MatchError
+	if !errors.As(isMatch(body, expected), &mErr) {
+		t.Fatal("Expected a *MatchError")
+	}
+	tree := newPathTree([]string{mErr.Leaves()[0].path})
+	if !tree.contains("$.data[0].name") || tree.contains("$.data[0].id") {
+		t.Errorf("Expected the recorded path to select only $.data[0].name")
+	}
+}
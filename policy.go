@@ -0,0 +1,169 @@
+package bodyguard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Policy is an ordered set of rules applied to every value of a document whose path matches,
+// for enforcing response conventions such as "every *_at field is a timestamp" across an API.
+// A Policy is a Matcher, so it can be passed to Assert, DoAndAssert or a StubServer route,
+// and combined with a regular expectation through All.
+type Policy struct {
+	Rules []PolicyRule
+}
+
+// PolicyRule applies Expected to every value found at a path matching Path.
+// Path is dot-separated and relative to the value the policy is matched against. Each segment is a
+// path.Match pattern for an object key or array index, e.g. "*_at", and a "**" segment matches any
+// number of segments, e.g. "**.password". Use Absent() as Expected to forbid a field.
+type PolicyRule struct {
+	Path     string
+	Expected interface{}
+}
+
+// policyMatchers are the matchers a policy file can refer to by name.
+var policyMatchers = map[string]func() Matcher{
+	"Absent":    Absent,
+	"Anything":  Anything,
+	"NotNull":   NotNull,
+	"Null":      Null,
+	"Bool":      Bool,
+	"String":    String,
+	"Number":    Number,
+	"Integer":   Integer,
+	"Positive":  Positive,
+	"Negative":  Negative,
+	"UUID":      UUID,
+	"Email":     Email,
+	"URL":       URL,
+	"Timestamp": Timestamp,
+	"Date":      Date,
+	"TZName":    TZName,
+	"Empty":     Empty,
+	"NotEmpty":  NotEmpty,
+}
+
+// LoadPolicy reads a policy from a JSON file of the form
+//
+//	{"rules": [{"path": "**.*_at", "matcher": "Timestamp"}, {"path": "**.password", "matcher": "Absent"}]}
+//
+// where matcher names one of the argument-free matchers of this package.
+func LoadPolicy(filename string) (*Policy, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open policy file: %w", err)
+	}
+	return ParsePolicy(data)
+}
+
+// ParsePolicy parses a policy in the format read by LoadPolicy.
+func ParsePolicy(data []byte) (*Policy, error) {
+	var config struct {
+		Rules []struct {
+			Path    string `json:"path"`
+			Matcher string `json:"matcher"`
+		} `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+
+	p := &Policy{}
+	for i, r := range config.Rules {
+		newMatcher, ok := policyMatchers[r.Matcher]
+		if !ok {
+			return nil, fmt.Errorf("invalid policy: rule %d: unknown matcher %q", i, r.Matcher)
+		}
+		p.Rules = append(p.Rules, PolicyRule{Path: r.Path, Expected: newMatcher()})
+	}
+	return p, nil
+}
+
+func (p *Policy) Match(path string, value interface{}) error {
+	return matchRoot(newMatchState(), p, path, value)
+}
+
+// matchState applies the rules in order, reporting every violation in document order.
+func (p *Policy) matchState(st *matchState, path string, value interface{}) error {
+	var errs []error
+	for _, rule := range p.Rules {
+		pattern := strings.Split(rule.Path, ".")
+		if pattern[0] == "$" {
+			pattern = pattern[1:]
+		}
+
+		walkValues(path, nil, value, func(childPath string, segments []string, child interface{}) error {
+			if !matchSegments(pattern, segments) {
+				return nil
+			}
+			if err := match(st, rule.Expected, childPath, child); err != nil {
+				errs = append(errs, fmt.Errorf("policy rule %q: %w", rule.Path, err))
+			}
+			return nil
+		})
+	}
+	return errors.Join(errs...)
+}
+
+// walkValues calls fn for value and every value nested in it, with the path and the key or index segments
+// leading to it. Object keys are visited in sorted order.
+func walkValues(path string, segments []string, value interface{}, fn func(path string, segments []string, value interface{}) error) error {
+	if _, ok := value.(ignoredValue); ok {
+		return nil
+	}
+	if err := fn(path, segments, value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			if err := walkValues(fmt.Sprintf("%s.%s", path, key), append(slices.Clip(segments), key), v[key], fn); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, element := range v {
+			if err := walkValues(elementPath(path, i, element), append(slices.Clip(segments), strconv.Itoa(i)), element, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// matchSegments reports whether the key or index segments of a path match the pattern segments.
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], segments[0]); err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,97 @@
+package bodyguard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPolicy(t *testing.T) {
+	policy := &Policy{Rules: []PolicyRule{
+		{Path: "**.*_at", Expected: Timestamp()},
+		{Path: "**.password", Expected: Absent()},
+		{Path: "data.*.id", Expected: Integer()},
+	}}
+
+	tests := map[string]struct {
+		body     string
+		expected interface{}
+		wantErr  string
+	}{
+		"Pass": {
+			body:     `{"created_at": "2024-01-01T00:00:00Z", "data": [{"id": 1, "user": {"updated_at": "2024-01-02T00:00:00Z"}}]}`,
+			expected: policy,
+			wantErr:  "",
+		},
+		"Nested Timestamp Fail": {
+			body:     `{"data": [{"id": 7, "user": {"updated_at": "yesterday"}}]}`,
+			expected: policy,
+			wantErr:  "policy rule \"**.*_at\": at $.data[0](id=7).user.updated_at: parsing time \"yesterday\"",
+		},
+		"Forbidden Field": {
+			body:     `{"user": {"name": "jdoe", "password": "hunter2"}}`,
+			expected: policy,
+			wantErr:  "policy rule \"**.password\": at $.user.password: expected absent value, got hunter2",
+		},
+		"Wildcard Index": {
+			body:     `{"data": [{"id": 1}, {"id": 1.5}]}`,
+			expected: policy,
+			wantErr:  "policy rule \"data.*.id\": at $.data[1](id=1.5).id: expected integer",
+		},
+		"Combined With Expectation": {
+			body:     `{"name": "jdoe", "password": "x"}`,
+			expected: All(policy, Object(map[string]any{"name": "jdoe"})),
+			wantErr:  "at $.password: expected absent value",
+		},
+		"Root Prefix": {
+			body:     `{"token": 1}`,
+			expected: &Policy{Rules: []PolicyRule{{Path: "$.token", Expected: String()}}},
+			wantErr:  "at $.token: expected string",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := isMatch(tt.body, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "policy.json")
+	config := `{"rules": [{"path": "**.*_at", "matcher": "Timestamp"}, {"path": "**.password", "matcher": "Absent"}]}`
+	if err := os.WriteFile(filename, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := LoadPolicy(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policy.Rules) != 2 || policy.Rules[1].Path != "**.password" {
+		t.Fatalf("Expected two rules, got %+v", policy.Rules)
+	}
+	if err := isMatch(`{"user": {"password": ""}}`, policy); err == nil || !strings.Contains(err.Error(), "expected absent value") {
+		t.Errorf("Expected password to be forbidden, got %v", err)
+	}
+
+	if _, err := ParsePolicy([]byte(`{"rules": [{"path": "a", "matcher": "Bogus"}]}`)); err == nil || !strings.Contains(err.Error(), "rule 0: unknown matcher \"Bogus\"") {
+		t.Errorf("Expected unknown matcher error, got %v", err)
+	}
+	if _, err := LoadPolicy(filepath.Join(dir, "missing.json")); err == nil || !strings.Contains(err.Error(), "cannot open policy file") {
+		t.Errorf("Expected missing file error, got %v", err)
+	}
+}
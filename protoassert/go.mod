@@ -0,0 +1,10 @@
+module github.com/dedalusj/bodyguard/protoassert
+
+go 1.25.5
+
+require (
+	github.com/dedalusj/bodyguard v0.0.0
+	google.golang.org/protobuf v1.36.11
+)
+
+replace github.com/dedalusj/bodyguard => ../
@@ -0,0 +1,40 @@
+// Package protoassert checks protobuf messages, such as gRPC responses, with bodyguard matchers.
+package protoassert
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dedalusj/bodyguard"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// marshalOptions emit unpopulated fields with their zero value: proto3 does not distinguish an unset
+// field from a zero one, so expectations need not either.
+var marshalOptions = protojson.MarshalOptions{EmitUnpopulated: true}
+
+// Assert checks that msg, converted to JSON with protojson, matches the expected structure, so gRPC handler
+// tests get the partial matching of bodyguard.Assert. Fields are keyed by their lowerCamelCase JSON name.
+// Unset fields hold their zero value, or null for fields with presence such as proto2 optional fields and
+// messages. As in protojson, 64-bit integers are strings, matched with
+// bodyguard.StringAsInt, enums are value names and well-known types such as Timestamp use their JSON form.
+// Options adjust the behaviour of this assertion only. It fails the test if there is a mismatch.
+func Assert(t *testing.T, expected interface{}, msg proto.Message, opts ...bodyguard.Option) {
+	t.Helper()
+	body, err := toJSON(msg)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	bodyguard.Assert(t, expected, body, opts...)
+}
+
+// toJSON converts msg to the JSON document it is matched as.
+func toJSON(msg proto.Message) ([]byte, error) {
+	body, err := marshalOptions.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %s to JSON: %w", msg.ProtoReflect().Descriptor().FullName(), err)
+	}
+	return body, nil
+}
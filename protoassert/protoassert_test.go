@@ -0,0 +1,85 @@
+package protoassert
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dedalusj/bodyguard"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestAssert(t *testing.T) {
+	msg := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String("user_id"),
+		Number: proto.Int32(1),
+		Type:   descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(),
+	}
+	Assert(t, bodyguard.Object(map[string]any{
+		"name":     "user_id",
+		"number":   1,
+		"type":     "TYPE_INT64",
+		"jsonName": nil,
+	}), msg)
+
+	Assert(t, bodyguard.Timestamp(), timestamppb.New(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)))
+}
+
+func TestToJSON(t *testing.T) {
+	details, _ := structpb.NewStruct(map[string]any{"tags": []any{"a", "b"}})
+
+	tests := map[string]struct {
+		msg      proto.Message
+		expected interface{}
+		wantErr  string
+	}{
+		"Zero Values Pass": {
+			msg:      &wrapperspb.Int32Value{},
+			expected: 0,
+			wantErr:  "",
+		},
+		"Int64 As String": {
+			msg:      wrapperspb.Int64(42),
+			expected: bodyguard.StringAsInt(bodyguard.NumberWithinRange(1, 100)),
+			wantErr:  "",
+		},
+		"Struct Pass": {
+			msg:      details,
+			expected: bodyguard.Object(map[string]any{"tags": bodyguard.UnorderedArray("b", "a")}),
+			wantErr:  "",
+		},
+		"Nested Mismatch": {
+			msg: &descriptorpb.DescriptorProto{
+				Name:  proto.String("User"),
+				Field: []*descriptorpb.FieldDescriptorProto{{Name: proto.String("id"), Number: proto.Int32(2)}},
+			},
+			expected: bodyguard.Object(map[string]any{"field": bodyguard.Each(bodyguard.Object(map[string]any{"number": 1}))}),
+			wantErr:  "at $.field[0].number: expected 1 (int), got 2 (float64)",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			body, err := toJSON(tt.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = bodyguard.Evaluate(tt.expected, body).Err
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package bodyguard
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestRequire(t *testing.T) {
+	if os.Getenv("BODYGUARD_REQUIRE_MISMATCH") == "1" {
+		Require(t, Object(map[string]any{"id": 2}), `{"id": 1}`)
+		t.Log("still running after Require")
+		return
+	}
+
+	Require(t, Object(map[string]any{"id": 1}), `{"id": 1}`)
+
+	// a mismatch stops the test, which is observed from a separate run of this test
+	cmd := exec.Command(os.Args[0], "-test.run=^TestRequire$", "-test.v")
+	cmd.Env = append(os.Environ(), "BODYGUARD_REQUIRE_MISMATCH=1")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected the test to fail, got\n%s", out)
+	}
+	if !strings.Contains(string(out), "at $.id: expected 2 (int), got 1 (float64)") {
+		t.Errorf("Expected the mismatch to be reported, got\n%s", out)
+	}
+	if strings.Contains(string(out), "still running after Require") {
+		t.Errorf("Expected the test to stop at Require, got\n%s", out)
+	}
+}
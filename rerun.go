@@ -0,0 +1,149 @@
+package bodyguard
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// readPaths reads the paths listed in file, one per line. A missing file lists no paths.
+func readPaths(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var paths []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !slices.Contains(paths, line) {
+			paths = append(paths, line)
+		}
+	}
+	return paths, scanner.Err()
+}
+
+// recordFailedPaths appends the path of every mismatch of err to file.
+func recordFailedPaths(file string, err error) error {
+	mErr, ok := err.(*MatchError)
+	if !ok {
+		return nil
+	}
+
+	var b strings.Builder
+	for _, leaf := range mErr.Leaves() {
+		b.WriteString(leaf.path + "\n")
+	}
+
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("cannot record failed paths: %w", err)
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		f.Close()
+		return fmt.Errorf("cannot record failed paths: %w", err)
+	}
+	return f.Close()
+}
+
+// pathTree is a set of paths, stored by segment.
+type pathTree struct {
+	terminal bool
+	children map[string]*pathTree
+}
+
+func newPathTree(paths []string) *pathTree {
+	root := &pathTree{}
+	for _, path := range paths {
+		node := root
+		for _, segment := range splitPath(path) {
+			if node.children == nil {
+				node.children = map[string]*pathTree{}
+			}
+			child, ok := node.children[segment]
+			if !ok {
+				child = &pathTree{}
+				node.children[segment] = child
+			}
+			node = child
+		}
+		node.terminal = true
+	}
+	return root
+}
+
+// contains reports whether path is one of the paths of the tree or lies below one of them.
+func (t *pathTree) contains(path string) bool {
+	node := t
+	for _, segment := range splitPath(path) {
+		if node.terminal {
+			return true
+		}
+		node = node.children[segment]
+		if node == nil {
+			return false
+		}
+	}
+	return node.terminal
+}
+
+// keepOnly replaces every value of the decoded document that neither lies on the way to one of the paths
+// of the tree nor below one of them with ignoredValue, so it is skipped during matching. Containers are
+// copied rather than modified.
+func (t *pathTree) keepOnly(value interface{}) interface{} {
+	if t.terminal {
+		return value
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		v = maps.Clone(v)
+		for key, child := range v {
+			if node, ok := t.children[key]; ok {
+				v[key] = node.keepOnly(child)
+			} else {
+				v[key] = ignoredValue{}
+			}
+		}
+		return v
+	case []interface{}:
+		v = slices.Clone(v)
+		for i, child := range v {
+			if node, ok := t.children[strconv.Itoa(i)]; ok {
+				v[i] = node.keepOnly(child)
+			} else {
+				v[i] = ignoredValue{}
+			}
+		}
+		return v
+	}
+	return value
+}
+
+// keepErrors keeps the mismatches of err found at or below one of the paths of the tree.
+func (t *pathTree) keepErrors(err error) error {
+	mErr, ok := err.(*MatchError)
+	if !ok {
+		return err
+	}
+
+	var kept []error
+	for _, leaf := range mErr.Leaves() {
+		if t.contains(leaf.path) {
+			kept = append(kept, leaf)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return &MatchError{path: mErr.path, expected: mErr.expected, actual: mErr.actual, err: errors.Join(kept...)}
+}
@@ -0,0 +1,51 @@
+package bodyguard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordFailedPathsAndOnlyPaths(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "failed.txt")
+	body := `{"id": 1, "name": 2, "tags": ["a", 3], "meta": {"page": "1"}}`
+	expected := Object(map[string]any{
+		"id":   String(),
+		"name": String(),
+		"tags": Each(String()),
+		"meta": Object(map[string]any{"page": Integer()}),
+	})
+
+	record := defaultEngine.with([]Option{RecordFailedPaths(file)})
+	if err := record.isMatch(body, expected); err == nil {
+		t.Fatal("Expected mismatches")
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "$.id\n$.meta.page\n$.name\n$.tags[1]\n"; string(data) != want {
+		t.Errorf("Expected recorded paths %q, got %q", want, string(data))
+	}
+
+	if err := os.WriteFile(file, []byte("$.name\n$.tags\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var checked []string
+	only := defaultEngine.with([]Option{OnlyPaths(file)})
+	only.BeforeMatch = func(path string, value interface{}) { checked = append(checked, path) }
+	err = only.isMatch(body, expected)
+	want := "at $.name: expected string, got float64\nat $.tags[1]: expected string, got float64"
+	if err == nil || err.Error() != want {
+		t.Errorf("Expected only the listed paths to be asserted %q, got %v", want, err)
+	}
+	if strings.Contains(strings.Join(checked, ","), "$.meta.page") {
+		t.Errorf("Expected values outside the listed paths to be skipped, got %v", checked)
+	}
+
+	missing := defaultEngine.with([]Option{OnlyPaths(filepath.Join(t.TempDir(), "missing.txt"))})
+	if err := missing.isMatch(body, expected); err == nil || !strings.Contains(err.Error(), "at $.meta.page") {
+		t.Errorf("Expected every path to be asserted without a file, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package bodyguard
+
+import (
+	"errors"
+	"maps"
+)
+
+// Result is the outcome of Evaluate, for making custom pass/fail decisions such as tolerating
+// a small share of drifting fields in exploratory monitoring.
+type Result struct {
+	// Matched is the number of values of the document that matched their expectation.
+	Matched int
+	// Failures lists every mismatch, in the order it is reported. WithMaxErrors does not apply.
+	Failures []*MatchError
+	// Captured holds the values captured by Capture and Template placeholders, keyed by name.
+	Captured map[string]interface{}
+	// Err is the error Assert would report without WithMaxErrors, or nil when the body matched.
+	// It is also set when the body could not be decoded, in which case Failures is empty.
+	Err error
+}
+
+// Passed reports whether the body matched.
+func (r *Result) Passed() bool {
+	return r.Err == nil
+}
+
+// FailureRate returns the share of checked values that did not match, between 0 and 1.
+func (r *Result) FailureRate() float64 {
+	checked := r.Matched + len(r.Failures)
+	if checked == 0 {
+		return 0
+	}
+	return float64(len(r.Failures)) / float64(checked)
+}
+
+// Evaluate matches the body (as a string, []byte or io.Reader) against the expected structure without failing
+// a test, and returns a summary of the match. Options adjust the behaviour of this evaluation only.
+func Evaluate(expected interface{}, body interface{}, opts ...Option) *Result {
+	return defaultEngine.Evaluate(expected, body, opts...)
+}
+
+// Evaluate matches the body against the expected structure, decoding it with the engine's Unmarshal
+// function, and returns a summary of the match. See the package-level Evaluate.
+func (e *Engine) Evaluate(expected interface{}, body interface{}, opts ...Option) *Result {
+	c := *e.with(opts)
+	c.MaxErrors = 0
+
+	// a value counts as matched when one of its checks passed, since matchers such as
+	// UnorderedArray probe values against several expectations
+	matched := map[string]bool{}
+	afterMatch := c.AfterMatch
+	c.AfterMatch = func(path string, value interface{}, err error) {
+		if err == nil {
+			matched[path] = true
+		}
+		if afterMatch != nil {
+			afterMatch(path, value, err)
+		}
+	}
+
+	r := &Result{Captured: map[string]interface{}{}}
+	st := c.newMatchState()
+	data, err := bodyBytes(body)
+	if err == nil {
+		err = c.matchBodyState(st, data, expected)
+	}
+	r.Err = err
+
+	var mErr *MatchError
+	if errors.As(err, &mErr) {
+		r.Failures = mErr.Leaves()
+		for _, failure := range r.Failures {
+			delete(matched, failure.path)
+		}
+	}
+	r.Matched = len(matched)
+	for name, v := range maps.All(st.vars) {
+		r.Captured[name] = v.value
+	}
+	return r
+}
@@ -0,0 +1,48 @@
+package bodyguard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	expected := Object(map[string]any{
+		"id":   Capture("id"),
+		"name": "jdoe",
+		"tags": Array("a", "b"),
+	})
+
+	r := Evaluate(expected, `{"id": 7, "name": "jdoe", "tags": ["a", "b"]}`)
+	if !r.Passed() || r.Err != nil {
+		t.Fatalf("Expected a pass, got %v", r.Err)
+	}
+	// $, $.id, $.name, $.tags, $.tags[0] and $.tags[1]
+	if r.Matched != 6 || len(r.Failures) != 0 || r.FailureRate() != 0 {
+		t.Errorf("Expected 6 matched values and no failures, got %d and %v", r.Matched, r.Failures)
+	}
+	if want := map[string]any{"id": 7.0}; !reflect.DeepEqual(r.Captured, want) {
+		t.Errorf("Expected captured values %v, got %v", want, r.Captured)
+	}
+
+	r = Evaluate(expected, `{"id": 7, "name": "other", "tags": ["a", "c"]}`, WithMaxErrors(1))
+	if r.Passed() {
+		t.Fatal("Expected a failure")
+	}
+	var paths []string
+	for _, failure := range r.Failures {
+		paths = append(paths, failure.Path())
+	}
+	if want := "$.name,$.tags[1]"; strings.Join(paths, ",") != want {
+		t.Errorf("Expected every failure %s despite WithMaxErrors, got %s", want, strings.Join(paths, ","))
+	}
+	// $.id and $.tags[0]
+	if r.Matched != 2 || r.FailureRate() != 0.5 {
+		t.Errorf("Expected 2 matched values and a failure rate of 0.5, got %d and %v", r.Matched, r.FailureRate())
+	}
+
+	r = Evaluate(expected, `{"id": `)
+	if r.Passed() || len(r.Failures) != 0 || !strings.Contains(r.Err.Error(), "invalid json") {
+		t.Errorf("Expected a decoding error without failures, got %v and %v", r.Err, r.Failures)
+	}
+}
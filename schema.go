@@ -0,0 +1,232 @@
+package bodyguard
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// schema describes the values accepted by an expectation, for exporting it to other formats.
+// Its fields follow JSON Schema; zero fields place no constraint.
+type schema struct {
+	typ     string // "string", "number", "integer", "boolean", "null", "object" or "array"
+	format  string // "uuid", "email", "uri", "date-time" or "date"
+	pattern string
+	enum    []interface{}
+
+	minimum, maximum                   *float64
+	exclusiveMinimum, exclusiveMaximum *float64
+	minLength, maxLength               *int
+
+	properties        map[string]*schema
+	required          []string
+	closed            bool    // no properties other than those listed
+	additional        *schema // every property not listed
+	propertyNames     *schema
+	patternProperties map[string]*schema
+	minProperties     *int
+	maxProperties     *int
+
+	items       *schema   // every element
+	prefixItems []*schema // the first elements, in order
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	anyOf []*schema
+	allOf []*schema
+	not   *schema
+
+	invalid error // the expectation can never be matched
+
+	description string // the values accepted, in words, where the fields above cannot say it
+}
+
+// describedMatcher is a matcher annotated with the schema of the values it accepts.
+type describedMatcher struct {
+	Matcher
+	schema *schema
+
+	// wrapped is the expectation m matches with extra behaviour, such as a label, if any
+	wrapped interface{}
+}
+
+func (m describedMatcher) matchState(st *matchState, path string, value interface{}) error {
+	return matchNode(st, m.Matcher, path, value)
+}
+
+func (m describedMatcher) keyPresence(st *matchState) keyPresence {
+	return presenceOf(st, m.wrapped)
+}
+
+// describe annotates m with the schema of the values it accepts.
+func describe(m Matcher, s *schema) Matcher {
+	return describedMatcher{Matcher: m, schema: s}
+}
+
+// describeWrapper annotates m, which matches expected with extra behaviour, with the schema of expected.
+// m keeps the key presence of expected, so it can wrap Optional or Absent keys of an Object.
+func describeWrapper(m Matcher, expected interface{}) Matcher {
+	return describedMatcher{Matcher: m, schema: schemaOf(expected), wrapped: expected}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// stringValues returns the options of a string enumeration.
+func stringValues(options []string) []interface{} {
+	values := make([]interface{}, len(options))
+	for i, option := range options {
+		values[i] = option
+	}
+	return values
+}
+
+// schemaOf returns the schema of the values accepted by an expectation. Matchers that cannot be
+// described accept any value.
+func schemaOf(expected interface{}) *schema {
+	if m, ok := expected.(Matcher); ok && isNilMatcher(m) {
+		return &schema{invalid: errNilMatcher}
+	}
+
+	switch e := expected.(type) {
+	case invalidMatcher:
+		return &schema{invalid: e.err}
+	case describedMatcher:
+		return e.schema
+	case *ObjectMatcher:
+		return objectSchema(e.expected, e.strict)
+	case optionalMatcher:
+		return schemaOf(e.expected)
+	case envMatcher:
+		return schemaOf(e.expected)
+	case allMatcher:
+		s := &schema{}
+		for _, child := range e {
+			if _, ok := child.(BodyMatcher); !ok {
+				s.allOf = append(s.allOf, schemaOf(child))
+			}
+		}
+		if merged, ok := mergeSchemas(s.allOf); ok {
+			return merged
+		}
+		return s
+	case Matcher:
+		if d, ok := e.(Describer); ok {
+			// a matcher describing itself in words only
+			return &schema{description: d.Describe()}
+		}
+		return &schema{}
+	case nil:
+		return &schema{typ: "null"}
+	case string:
+		return &schema{typ: "string", enum: []interface{}{e}}
+	case bool:
+		return &schema{typ: "boolean", enum: []interface{}{e}}
+	case json.Number:
+		return &schema{typ: "number", enum: []interface{}{e}}
+	case map[string]interface{}:
+		return objectSchema(e, true)
+	case []interface{}:
+		return arraySchema(e)
+	}
+
+	if m, ok := comparatorFor(expected); ok {
+		return schemaOf(m)
+	}
+	switch reflect.ValueOf(expected).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &schema{typ: "integer", enum: []interface{}{expected}}
+	case reflect.Float32, reflect.Float64:
+		return &schema{typ: "number", enum: []interface{}{expected}}
+	}
+	return &schema{}
+}
+
+// schemasOf returns the schema of every expectation.
+func schemasOf(expectations []interface{}) []*schema {
+	schemas := make([]*schema, len(expectations))
+	for i, e := range expectations {
+		schemas[i] = schemaOf(e)
+	}
+	return schemas
+}
+
+func objectSchema(expected map[string]interface{}, strict bool) *schema {
+	s := &schema{typ: "object", properties: map[string]*schema{}, closed: strict}
+	for _, key := range slices.Sorted(maps.Keys(expected)) {
+		e := expected[key]
+		switch presenceOf(nil, e) {
+		case keyAbsent:
+			continue
+		case keyOptional:
+			s.properties[key] = schemaOf(e)
+		default:
+			s.properties[key] = schemaOf(e)
+			s.required = append(s.required, key)
+		}
+	}
+	return s
+}
+
+// arraySchema returns the schema of an array whose elements match elements in order.
+func arraySchema(elements []interface{}) *schema {
+	return &schema{typ: "array", prefixItems: schemasOf(elements), minItems: ptr(len(elements)), maxItems: ptr(len(elements))}
+}
+
+// mergeSchemas combines the constraints of schemas that all apply to one value into a single schema.
+// It fails when two of them constrain the same aspect, such as two patterns or two element schemas.
+func mergeSchemas(schemas []*schema) (*schema, bool) {
+	merged := &schema{}
+	for _, s := range schemas {
+		if !mergeField(&merged.typ, s.typ) || !mergeField(&merged.format, s.format) || !mergeField(&merged.pattern, s.pattern) ||
+			!mergeField(&merged.minimum, s.minimum) || !mergeField(&merged.maximum, s.maximum) ||
+			!mergeField(&merged.exclusiveMinimum, s.exclusiveMinimum) || !mergeField(&merged.exclusiveMaximum, s.exclusiveMaximum) ||
+			!mergeField(&merged.minLength, s.minLength) || !mergeField(&merged.maxLength, s.maxLength) ||
+			!mergeField(&merged.additional, s.additional) || !mergeField(&merged.propertyNames, s.propertyNames) ||
+			!mergeField(&merged.minProperties, s.minProperties) || !mergeField(&merged.maxProperties, s.maxProperties) ||
+			!mergeField(&merged.items, s.items) || !mergeField(&merged.minItems, s.minItems) || !mergeField(&merged.maxItems, s.maxItems) ||
+			!mergeField(&merged.not, s.not) || !mergeField(&merged.description, s.description) {
+			return nil, false
+		}
+		if (len(merged.enum) > 0 && len(s.enum) > 0) || (len(merged.properties) > 0 && len(s.properties) > 0) ||
+			(len(merged.patternProperties) > 0 && len(s.patternProperties) > 0) || (len(merged.prefixItems) > 0 && len(s.prefixItems) > 0) ||
+			(len(merged.anyOf) > 0 && len(s.anyOf) > 0) || len(s.allOf) > 0 {
+			return nil, false
+		}
+		if len(s.enum) > 0 {
+			merged.enum = s.enum
+		}
+		if len(s.properties) > 0 {
+			merged.properties, merged.required = s.properties, s.required
+		}
+		if len(s.patternProperties) > 0 {
+			merged.patternProperties = s.patternProperties
+		}
+		if len(s.prefixItems) > 0 {
+			merged.prefixItems = s.prefixItems
+		}
+		if len(s.anyOf) > 0 {
+			merged.anyOf = s.anyOf
+		}
+		merged.closed = merged.closed || s.closed
+		merged.uniqueItems = merged.uniqueItems || s.uniqueItems
+	}
+	return merged, true
+}
+
+// mergeField sets *dst to src unless src is the zero value. It fails when both are set to different values.
+func mergeField[T comparable](dst *T, src T) bool {
+	var zero T
+	if src == zero || *dst == src {
+		return true
+	}
+	if *dst != zero {
+		return false
+	}
+	*dst = src
+	return true
+}
@@ -0,0 +1,101 @@
+package bodyguard
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// recordSeparator starts every record of an RFC 7464 JSON text sequence.
+const recordSeparator = 0x1E
+
+// AssertSequence checks that the body (as a string, []byte or io.Reader) holds a sequence of JSON documents
+// matching expectations in order. Both RFC 7464 JSON text sequences and plainly concatenated
+// documents are supported. Every mismatching document is reported, numbered from 1. Options adjust the behaviour of this assertion only. It fails the test if
+// there is a mismatch.
+func AssertSequence(t *testing.T, expectations []interface{}, body interface{}, opts ...Option) {
+	t.Helper()
+	defaultEngine.AssertSequence(t, expectations, body, opts...)
+}
+
+// AssertSequence checks that the body holds a sequence of JSON documents matching expectations in order,
+// decoding every document with the engine's Unmarshal function. See the package-level AssertSequence.
+func (e *Engine) AssertSequence(t *testing.T, expectations []interface{}, body interface{}, opts ...Option) {
+	t.Helper()
+	e = e.with(opts)
+	e.report(t, "AssertSequence", expectations, e.isSequenceMatch(body, expectations))
+}
+
+func isSequenceMatch(body interface{}, expectations []interface{}) error {
+	return defaultEngine.isSequenceMatch(body, expectations)
+}
+
+func (e *Engine) isSequenceMatch(body interface{}, expectations []interface{}) error {
+	data, err := bodyBytes(body)
+	if err != nil {
+		return err
+	}
+
+	docs, err := e.decodeSequence(data)
+	if err != nil {
+		return err
+	}
+
+	if len(docs) != len(expectations) {
+		return fmt.Errorf("expected %d documents, got %d", len(expectations), len(docs))
+	}
+
+	var errs []error
+	for i, expected := range expectations {
+		if err := e.matchValue(expected, docs[i]); err != nil {
+			errs = append(errs, fmt.Errorf("document %d: %w", i+1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// decodeSequence splits data into its JSON documents.
+func (e *Engine) decodeSequence(data []byte) ([]interface{}, error) {
+	if bytes.IndexByte(data, recordSeparator) >= 0 {
+		return e.decodeTextSequence(data)
+	}
+
+	var docs []interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var raw json.RawMessage
+		err := dec.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		var doc interface{}
+		if err == nil {
+			err = e.unmarshal(raw, &doc)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("document %d: invalid json: %w", len(docs)+1, err)
+		}
+		docs = append(docs, doc)
+	}
+}
+
+// decodeTextSequence decodes an RFC 7464 sequence, where every document is preceded by a record separator.
+func (e *Engine) decodeTextSequence(data []byte) ([]interface{}, error) {
+	records := bytes.Split(data, []byte{recordSeparator})
+	if len(bytes.TrimSpace(records[0])) != 0 {
+		return nil, fmt.Errorf("invalid json sequence: data before first record separator")
+	}
+
+	docs := make([]interface{}, 0, len(records)-1)
+	for i, record := range records[1:] {
+		var doc interface{}
+		if err := e.unmarshal(record, &doc); err != nil {
+			return nil, fmt.Errorf("document %d: invalid json: %w", i+1, err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
@@ -0,0 +1,84 @@
+package bodyguard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsSequenceMatch(t *testing.T) {
+	tests := map[string]struct {
+		body         string
+		expectations []interface{}
+		wantErr      string
+	}{
+		"Text Sequence Pass": {
+			body:         "\x1e{\"type\": \"start\"}\n\x1e{\"type\": \"data\", \"value\": 1}\n\x1e{\"type\": \"end\"}\n",
+			expectations: []interface{}{Object(map[string]any{"type": "start"}), Object(map[string]any{"value": Number()}), Object(map[string]any{"type": "end"})},
+			wantErr:      "",
+		},
+		"Concatenated Pass": {
+			body:         `{"a": 1}{"a": 2} [3] "four"`,
+			expectations: []interface{}{Object(map[string]any{"a": 1}), Object(map[string]any{"a": 2}), Array(3), "four"},
+			wantErr:      "",
+		},
+		"Document Mismatch": {
+			body:         `{"a": 1} {"a": 2}`,
+			expectations: []interface{}{Object(map[string]any{"a": 1}), Object(map[string]any{"a": 3})},
+			wantErr:      "document 2: at $.a: expected 3 (int), got 2 (float64)",
+		},
+		"Every Document Mismatch": {
+			body:         `{"a": 1} {"a": 2}`,
+			expectations: []interface{}{Object(map[string]any{"a": 0}), Object(map[string]any{"a": 3})},
+			wantErr:      "document 1: at $.a: expected 0 (int), got 1 (float64)\ndocument 2: at $.a: expected 3 (int), got 2 (float64)",
+		},
+		"Count Mismatch": {
+			body:         "\x1e1\n\x1e2\n",
+			expectations: []interface{}{1},
+			wantErr:      "expected 1 documents, got 2",
+		},
+		"Invalid Record": {
+			body:         "\x1e{\"a\": 1}\n\x1e{\"a\": \n",
+			expectations: []interface{}{Object(map[string]any{}), Object(map[string]any{})},
+			wantErr:      "document 2: invalid json",
+		},
+		"Data Before Separator": {
+			body:         "{}\x1e{}\n",
+			expectations: []interface{}{Object(map[string]any{})},
+			wantErr:      "data before first record separator",
+		},
+		"Invalid Concatenated": {
+			body:         `{"a": 1} {"a"`,
+			expectations: []interface{}{Object(map[string]any{}), Object(map[string]any{})},
+			wantErr:      "document 2: invalid json",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := isSequenceMatch(tt.body, tt.expectations)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestIsSequenceMatchOptions(t *testing.T) {
+	e := defaultEngine.with([]Option{WithPathFormat(JSONPointer), DecimalNumbers()})
+	err := e.isSequenceMatch("\x1e{\"id\": 1}\n\x1e{\"id\": 0.1}\n", []interface{}{
+		Object(map[string]any{"id": 1}),
+		Object(map[string]any{"id": "0.1"}),
+	})
+	want := "document 2: at /id: expected 0.1 (string), got 0.1 (json.Number)"
+	if err == nil || err.Error() != want {
+		t.Errorf("Expected error %q, got %v", want, err)
+	}
+}
@@ -0,0 +1,180 @@
+package bodyguard
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// UpdateSnapshotsVariable names the variable that, when set to a true value such as "1", makes
+// AssertSnapshot rewrite golden files rather than compare against them.
+const UpdateSnapshotsVariable = "BODYGUARD_UPDATE_SNAPSHOTS"
+
+// Overrides replaces the values of a snapshot at the given paths, typically with matchers for dynamic
+// fields, e.g. Overrides{"$.id": UUID(), "$.items[*].created_at": Timestamp()}. Paths use the syntax of
+// AssertFlat, with the leading "$" optional.
+type Overrides map[string]interface{}
+
+// AssertSnapshot checks that the body matches the JSON document stored in a golden file, except at the
+// paths of overrides, whose values are matched by the given expectations instead. Objects must match
+// exactly and arrays in order. Options adjust the behaviour of this assertion only.
+// It fails the test if there is a mismatch.
+//
+// When the BODYGUARD_UPDATE_SNAPSHOTS variable is true, or the test binary defines an -update flag that
+// is set, the golden file is rewritten from the body instead. Values at the paths of overrides keep
+// those of the previous golden file, or become a placeholder describing their expectation, so dynamic
+// fields do not churn.
+func AssertSnapshot(t *testing.T, filename string, body interface{}, overrides Overrides, opts ...Option) {
+	t.Helper()
+	defaultEngine.AssertSnapshot(t, filename, body, overrides, opts...)
+}
+
+// AssertSnapshot checks that the body matches the golden file except at the paths of overrides,
+// decoding it with the engine's Unmarshal function. See the package-level AssertSnapshot.
+func (e *Engine) AssertSnapshot(t *testing.T, filename string, body interface{}, overrides Overrides, opts ...Option) {
+	t.Helper()
+	e = e.with(opts)
+	if updateSnapshots() {
+		if err := e.updateSnapshot(filename, body, overrides); err != nil {
+			t.Error(err)
+			return
+		}
+		t.Logf("updated snapshot %s", filename)
+		return
+	}
+	expected, err := e.isSnapshotMatch(filename, body, overrides)
+	e.report(t, "AssertSnapshot "+filename, expected, err)
+}
+
+// isSnapshotMatch matches the body against a golden file, returning the expectation read from it.
+func (e *Engine) isSnapshotMatch(filename string, body interface{}, overrides Overrides) (Matcher, error) {
+	expected, err := readSnapshot(filename, overrides)
+	if err != nil {
+		return nil, err
+	}
+	return expected, e.isMatch(body, expected)
+}
+
+// readSnapshot returns the expectation of a golden file with its overrides applied.
+func readSnapshot(filename string, overrides Overrides) (Matcher, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read snapshot: %w", err)
+	}
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", filename, err)
+	}
+	return withOverrides(document, overrides), nil
+}
+
+// updateSnapshots reports whether golden files are to be rewritten.
+func updateSnapshots() bool {
+	if update, err := strconv.ParseBool(os.Getenv(UpdateSnapshotsVariable)); err == nil && update {
+		return true
+	}
+	if f := flag.Lookup("update"); f != nil {
+		update, err := strconv.ParseBool(f.Value.String())
+		return err == nil && update
+	}
+	return false
+}
+
+// updateSnapshot writes the body to a golden file, keeping the previous values at the paths of overrides.
+func (e *Engine) updateSnapshot(filename string, body interface{}, overrides Overrides) error {
+	data, err := bodyBytes(body)
+	if err != nil {
+		return err
+	}
+	// numbers are kept as written
+	decoder := *e
+	decoder.DecimalNumbers = true
+	var document interface{}
+	if err := decoder.unmarshal(data, &document); err != nil {
+		return fmt.Errorf("invalid json: %w", err)
+	}
+
+	var previous interface{}
+	previousData, err := os.ReadFile(filename)
+	hasPrevious := err == nil
+	if hasPrevious {
+		dec := json.NewDecoder(bytes.NewReader(previousData))
+		dec.UseNumber()
+		hasPrevious = dec.Decode(&previous) == nil
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(overrides)) {
+		path := key
+		switch {
+		case strings.HasPrefix(key, "["):
+			path = "$" + key
+		case !strings.HasPrefix(key, "$"):
+			path = "$." + key
+		}
+		segments, err := parseJSONPath(path)
+		if err != nil {
+			return err
+		}
+		placeholder := "<" + summarize(overrides[key]) + ">"
+		document = keepPrevious(document, previous, hasPrevious, segments, placeholder)
+	}
+
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(document); err != nil {
+		return fmt.Errorf("cannot encode snapshot: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return fmt.Errorf("cannot write snapshot: %w", err)
+	}
+	if err := os.WriteFile(filename, out.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("cannot write snapshot: %w", err)
+	}
+	return nil
+}
+
+// keepPrevious replaces the values of a document selected by segments with the values at the same paths
+// in the previous snapshot, or with placeholder where it has none. Paths selecting nothing are left alone.
+func keepPrevious(doc, previous interface{}, hasPrevious bool, segments []pathSegment, placeholder string) interface{} {
+	if len(segments) == 0 {
+		if hasPrevious {
+			return previous
+		}
+		return placeholder
+	}
+
+	seg, rest := segments[0], segments[1:]
+	switch t := doc.(type) {
+	case map[string]interface{}:
+		previousObject, _ := previous.(map[string]interface{})
+		for key, child := range t {
+			if seg.wildcard || (!seg.isIndex && seg.key == key) {
+				p, ok := previousObject[key]
+				t[key] = keepPrevious(child, p, hasPrevious && ok, rest, placeholder)
+			}
+		}
+	case []interface{}:
+		previousArray, _ := previous.([]interface{})
+		for i, child := range t {
+			if seg.wildcard || (seg.isIndex && seg.index == i) {
+				var p interface{}
+				ok := i < len(previousArray)
+				if ok {
+					p = previousArray[i]
+				}
+				t[i] = keepPrevious(child, p, hasPrevious && ok, rest, placeholder)
+			}
+		}
+	}
+	return doc
+}
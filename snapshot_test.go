@@ -0,0 +1,165 @@
+package bodyguard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsSnapshotMatch(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return filename
+	}
+	user := write("user.json", `{
+		"id": "00000000-0000-0000-0000-000000000000",
+		"name": "jdoe",
+		"roles": ["admin"],
+		"orders": [{"total": 9.5, "created_at": "2024-01-01T00:00:00Z"}]
+	}`)
+	overrides := Overrides{"$.id": UUID(), "orders[*].created_at": Timestamp()}
+
+	tests := map[string]struct {
+		filename  string
+		body      string
+		overrides Overrides
+		wantErr   string
+	}{
+		"Pass": {
+			filename:  user,
+			body:      `{"id": "1b4e28ba-2fa1-11d2-883f-0016d3cca427", "name": "jdoe", "roles": ["admin"], "orders": [{"total": 9.5, "created_at": "2025-06-01T12:00:00Z"}]}`,
+			overrides: overrides,
+			wantErr:   "",
+		},
+		"Literal Mismatch": {
+			filename:  user,
+			body:      `{"id": "1b4e28ba-2fa1-11d2-883f-0016d3cca427", "name": "jdoe", "roles": ["member"], "orders": [{"total": 9.5, "created_at": "2025-06-01T12:00:00Z"}]}`,
+			overrides: overrides,
+			wantErr:   "at $.roles[0]: expected admin (string), got member (string)",
+		},
+		"Override Mismatch": {
+			filename:  user,
+			body:      `{"id": "1", "name": "jdoe", "roles": ["admin"], "orders": [{"total": 9.5, "created_at": "2025-06-01T12:00:00Z"}]}`,
+			overrides: overrides,
+			wantErr:   "at $.id: expected UUID, got \"1\"",
+		},
+		"Without Overrides": {
+			filename:  user,
+			body:      `{"id": "1b4e28ba-2fa1-11d2-883f-0016d3cca427", "name": "jdoe", "roles": ["admin"], "orders": []}`,
+			overrides: nil,
+			wantErr:   "at $.id: expected 00000000-0000-0000-0000-000000000000 (string), got 1b4e28ba-2fa1-11d2-883f-0016d3cca427 (string)",
+		},
+		"Missing Snapshot": {
+			filename: filepath.Join(dir, "missing.json"),
+			body:     `{}`,
+			wantErr:  "cannot read snapshot",
+		},
+		"Invalid Snapshot": {
+			filename: write("invalid.json", `{"id": `),
+			body:     `{}`,
+			wantErr:  "invalid snapshot " + filepath.Join(dir, "invalid.json"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := defaultEngine.isSnapshotMatch(tt.filename, tt.body, tt.overrides)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestUpdateSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	overrides := Overrides{"$.id": UUID(), "orders[*].created_at": Timestamp(), "$.nickname": Optional(String())}
+	body := `{"id": "1b4e28ba-2fa1-11d2-883f-0016d3cca427", "name": "jdoe", "total": 10.50, "orders": [` +
+		`{"total": 9.5, "created_at": "2025-06-01T12:00:00Z"}, {"total": 1, "created_at": "2025-06-02T12:00:00Z"}]}`
+
+	tests := map[string]struct {
+		previous string
+		want     string
+	}{
+		"New Snapshot": {
+			previous: "",
+			want: `{
+  "id": "<string(uuid)>",
+  "name": "jdoe",
+  "orders": [
+    {
+      "created_at": "<string(date-time)>",
+      "total": 9.5
+    },
+    {
+      "created_at": "<string(date-time)>",
+      "total": 1
+    }
+  ],
+  "total": 10.50
+}
+`,
+		},
+		"Existing Snapshot": {
+			previous: `{"id": "00000000-0000-0000-0000-000000000000", "name": "old", "orders": [{"created_at": "2024-01-01T00:00:00Z"}]}`,
+			want: `{
+  "id": "00000000-0000-0000-0000-000000000000",
+  "name": "jdoe",
+  "orders": [
+    {
+      "created_at": "2024-01-01T00:00:00Z",
+      "total": 9.5
+    },
+    {
+      "created_at": "<string(date-time)>",
+      "total": 1
+    }
+  ],
+  "total": 10.50
+}
+`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(dir, strings.ReplaceAll(name, " ", "_"), "user.json")
+			if tt.previous != "" {
+				if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filename, []byte(tt.previous), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			t.Setenv(UpdateSnapshotsVariable, "true")
+			AssertSnapshot(t, filename, body, overrides)
+			got, err := os.ReadFile(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Expected\n%s\ngot\n%s", tt.want, got)
+			}
+
+			t.Setenv(UpdateSnapshotsVariable, "")
+			if _, err := defaultEngine.isSnapshotMatch(filename, body, overrides); err != nil {
+				t.Errorf("Expected the updated snapshot to match, got %v", err)
+			}
+		})
+	}
+}
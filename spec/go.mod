@@ -0,0 +1,10 @@
+module github.com/dedalusj/bodyguard/spec
+
+go 1.25.5
+
+require (
+	github.com/dedalusj/bodyguard v0.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+replace github.com/dedalusj/bodyguard => ../
@@ -0,0 +1,359 @@
+// Package spec loads bodyguard expectations from declarative YAML or JSON files, so expectations can be
+// contributed as data by people who do not write Go:
+//
+//	type: object
+//	strict: true
+//	properties:
+//	  id: {matcher: uuid}
+//	  name: {type: string, minLength: 1, maxLength: 50}
+//	  age: {type: integer, minimum: 0, optional: true}
+//	  status: {enum: [active, pending]}
+//	  kind: user
+//	  manager:
+//	    nullable: true
+//	    type: object
+//	    properties:
+//	      id: {matcher: uuid}
+//	  tags:
+//	    type: array
+//	    items: {type: string}
+//
+// A mapping describes a value with the keys below, every one of which must match. Any other scalar is a
+// literal, and a sequence is an array whose elements match in order.
+//
+//	type                  object, array, string, integer, number, boolean, null or any
+//	matcher               uuid, email, url, timestamp, date, notnull, empty or nonempty
+//	const                 a literal value
+//	enum                  the strings the value may be
+//	pattern               a regular expression strings must match
+//	minLength, maxLength  the bounds of the length of strings
+//	minimum, maximum      the bounds of numbers
+//	properties            the values of the keys of an object
+//	strict                whether an object may only have the keys listed in properties
+//	items                 the value of every element of an array
+//	elements              the elements of an array, in order unless unordered is true
+//	unordered             whether the elements of an array may be in any order
+//	minItems, maxItems    the bounds of the length of arrays
+//	anyOf                 a list of values, one of which must match
+//	optional              whether an object key may be missing
+//	absent                whether an object key must be missing
+//	nullable              whether the value may also be null
+//
+// JSON is valid YAML, so spec files may also be written in JSON.
+package spec
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"slices"
+
+	"github.com/dedalusj/bodyguard"
+	"gopkg.in/yaml.v3"
+)
+
+// Load parses the spec file at path into an expectation.
+func Load(path string) (bodyguard.Matcher, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Parse parses a YAML or JSON spec into an expectation.
+func Parse(data []byte) (bodyguard.Matcher, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("empty spec")
+	}
+
+	expected, err := parseNode(doc.Content[0])
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := expected.(bodyguard.Matcher); ok {
+		return m, nil
+	}
+	return bodyguard.All(expected), nil
+}
+
+// parseNode returns the expectation described by a node.
+func parseNode(n *yaml.Node) (interface{}, error) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+
+	switch n.Kind {
+	case yaml.SequenceNode:
+		elements, err := parseNodes(n.Content)
+		if err != nil {
+			return nil, err
+		}
+		return bodyguard.Array(elements...), nil
+	case yaml.MappingNode:
+		return parseMapping(n)
+	}
+
+	var literal interface{}
+	if err := n.Decode(&literal); err != nil {
+		return nil, fmt.Errorf("line %d: %w", n.Line, err)
+	}
+	return literal, nil
+}
+
+func parseNodes(nodes []*yaml.Node) ([]interface{}, error) {
+	expectations := make([]interface{}, len(nodes))
+	for i, n := range nodes {
+		e, err := parseNode(n)
+		if err != nil {
+			return nil, err
+		}
+		expectations[i] = e
+	}
+	return expectations, nil
+}
+
+// mapping holds the keywords of a mapping node.
+type mapping struct {
+	value map[string]*yaml.Node
+}
+
+func (m *mapping) has(key string) bool {
+	_, ok := m.value[key]
+	return ok
+}
+
+// decode decodes the value of a keyword, when it is set.
+func (m *mapping) decode(key string, v interface{}) error {
+	n, ok := m.value[key]
+	if !ok {
+		return nil
+	}
+	if err := n.Decode(v); err != nil {
+		return fmt.Errorf("line %d: invalid %s: %w", n.Line, key, err)
+	}
+	return nil
+}
+
+// keywords lists the keys of a mapping node describing a value.
+var keywords = []string{
+	"type", "matcher", "const", "enum", "pattern", "minLength", "maxLength", "minimum", "maximum",
+	"properties", "strict", "items", "elements", "unordered", "minItems", "maxItems", "anyOf",
+	"optional", "absent", "nullable",
+}
+
+// parseMapping returns the expectation described by the keywords of a mapping node.
+func parseMapping(n *yaml.Node) (interface{}, error) {
+	m := &mapping{value: map[string]*yaml.Node{}}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key := n.Content[i].Value
+		if !slices.Contains(keywords, key) {
+			return nil, fmt.Errorf("line %d: unknown key %q", n.Content[i].Line, key)
+		}
+		m.value[key] = n.Content[i+1]
+	}
+
+	var absent, optional, nullable, strict, unordered bool
+	for _, flag := range []struct {
+		key   string
+		value *bool
+	}{{"absent", &absent}, {"optional", &optional}, {"nullable", &nullable}, {"strict", &strict}, {"unordered", &unordered}} {
+		if err := m.decode(flag.key, flag.value); err != nil {
+			return nil, err
+		}
+	}
+	if absent {
+		return bodyguard.Absent(), nil
+	}
+
+	var checks []interface{}
+	add := func(e interface{}) { checks = append(checks, e) }
+
+	var typ string
+	if err := m.decode("type", &typ); err != nil {
+		return nil, err
+	}
+	switch typ {
+	case "", "any":
+	case "object":
+		if !m.has("properties") {
+			add(bodyguard.MapOf(nil, bodyguard.Anything()))
+		}
+	case "array":
+		if !m.has("items") && !m.has("elements") {
+			add(bodyguard.Each(bodyguard.Anything()))
+		}
+	case "string":
+		add(bodyguard.String())
+	case "integer":
+		add(bodyguard.Integer())
+	case "number":
+		add(bodyguard.Number())
+	case "boolean":
+		add(bodyguard.Bool())
+	case "null":
+		add(bodyguard.Null())
+	default:
+		return nil, fmt.Errorf("line %d: unknown type %q", m.value["type"].Line, typ)
+	}
+
+	var name string
+	if err := m.decode("matcher", &name); err != nil {
+		return nil, err
+	}
+	if name != "" {
+		matcher, ok := namedMatchers[name]
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown matcher %q", m.value["matcher"].Line, name)
+		}
+		add(matcher())
+	}
+
+	if m.has("const") {
+		var literal interface{}
+		if err := m.decode("const", &literal); err != nil {
+			return nil, err
+		}
+		add(literal)
+	}
+	if m.has("enum") {
+		var options []string
+		if err := m.decode("enum", &options); err != nil {
+			return nil, err
+		}
+		add(bodyguard.OneOf(options...))
+	}
+	var pattern string
+	if err := m.decode("pattern", &pattern); err != nil {
+		return nil, err
+	}
+	if pattern != "" {
+		add(bodyguard.Regexp(pattern))
+	}
+
+	minLength, maxLength := 0, math.MaxInt
+	if err := decodeBounds(m, "minLength", &minLength, "maxLength", &maxLength); err != nil {
+		return nil, err
+	}
+	if m.has("minLength") || m.has("maxLength") {
+		add(bodyguard.StringLength(minLength, maxLength))
+	}
+	minimum, maximum := -math.MaxFloat64, math.MaxFloat64
+	if err := decodeBounds(m, "minimum", &minimum, "maximum", &maximum); err != nil {
+		return nil, err
+	}
+	if m.has("minimum") || m.has("maximum") {
+		add(bodyguard.NumberWithinRange(minimum, maximum))
+	}
+
+	if n, ok := m.value["properties"]; ok {
+		object, err := parseProperties(n, strict)
+		if err != nil {
+			return nil, err
+		}
+		add(object)
+	}
+
+	if n, ok := m.value["items"]; ok {
+		item, err := parseNode(n)
+		if err != nil {
+			return nil, err
+		}
+		add(bodyguard.Each(item))
+	}
+	if n, ok := m.value["elements"]; ok {
+		if n.Kind != yaml.SequenceNode {
+			return nil, fmt.Errorf("line %d: elements must be a list", n.Line)
+		}
+		elements, err := parseNodes(n.Content)
+		if err != nil {
+			return nil, err
+		}
+		if unordered {
+			add(bodyguard.UnorderedArray(elements...))
+		} else {
+			add(bodyguard.Array(elements...))
+		}
+	}
+	minItems, maxItems := 0, math.MaxInt
+	if err := decodeBounds(m, "minItems", &minItems, "maxItems", &maxItems); err != nil {
+		return nil, err
+	}
+	if m.has("minItems") || m.has("maxItems") {
+		add(bodyguard.ArrayLength(minItems, maxItems))
+	}
+
+	if n, ok := m.value["anyOf"]; ok {
+		if n.Kind != yaml.SequenceNode {
+			return nil, fmt.Errorf("line %d: anyOf must be a list", n.Line)
+		}
+		alternatives, err := parseNodes(n.Content)
+		if err != nil {
+			return nil, err
+		}
+		add(bodyguard.Any(alternatives...))
+	}
+
+	var e interface{} = bodyguard.Anything()
+	switch len(checks) {
+	case 0:
+	case 1:
+		e = checks[0]
+	default:
+		e = bodyguard.All(checks...)
+	}
+	if nullable {
+		e = bodyguard.Any(nil, e)
+	}
+	if optional {
+		e = bodyguard.Optional(e)
+	}
+	return e, nil
+}
+
+// parseProperties returns the Object described by the properties of a mapping node.
+func parseProperties(n *yaml.Node, strict bool) (*bodyguard.ObjectMatcher, error) {
+	if n.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: properties must be a mapping", n.Line)
+	}
+	properties := map[string]any{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		e, err := parseNode(n.Content[i+1])
+		if err != nil {
+			return nil, err
+		}
+		properties[n.Content[i].Value] = e
+	}
+	if strict {
+		return bodyguard.StrictObject(properties), nil
+	}
+	return bodyguard.Object(properties), nil
+}
+
+// decodeBounds decodes a pair of minimum and maximum keywords, leaving the defaults of those not set.
+func decodeBounds[T int | float64](m *mapping, minKey string, min *T, maxKey string, max *T) error {
+	if err := m.decode(minKey, min); err != nil {
+		return err
+	}
+	return m.decode(maxKey, max)
+}
+
+// namedMatchers maps the values of the matcher keyword to the matchers they stand for.
+var namedMatchers = map[string]func() bodyguard.Matcher{
+	"uuid":      bodyguard.UUID,
+	"email":     bodyguard.Email,
+	"url":       bodyguard.URL,
+	"timestamp": bodyguard.Timestamp,
+	"date":      bodyguard.Date,
+	"notnull":   bodyguard.NotNull,
+	"empty":     bodyguard.Empty,
+	"nonempty":  bodyguard.NotEmpty,
+}
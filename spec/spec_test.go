@@ -0,0 +1,171 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dedalusj/bodyguard"
+)
+
+const userSpec = `
+type: object
+strict: true
+properties:
+  id: {matcher: uuid}
+  name: {type: string, minLength: 1, maxLength: 10}
+  age: {type: integer, minimum: 0, maximum: 130, optional: true}
+  status: {enum: [active, pending]}
+  kind: user
+  password: {absent: true}
+  manager:
+    nullable: true
+    type: object
+    properties:
+      id: {matcher: uuid}
+  tags:
+    type: array
+    items: {type: string, pattern: "^[a-z]+$"}
+    minItems: 1
+  roles:
+    elements: [admin, member]
+    unordered: true
+  pair: [1, x]
+  score: {anyOf: [{type: "null"}, {type: number, maximum: 1}]}
+`
+
+const userBody = `{
+	"id": "1b4e28ba-2fa1-11d2-883f-0016d3cca427",
+	"name": "jdoe",
+	"status": "active",
+	"kind": "user",
+	"manager": null,
+	"tags": ["a", "b"],
+	"roles": ["member", "admin"],
+	"pair": [1, "x"],
+	"score": 0.5
+}`
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		spec    string
+		body    string
+		wantErr string
+	}{
+		"Pass": {
+			spec:    userSpec,
+			body:    userBody,
+			wantErr: "",
+		},
+		"JSON Spec Pass": {
+			spec:    `{"type": "object", "properties": {"id": {"matcher": "uuid"}, "kind": "user"}}`,
+			body:    userBody,
+			wantErr: "",
+		},
+		"Matcher Mismatch": {
+			spec:    userSpec,
+			body:    strings.Replace(userBody, `"1b4e28ba-2fa1-11d2-883f-0016d3cca427"`, `"1"`, 1),
+			wantErr: "at $.id: expected UUID, got \"1\"",
+		},
+		"Length Mismatch": {
+			spec:    userSpec,
+			body:    strings.Replace(userBody, `"jdoe"`, `"jdoe-the-second"`, 1),
+			wantErr: "at $.name: expected string length between 1 and 10, got 15",
+		},
+		"Optional Key Checked": {
+			spec:    userSpec,
+			body:    strings.Replace(userBody, `"kind": "user",`, `"kind": "user", "age": 200,`, 1),
+			wantErr: "at $.age: expected number within range 0 to 130, got 200",
+		},
+		"Literal Mismatch": {
+			spec:    userSpec,
+			body:    strings.Replace(userBody, `"kind": "user"`, `"kind": "admin"`, 1),
+			wantErr: "at $.kind: expected user (string), got admin (string)",
+		},
+		"Absent Key": {
+			spec:    userSpec,
+			body:    strings.Replace(userBody, `"kind": "user",`, `"kind": "user", "password": "x",`, 1),
+			wantErr: "at $: expected key \"password\" to be absent, got x",
+		},
+		"Item Mismatch": {
+			spec:    userSpec,
+			body:    strings.Replace(userBody, `["a", "b"]`, `["a", "B"]`, 1),
+			wantErr: "at $.tags[1]: expected to match \"^[a-z]+$\"",
+		},
+		"Extra Key": {
+			spec:    userSpec,
+			body:    strings.Replace(userBody, `"kind": "user",`, `"kind": "user", "role": "x",`, 1),
+			wantErr: "at $: unexpected key \"role\"",
+		},
+		"Unknown Key": {
+			spec:    "type: object\nproperties:\n  name: {type: string, minLen: 1}\n",
+			wantErr: "line 3: unknown key \"minLen\"",
+		},
+		"Unknown Type": {
+			spec:    "type: text\n",
+			wantErr: "line 1: unknown type \"text\"",
+		},
+		"Unknown Matcher": {
+			spec:    "matcher: ulid\n",
+			wantErr: "line 1: unknown matcher \"ulid\"",
+		},
+		"Invalid Bound": {
+			spec:    "minLength: short\n",
+			wantErr: "line 1: invalid minLength",
+		},
+		"Invalid YAML": {
+			spec:    "type: [object\n",
+			wantErr: "yaml: line 1: did not find expected ',' or ']'",
+		},
+		"Empty Spec": {
+			spec:    "",
+			wantErr: "empty spec",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			m, err := Parse([]byte(tt.spec))
+			if err == nil {
+				err = bodyguard.Evaluate(m, tt.body).Err
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "user.yaml")
+	if err := os.WriteFile(valid, []byte(userSpec), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(invalid, []byte("type: text\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Load(valid)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	bodyguard.Assert(t, m, userBody)
+
+	if _, err := Load(invalid); err == nil || err.Error() != invalid+": line 1: unknown type \"text\"" {
+		t.Errorf("Expected error naming the file, got %v", err)
+	}
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Errorf("Expected error for a missing file, got nil")
+	}
+}
@@ -0,0 +1,226 @@
+package bodyguard
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// matchState holds the document-level state of a single match run.
+// It is shared by every matcher of the expected tree, so matchers can relate values
+// found at different paths of the same document.
+type matchState struct {
+	beforeMatch func(path string, value interface{})
+	afterMatch  func(path string, value interface{}, err error)
+
+	unorderedArrays bool
+	strictObjects   bool
+	decimalNumbers  bool
+	epsilon         float64
+	timeLayouts     []string
+	environment     string
+
+	// ignored holds the segments of the paths skipped through Ignoring
+	ignored [][]string
+
+	classes map[string]classValue
+	vars    map[string]classValue
+	refs    []pendingRef
+
+	// captures store values into test variables once the whole match run has passed
+	captures []func()
+
+	// moves maps the path of each array element found at the wrong index to the path it was expected at.
+	// It is only tracked when rendering a diff.
+	moves map[string]string
+}
+
+// pendingRef is a SameAs reference seen before its value was captured.
+type pendingRef struct {
+	name  string
+	path  string
+	value interface{}
+}
+
+// classValue is the first value seen for a ConsistentValues class or a captured variable.
+type classValue struct {
+	path  string
+	value interface{}
+}
+
+func newMatchState() *matchState {
+	return &matchState{
+		environment: currentEnvironment(),
+		classes:     map[string]classValue{},
+		vars:        map[string]classValue{},
+	}
+}
+
+// newMatchState returns the state for a match run configured by the engine.
+func (e *Engine) newMatchState() *matchState {
+	st := newMatchState()
+	st.beforeMatch = e.BeforeMatch
+	st.afterMatch = e.AfterMatch
+	st.unorderedArrays = e.UnorderedArrays
+	st.strictObjects = e.StrictObjects
+	st.decimalNumbers = e.DecimalNumbers
+	st.epsilon = e.Epsilon
+	st.timeLayouts = e.TimeLayouts
+	if e.Environment != "" {
+		st.environment = e.Environment
+	}
+	if e.Diff {
+		st.moves = map[string]string{}
+	}
+	return st
+}
+
+// fork returns a copy of the state for probing an alternative match.
+// Changes to the copy only become visible once committed.
+func (st *matchState) fork() *matchState {
+	probe := *st
+	probe.classes = maps.Clone(st.classes)
+	probe.vars = maps.Clone(st.vars)
+	probe.refs = slices.Clone(st.refs)
+	probe.captures = slices.Clone(st.captures)
+	if st.moves != nil {
+		probe.moves = maps.Clone(st.moves)
+	}
+	return &probe
+}
+
+// commit adopts the state of a successful probe.
+func (st *matchState) commit(probe *matchState) {
+	st.classes = probe.classes
+	st.vars = probe.vars
+	st.refs = probe.refs
+	st.captures = probe.captures
+	st.moves = probe.moves
+}
+
+// matchRoot runs a complete match run, then checks every SameAs reference was resolved.
+// Values captured by CaptureAs are only stored once the run has passed.
+func matchRoot(st *matchState, expected interface{}, path string, actual interface{}) error {
+	if err := match(st, expected, path, actual); err != nil {
+		return err
+	}
+	if len(st.refs) > 0 {
+		ref := st.refs[0]
+		return fmt.Errorf("at %s: expected the value captured as %q, but none was captured", ref.path, ref.name)
+	}
+	for _, store := range st.captures {
+		store()
+	}
+	return nil
+}
+
+// statefulMatcher is implemented by matchers that take part in the document-level state of a match run.
+type statefulMatcher interface {
+	matchState(st *matchState, path string, value interface{}) error
+}
+
+var (
+	_ Matcher         = stateMatcherFunc(nil)
+	_ statefulMatcher = stateMatcherFunc(nil)
+)
+
+// stateMatcherFunc is the stateful counterpart of MatcherFunc.
+// When called through Match it runs with fresh state.
+type stateMatcherFunc func(st *matchState, path string, value interface{}) error
+
+func (m stateMatcherFunc) Match(path string, value interface{}) error {
+	return matchRoot(newMatchState(), m, path, value)
+}
+
+func (m stateMatcherFunc) matchState(st *matchState, path string, value interface{}) error {
+	return m(st, path, value)
+}
+
+// ConsistentValues asserts that every value captured under the same class within a document is equal,
+// e.g. every "tenant_id" in an aggregated response. The first value seen sets the expectation for the class.
+func ConsistentValues(class string) Matcher {
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		first, seen := st.classes[class]
+		if !seen {
+			st.classes[class] = classValue{path: path, value: value}
+			return nil
+		}
+
+		if !reflect.DeepEqual(first.value, value) {
+			return fmt.Errorf("at %s: expected %q value %v as at %s, got %v", path, class, first.value, first.path, value)
+		}
+		return nil
+	}), &schema{description: fmt.Sprintf("value equal to every other of class %q", class)})
+}
+
+// Capture captures the value under name so SameAs can refer to it elsewhere in the same document,
+// e.g. Capture("owner") at data.owner_id and SameAs("owner") at meta.requested_by.
+// Capturing the same name twice requires both values to be equal.
+func Capture(name string) Matcher {
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		if first, seen := st.vars[name]; seen {
+			if !reflect.DeepEqual(first.value, value) {
+				return fmt.Errorf("at %s: expected captured %q value %v as at %s, got %v", path, name, first.value, first.path, value)
+			}
+			return nil
+		}
+		st.vars[name] = classValue{path: path, value: value}
+
+		// resolve references matched before the value was captured
+		refs := st.refs[:0:0]
+		for _, ref := range st.refs {
+			if ref.name != name {
+				refs = append(refs, ref)
+				continue
+			}
+			if !reflect.DeepEqual(value, ref.value) {
+				return fmt.Errorf("at %s: expected %v, the value captured as %q at %s, got %v", ref.path, value, name, path, ref.value)
+			}
+		}
+		st.refs = refs
+		return nil
+	}), &schema{description: fmt.Sprintf("any value, captured as %q", name)})
+}
+
+// SameAs asserts that the value equals the value captured under name by Capture in the same document.
+// The order in which keys are matched does not matter: a reference matched before its capture is checked
+// once the value is captured, and fails the match if it never is.
+func SameAs(name string) Matcher {
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		captured, seen := st.vars[name]
+		if !seen {
+			st.refs = append(st.refs, pendingRef{name: name, path: path, value: value})
+			return nil
+		}
+
+		if !reflect.DeepEqual(captured.value, value) {
+			return fmt.Errorf("at %s: expected %v, the value captured as %q at %s, got %v", path, captured.value, name, captured.path, value)
+		}
+		return nil
+	}), &schema{description: fmt.Sprintf("value equal to the one captured as %q", name)})
+}
+
+// CaptureAs matches any value convertible to T and stores it in dest, so later test steps can use it,
+// e.g. the id of a resource created by a POST. Values are converted through encoding/json, so T may be
+// a string, number, slice, map or struct. dest is only written once the whole assertion has passed.
+func CaptureAs[T any](dest *T) Matcher {
+	return describe(stateMatcherFunc(func(st *matchState, path string, value interface{}) error {
+		var captured T
+		if v, ok := value.(T); ok {
+			captured = v
+		} else {
+			data, err := json.Marshal(value)
+			if err == nil {
+				err = json.Unmarshal(data, &captured)
+			}
+			if err != nil {
+				return fmt.Errorf("at %s: cannot capture %v as %T: %w", path, value, captured, err)
+			}
+		}
+
+		st.captures = append(st.captures, func() { *dest = captured })
+		return nil
+	}), &schema{description: "value captured as " + reflect.TypeFor[T]().String()})
+}
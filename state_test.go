@@ -0,0 +1,193 @@
+package bodyguard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConsistentValues(t *testing.T) {
+	tenant := ConsistentValues("tenant")
+
+	tests := map[string]struct {
+		body     string
+		expected interface{}
+		wantErr  string
+	}{
+		"Consistent Pass": {
+			body: `{"tenant_id": "t1", "data": [{"tenant_id": "t1"}, {"tenant_id": "t1"}]}`,
+			expected: Object(map[string]any{
+				"tenant_id": tenant,
+				"data": Array(
+					Object(map[string]any{"tenant_id": tenant}),
+					Object(map[string]any{"tenant_id": tenant}),
+				),
+			}),
+			wantErr: "",
+		},
+		"Inconsistent Fail": {
+			body: `{"data": [{"tenant_id": "t1"}, {"tenant_id": "t2"}]}`,
+			expected: Object(map[string]any{
+				"data": Array(
+					Object(map[string]any{"tenant_id": tenant}),
+					Object(map[string]any{"tenant_id": tenant}),
+				),
+			}),
+			wantErr: "at $.data[1].tenant_id: expected \"tenant\" value t1 as at $.data[0].tenant_id, got t2",
+		},
+		"Separate Classes Pass": {
+			body:     `["a", "b"]`,
+			expected: Array(ConsistentValues("first"), ConsistentValues("second")),
+			wantErr:  "",
+		},
+		"State Is Per Document": {
+			body:     `["a"]`,
+			expected: Array(tenant),
+			wantErr:  "",
+		},
+		"Failed Any Alternative Does Not Record": {
+			body:     `[["a", "b"], "c"]`,
+			expected: Array(Any(Array(tenant, Number()), Array(String(), String())), tenant),
+			wantErr:  "",
+		},
+		"Failed Not Probe Does Not Record": {
+			body:     `[["a", "x"], "b"]`,
+			expected: Array(Not(Array(tenant, Number())), tenant),
+			wantErr:  "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := isMatch(tt.body, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestCaptureSameAs(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected interface{}
+		wantErr  string
+	}{
+		"Same Value Pass": {
+			body: `{"data": {"owner_id": "u1"}, "meta": {"requested_by": "u1"}}`,
+			expected: Object(map[string]any{
+				"data": Object(map[string]any{"owner_id": Capture("owner")}),
+				"meta": Object(map[string]any{"requested_by": SameAs("owner")}),
+			}),
+			wantErr: "",
+		},
+		"Different Value Fail": {
+			body: `{"data": {"owner_id": "u1"}, "meta": {"requested_by": "u2"}}`,
+			expected: Object(map[string]any{
+				"data": Object(map[string]any{"owner_id": Capture("owner")}),
+				"meta": Object(map[string]any{"requested_by": SameAs("owner")}),
+			}),
+			wantErr: "at $.meta.requested_by: expected u1, the value captured as \"owner\" at $.data.owner_id, got u2",
+		},
+		"Capture Combined With Matcher": {
+			body:     `{"id": 7, "links": [{"id": 7}]}`,
+			expected: Object(map[string]any{"id": All(Integer(), Capture("id")), "links": Each(Object(map[string]any{"id": SameAs("id")}))}),
+			wantErr:  "",
+		},
+		"Nothing Captured": {
+			body:     `{"a": 1}`,
+			expected: Object(map[string]any{"a": SameAs("missing")}),
+			wantErr:  "at $.a: expected the value captured as \"missing\", but none was captured",
+		},
+		"Failed Probe Does Not Capture": {
+			body:     `{"a": 1, "b": 1}`,
+			expected: Object(map[string]any{"a": Any(All(Capture("x"), String()), Number()), "b": SameAs("x")}),
+			wantErr:  "but none was captured",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := isMatch(tt.body, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestCaptureAs(t *testing.T) {
+	var (
+		id    string
+		count int
+		tags  []string
+	)
+	err := isMatch(`{"id": "u-1", "count": 3, "tags": ["a", "b"]}`, Object(map[string]any{
+		"id":    All(String(), CaptureAs(&id)),
+		"count": CaptureAs(&count),
+		"tags":  CaptureAs(&tags),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "u-1" || count != 3 || len(tags) != 2 || tags[1] != "b" {
+		t.Errorf("Expected captured values, got %q, %d, %v", id, count, tags)
+	}
+
+	var failed string
+	err = isMatch(`{"id": "u-2", "name": "x"}`, Object(map[string]any{
+		"id":   CaptureAs(&failed),
+		"name": "jdoe",
+	}))
+	if err == nil {
+		t.Fatal("Expected mismatch")
+	}
+	if failed != "" {
+		t.Errorf("Expected nothing stored from a failed assertion, got %q", failed)
+	}
+
+	var n int
+	err = isMatch(`{"n": "seven"}`, Object(map[string]any{"n": CaptureAs(&n)}))
+	if err == nil || !strings.Contains(err.Error(), "at $.n: cannot capture seven as int") {
+		t.Errorf("Expected conversion error, got %v", err)
+	}
+}
+
+func TestCaptureInUnorderedArrays(t *testing.T) {
+	// NumberSmaller(1.5) first probes 1, which Capture already probed, so the pairing is revised
+	err := isMatch(`{"values": [1, 2], "winner": 2}`, Object(map[string]any{
+		"values": UnorderedArray(All(Number(), Capture("n")), NumberSmaller(1.5)),
+		"winner": SameAs("n"),
+	}))
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	var captured int
+	if err := isMatch(`[1, 2]`, ArraySuperset(CaptureAs(&captured), NumberSmaller(1.5))); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if captured != 2 {
+		t.Errorf("Expected the element paired with CaptureAs to be stored, got %d", captured)
+	}
+
+	err = isMatch(`[1, 2]`, ArraySubset(ConsistentValues("v"), ConsistentValues("v")))
+	if err == nil || !strings.Contains(err.Error(), "unexpected element 2") {
+		t.Errorf("Expected conflicting pairs to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package bodyguard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// StubServer is a test HTTP server that serves canned JSON responses per route and verifies
+// the incoming request bodies against expected structures. Mismatches are answered normally
+// and reported when the test finishes.
+type StubServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	routes   []*StubRoute
+	failures []error
+}
+
+// StubRoute is a route of a StubServer, configured with ExpectBody and Respond.
+type StubRoute struct {
+	method string
+	path   string
+
+	expected interface{}
+	status   int
+	body     interface{}
+}
+
+// NewStubServer starts a StubServer that is closed, and whose failures are reported, when the test finishes.
+func NewStubServer(t *testing.T) *StubServer {
+	t.Helper()
+	s := &StubServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(func() {
+		t.Helper()
+		s.Close()
+		if err := s.verify(); err != nil {
+			t.Error(err)
+		}
+	})
+	return s
+}
+
+// On registers a route for the given method and URL path. It responds 200 with no body until Respond is called.
+func (s *StubServer) On(method, path string) *StubRoute {
+	r := &StubRoute{method: method, path: path, status: http.StatusOK}
+	s.mu.Lock()
+	s.routes = append(s.routes, r)
+	s.mu.Unlock()
+	return r
+}
+
+// ExpectBody sets the structure every request body sent to the route must match.
+func (r *StubRoute) ExpectBody(expected interface{}) *StubRoute {
+	r.expected = expected
+	return r
+}
+
+// Respond sets the response of the route. A string or []byte body is written as is;
+// any other non-nil body is encoded as JSON.
+func (r *StubRoute) Respond(status int, body interface{}) *StubRoute {
+	r.status = status
+	r.body = body
+	return r
+}
+
+func (s *StubServer) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	route := s.route(req.Method, req.URL.Path)
+	if route == nil {
+		s.fail(fmt.Errorf("%s %s: unexpected request, no route registered", req.Method, req.URL.Path))
+		http.NotFound(w, req)
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		s.fail(fmt.Errorf("%s %s: cannot read request body: %w", req.Method, req.URL.Path, err))
+	} else if body, err = Decompress(req.Header.Get("Content-Encoding"), body); err != nil {
+		s.fail(fmt.Errorf("%s %s: cannot decode request body: %w", req.Method, req.URL.Path, err))
+	} else if route.expected != nil {
+		if err := defaultEngine.matchMessage(req.Header.Get("Content-Type"), body, route.expected); err != nil {
+			s.fail(fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, err))
+		}
+	}
+
+	response, err := responseBytes(route.body)
+	if err != nil {
+		s.fail(fmt.Errorf("%s %s: cannot encode response: %w", req.Method, req.URL.Path, err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(response) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(route.status)
+	_, _ = w.Write(response)
+}
+
+func (s *StubServer) route(method, path string) *StubRoute {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.routes {
+		if r.method == method && r.path == path {
+			return r
+		}
+	}
+	return nil
+}
+
+func (s *StubServer) fail(err error) {
+	s.mu.Lock()
+	s.failures = append(s.failures, err)
+	s.mu.Unlock()
+}
+
+// verify returns every failure recorded while serving requests.
+func (s *StubServer) verify() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return errors.Join(s.failures...)
+}
+
+func responseBytes(body interface{}) ([]byte, error) {
+	switch b := body.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(b), nil
+	case []byte:
+		return b, nil
+	default:
+		return json.Marshal(b)
+	}
+}
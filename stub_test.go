@@ -0,0 +1,108 @@
+package bodyguard
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStubServer(t *testing.T) {
+	s := NewStubServer(t)
+	s.On(http.MethodPost, "/users").
+		ExpectBody(Object(map[string]any{"name": String()})).
+		Respond(http.StatusCreated, map[string]any{"id": 1, "name": "jdoe"})
+
+	resp, err := http.Post(s.URL+"/users", "application/json", strings.NewReader(`{"name": "jdoe"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("Expected status 201, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", ct)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	Assert(t, Object(map[string]any{"id": 1, "name": "jdoe"}), body)
+}
+
+func TestStubServerFailures(t *testing.T) {
+	tests := map[string]struct {
+		method  string
+		path    string
+		body    string
+		wantErr string
+	}{
+		"Pass": {
+			method:  http.MethodPut,
+			path:    "/items/1",
+			body:    `{"qty": 2}`,
+			wantErr: "",
+		},
+		"Body Mismatch": {
+			method:  http.MethodPut,
+			path:    "/items/1",
+			body:    `{"qty": "2"}`,
+			wantErr: "PUT /items/1: at $.qty: expected number, got string",
+		},
+		"Form Body Pass": {
+			method:  http.MethodPost,
+			path:    "/items",
+			body:    `qty=2`,
+			wantErr: "",
+		},
+		"Form Body Mismatch": {
+			method:  http.MethodPost,
+			path:    "/items",
+			body:    `qty=two`,
+			wantErr: "POST /items: at $.qty: expected to match \"^[0-9]+$\", got \"two\"",
+		},
+		"Unexpected Route": {
+			method:  http.MethodDelete,
+			path:    "/items/1",
+			wantErr: "DELETE /items/1: unexpected request, no route registered",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := &StubServer{}
+			s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+			defer s.Close()
+			s.On(http.MethodPut, "/items/1").
+				ExpectBody(Object(map[string]any{"qty": Number()})).
+				Respond(http.StatusOK, `{"ok": true}`)
+			s.On(http.MethodPost, "/items").
+				ExpectBody(ByContentType(map[string]any{
+					"application/x-www-form-urlencoded": Object(map[string]any{"qty": Regexp(`^[0-9]+$`)}),
+				}))
+
+			req, _ := http.NewRequest(tt.method, s.URL+tt.path, strings.NewReader(tt.body))
+			if tt.method == http.MethodPost {
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			err = s.verify()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
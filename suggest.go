@@ -0,0 +1,70 @@
+package bodyguard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxSuggestionDistance is the largest edit distance for which a key is
+// considered a likely typo of a missing key.
+const maxSuggestionDistance = 2
+
+// missingKeyError reports a missing key, suggesting a similarly named key from
+// the actual object when one exists. Keys that are themselves expected are not
+// suggested since they are accounted for.
+func missingKeyError(path, key string, expected, actual map[string]any) error {
+	candidates := make([]string, 0, len(actual))
+	for k := range actual {
+		if _, isExpected := expected[k]; !isExpected {
+			candidates = append(candidates, k)
+		}
+	}
+
+	if suggestion, ok := suggestKey(key, candidates); ok {
+		return fmt.Errorf("at %s: missing key %q, did you mean %q?", path, key, suggestion)
+	}
+	return fmt.Errorf("at %s: missing key %q", path, key)
+}
+
+// suggestKey returns the candidate closest to key, if any is within
+// maxSuggestionDistance or differs from key only by case.
+func suggestKey(key string, candidates []string) (string, bool) {
+	sort.Strings(candidates)
+
+	best, bestDistance := "", maxSuggestionDistance+1
+	for _, c := range candidates {
+		if strings.EqualFold(c, key) {
+			return c, true
+		}
+		d := levenshtein(key, c)
+		if d < bestDistance && d < len(key) {
+			best, bestDistance = c, d
+		}
+	}
+	return best, best != ""
+}
+
+// levenshtein returns the edit distance between a and b counted in runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
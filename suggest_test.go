@@ -0,0 +1,49 @@
+package bodyguard
+
+import "testing"
+
+func TestLevenshtein(t *testing.T) {
+	tests := map[string]struct {
+		a, b string
+		want int
+	}{
+		"Equal":        {a: "createdAt", b: "createdAt", want: 0},
+		"Substitution": {a: "createdAt", b: "createdAs", want: 1},
+		"Insertion":    {a: "created", b: "createdAt", want: 2},
+		"Deletion":     {a: "createdAt", b: "creatdAt", want: 1},
+		"Empty":        {a: "", b: "abc", want: 3},
+		"Unicode":      {a: "naïve", b: "naive", want: 1},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := levenshtein(tt.a, tt.b); got != tt.want {
+				t.Errorf("Expected distance %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSuggestKey(t *testing.T) {
+	tests := map[string]struct {
+		key        string
+		candidates []string
+		want       string
+	}{
+		"Typo":            {key: "createdAt", candidates: []string{"id", "createAt"}, want: "createAt"},
+		"Case Difference": {key: "createdAt", candidates: []string{"created_at", "CreatedAt"}, want: "CreatedAt"},
+		"Closest Wins":    {key: "status", candidates: []string{"stat", "statuss"}, want: "statuss"},
+		"Too Far":         {key: "createdAt", candidates: []string{"updatedAt"}, want: ""},
+		"Short Key":       {key: "id", candidates: []string{"ab"}, want: ""},
+		"No Candidates":   {key: "id", candidates: nil, want: ""},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := suggestKey(tt.key, tt.candidates)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("Expected suggestion %q, got %q (ok=%v)", tt.want, got, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package bodyguard
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FromTaggedStruct returns an expectation for the JSON encoding of the struct type T, so DTOs annotated
+// once produce the assertions of every test. Keys follow the json tags of T, including omitempty, and
+// embedded structs are flattened as encoding/json does. The result is an Object: extra keys are ignored.
+//
+// Each field is checked by its type, e.g. String for a string, Integer for an int and Timestamp for a
+// time.Time; pointers, slices and maps may also be null. A bodyguard tag replaces the type check with
+// a comma-separated list of checks that must all match:
+//
+//	type User struct {
+//		ID    string   `json:"id" bodyguard:"uuid"`
+//		Email string   `json:"email" bodyguard:"email"`
+//		Age   int      `json:"age" bodyguard:"range=0:130"`
+//		Role  string   `json:"role" bodyguard:"oneof=admin|member"`
+//		Tags  []string `json:"tags" bodyguard:"length=1:10"`
+//		Slug  string   `json:"slug" bodyguard:"regexp=^[a-z-]+$"`
+//	}
+//
+// The checks are uuid, email, url, timestamp, date, string, integer, number, bool, notnull, nonempty,
+// anything, range=min:max, length=min:max (of a string, or of an array for slice fields), oneof=a|b|c
+// and regexp=pattern, which takes the rest of the tag. optional allows the key to be missing, nullable
+// allows null, and "-" leaves the field unchecked. A recursive field is only checked to be an object.
+// Invalid tags make the expectation fail every match, and ValidateExpected reports them.
+func FromTaggedStruct[T any]() Matcher {
+	typ := reflect.TypeFor[T]()
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return invalidMatcher{fmt.Errorf("expected struct type, got %s", typ)}
+	}
+	return structExpectation(typ, map[reflect.Type]bool{})
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	jsonNumberType    = reflect.TypeFor[json.Number]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// structExpectation returns the Object expected for the encoding of a struct type. inProgress holds the
+// struct types being built, to stop at recursive fields.
+func structExpectation(typ reflect.Type, inProgress map[reflect.Type]bool) *ObjectMatcher {
+	inProgress[typ] = true
+	defer delete(inProgress, typ)
+
+	expected := map[string]any{}
+	addFields(expected, typ, inProgress)
+	return Object(expected)
+}
+
+// addFields adds the expectations of the fields of a struct type, flattening embedded structs.
+func addFields(expected map[string]any, typ reflect.Type, inProgress map[reflect.Type]bool) {
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && options == "" {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			addFields(expected, fieldType, inProgress)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		tag, tagged := field.Tag.Lookup("bodyguard")
+		if tag == "-" {
+			continue
+		}
+		var e interface{}
+		if tagged {
+			e = taggedExpectation(field, tag)
+		} else {
+			e = typeExpectation(field.Type, inProgress)
+		}
+		if strings.Contains(","+options+",", ",omitempty,") || strings.Contains(","+options+",", ",omitzero,") {
+			e = Optional(e)
+		}
+		expected[name] = e
+	}
+}
+
+// typeExpectation returns the expectation inferred from a Go type.
+func typeExpectation(typ reflect.Type, inProgress map[reflect.Type]bool) interface{} {
+	switch {
+	case typ == timeType:
+		return Timestamp()
+	case typ == jsonNumberType:
+		return Number()
+	case typ == rawMessageType:
+		return Anything()
+	case typ.Implements(jsonMarshalerType) || reflect.PointerTo(typ).Implements(jsonMarshalerType):
+		// the encoding is up to the type
+		return Anything()
+	case typ.Implements(textMarshalerType) || reflect.PointerTo(typ).Implements(textMarshalerType):
+		return String()
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return String()
+	case reflect.Bool:
+		return Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Integer()
+	case reflect.Float32, reflect.Float64:
+		return Number()
+	case reflect.Interface:
+		return Anything()
+	case reflect.Pointer:
+		return Any(Null(), typeExpectation(typ.Elem(), inProgress))
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			// []byte is encoded as a base64 string
+			return Any(Null(), String())
+		}
+		return Any(Null(), Each(typeExpectation(typ.Elem(), inProgress)))
+	case reflect.Array:
+		return Each(typeExpectation(typ.Elem(), inProgress))
+	case reflect.Map:
+		return Any(Null(), MapOf(nil, typeExpectation(typ.Elem(), inProgress)))
+	case reflect.Struct:
+		if inProgress[typ] {
+			return Object(map[string]any{})
+		}
+		return structExpectation(typ, inProgress)
+	}
+	return invalidMatcher{fmt.Errorf("unsupported field type %s", typ)}
+}
+
+// taggedExpectation returns the expectation described by the bodyguard tag of a field.
+func taggedExpectation(field reflect.StructField, tag string) interface{} {
+	var checks []interface{}
+	optional, nullable := false, field.Type.Kind() == reflect.Pointer
+	for tag != "" {
+		var term string
+		if strings.HasPrefix(tag, "regexp=") {
+			term, tag = tag, ""
+		} else {
+			term, tag, _ = strings.Cut(tag, ",")
+		}
+		name, arg, _ := strings.Cut(strings.TrimSpace(term), "=")
+
+		var check interface{}
+		var err error
+		switch name {
+		case "uuid":
+			check = UUID()
+		case "email":
+			check = Email()
+		case "url":
+			check = URL()
+		case "timestamp":
+			check = Timestamp()
+		case "date":
+			check = Date()
+		case "string":
+			check = String()
+		case "integer":
+			check = Integer()
+		case "number":
+			check = Number()
+		case "bool":
+			check = Bool()
+		case "notnull":
+			check = NotNull()
+		case "nonempty":
+			check = NotEmpty()
+		case "anything":
+			check = Anything()
+		case "optional":
+			optional = true
+			continue
+		case "nullable":
+			nullable = true
+			continue
+		case "range":
+			var min, max float64
+			if min, max, err = parseTagRange(arg, strconv.ParseFloat); err == nil {
+				check = NumberWithinRange(min, max)
+			}
+		case "length":
+			var min, max int
+			if min, max, err = parseTagRange(arg, func(s string, _ int) (int, error) { return strconv.Atoi(s) }); err == nil {
+				check = StringLength(min, max)
+				if kind := field.Type.Kind(); kind == reflect.Slice || kind == reflect.Array {
+					check = ArrayLength(min, max)
+				}
+			}
+		case "oneof":
+			check = OneOf(strings.Split(arg, "|")...)
+		case "regexp":
+			check = Regexp(arg)
+		default:
+			err = fmt.Errorf("unknown check %q", name)
+		}
+		if err != nil {
+			return invalidMatcher{fmt.Errorf("invalid bodyguard tag on field %s: %w", field.Name, err)}
+		}
+		checks = append(checks, check)
+	}
+
+	var e interface{} = Anything()
+	switch len(checks) {
+	case 0:
+	case 1:
+		e = checks[0]
+	default:
+		e = All(checks...)
+	}
+	if nullable {
+		e = Any(Null(), e)
+	}
+	if optional {
+		e = Optional(e)
+	}
+	return e
+}
+
+// parseTagRange parses a min:max tag argument.
+func parseTagRange[T any](arg string, parse func(s string, bitSize int) (T, error)) (T, T, error) {
+	var zero T
+	minText, maxText, ok := strings.Cut(arg, ":")
+	if !ok {
+		return zero, zero, fmt.Errorf("expected min:max, got %q", arg)
+	}
+	min, err := parse(minText, 64)
+	if err != nil {
+		return zero, zero, fmt.Errorf("invalid minimum %q", minText)
+	}
+	max, err := parse(maxText, 64)
+	if err != nil {
+		return zero, zero, fmt.Errorf("invalid maximum %q", maxText)
+	}
+	return min, max, nil
+}
@@ -0,0 +1,150 @@
+package bodyguard
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type auditFields struct {
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+type taggedAccount struct {
+	ID      string            `json:"id" bodyguard:"uuid"`
+	Email   string            `json:"email" bodyguard:"email"`
+	Age     int               `json:"age" bodyguard:"range=0:130"`
+	Role    string            `json:"role" bodyguard:"oneof=admin|member"`
+	Slug    string            `json:"slug" bodyguard:"regexp=^[a-z]{1,3}(-[a-z]+)?$"`
+	Tags    []string          `json:"tags" bodyguard:"length=1:3"`
+	Nick    *string           `json:"nick" bodyguard:"length=2:20"`
+	Labels  map[string]string `json:"labels"`
+	Score   float64           `json:"score"`
+	Active  bool              `json:"active"`
+	Parent  *taggedAccount    `json:"parent"`
+	Secret  string            `json:"-"`
+	Skipped string            `json:"skipped" bodyguard:"-"`
+	auditFields
+}
+
+type badlyTagged struct {
+	Age int `json:"age" bodyguard:"range=10"`
+}
+
+func TestFromTaggedStruct(t *testing.T) {
+	account := `{
+		"id": "1b4e28ba-2fa1-11d2-883f-0016d3cca427",
+		"email": "jdoe@example.com",
+		"age": 42,
+		"role": "admin",
+		"slug": "jd-doe",
+		"tags": ["a"],
+		"nick": null,
+		"labels": {"team": "core"},
+		"score": 0.5,
+		"active": true,
+		"parent": {"id": "6fa459ea-ee8a-3ca4-894e-db77e160355e"},
+		"skipped": 7,
+		"created_at": "2024-01-02T15:04:05Z"
+	}`
+
+	tests := map[string]struct {
+		body     string
+		expected Matcher
+		wantErr  string
+	}{
+		"Pass": {
+			body:     account,
+			expected: FromTaggedStruct[taggedAccount](),
+			wantErr:  "",
+		},
+		"Pointer Type Pass": {
+			body:     account,
+			expected: FromTaggedStruct[*taggedAccount](),
+			wantErr:  "",
+		},
+		"UUID Tag": {
+			body:     strings.Replace(account, `"1b4e28ba-2fa1-11d2-883f-0016d3cca427"`, `"1"`, 1),
+			expected: FromTaggedStruct[taggedAccount](),
+			wantErr:  "at $.id: expected UUID, got \"1\"",
+		},
+		"Range Tag": {
+			body:     strings.Replace(account, `"age": 42`, `"age": 200`, 1),
+			expected: FromTaggedStruct[taggedAccount](),
+			wantErr:  "at $.age: expected number within range 0 to 130, got 200",
+		},
+		"OneOf Tag": {
+			body:     strings.Replace(account, `"admin"`, `"owner"`, 1),
+			expected: FromTaggedStruct[taggedAccount](),
+			wantErr:  "at $.role: expected one of",
+		},
+		"Regexp Tag With Comma": {
+			body:     strings.Replace(account, `"jd-doe"`, `"jdoe-x"`, 1),
+			expected: FromTaggedStruct[taggedAccount](),
+			wantErr:  "at $.slug: expected to match",
+		},
+		"Array Length Tag": {
+			body:     strings.Replace(account, `["a"]`, `[]`, 1),
+			expected: FromTaggedStruct[taggedAccount](),
+			wantErr:  "at $.tags: expected array length between 1 and 3, got 0",
+		},
+		"Nullable Pointer Tag": {
+			body:     strings.Replace(account, `"nick": null`, `"nick": "j"`, 1),
+			expected: FromTaggedStruct[taggedAccount](),
+			wantErr:  "at $.nick: expected string length between 2 and 20, got 1",
+		},
+		"Inferred Type": {
+			body:     strings.Replace(account, `"active": true`, `"active": "yes"`, 1),
+			expected: FromTaggedStruct[taggedAccount](),
+			wantErr:  "at $.active: expected boolean",
+		},
+		"Inferred Map": {
+			body:     strings.Replace(account, `{"team": "core"}`, `{"team": 1}`, 1),
+			expected: FromTaggedStruct[taggedAccount](),
+			wantErr:  "at $.labels.team: expected string",
+		},
+		"Recursive Field": {
+			body:     strings.Replace(account, `{"id": "6fa459ea-ee8a-3ca4-894e-db77e160355e"}`, `[]`, 1),
+			expected: FromTaggedStruct[taggedAccount](),
+			wantErr:  "at $.parent: expected any of 2 alternatives to match",
+		},
+		"Embedded Field": {
+			body:     strings.Replace(account, `"2024-01-02T15:04:05Z"`, `"2024-01-02"`, 1),
+			expected: FromTaggedStruct[taggedAccount](),
+			wantErr:  "at $.created_at: ",
+		},
+		"Missing Key": {
+			body:     strings.Replace(account, `"score": 0.5,`, ``, 1),
+			expected: FromTaggedStruct[taggedAccount](),
+			wantErr:  "at $: missing key \"score\"",
+		},
+		"Invalid Tag": {
+			body:     `{"age": 20}`,
+			expected: FromTaggedStruct[badlyTagged](),
+			wantErr:  "at $.age: invalid bodyguard tag on field Age: expected min:max, got \"10\"",
+		},
+		"Not A Struct": {
+			body:     `[]`,
+			expected: FromTaggedStruct[[]string](),
+			wantErr:  "at $: expected struct type, got []string",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := isMatch(tt.body, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package bodyguard
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// Vars holds the values of template placeholders, keyed by name.
+type Vars map[string]interface{}
+
+// placeholderRegex matches a ${name} placeholder.
+var placeholderRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_.-]*)\}`)
+
+// templateMatcher is returned by Template.
+type templateMatcher struct {
+	document interface{}
+	err      error
+	vars     Vars
+}
+
+// Template returns an expectation built from a JSON document, given as a string or []byte, containing
+// ${name} placeholders, so one fixture can serve many parameterized cases. Objects must match exactly,
+// as with StrictObject, and arrays in order.
+//
+// A string that is exactly "${name}" is replaced by vars[name], which may be a literal or a matcher.
+// Placeholders inside longer strings, e.g. "/users/${id}", are replaced by the text of the value.
+// A whole-string placeholder without a value captures the actual value instead: every occurrence must be
+// equal, and once the whole assertion passes, the value is stored in vars for use by later assertions.
+// Placeholders are resolved at match time, so values captured by one template can be used by the next.
+func Template(tmpl interface{}, vars Vars) Matcher {
+	m := &templateMatcher{vars: vars}
+	data, err := bodyBytes(tmpl)
+	if err != nil {
+		m.err = err
+	} else if err := json.Unmarshal(data, &m.document); err != nil {
+		m.err = err
+	}
+	return m
+}
+
+func (m *templateMatcher) Match(path string, value interface{}) error {
+	return matchRoot(newMatchState(), m, path, value)
+}
+
+func (m *templateMatcher) matchState(st *matchState, path string, value interface{}) error {
+	if m.err != nil {
+		return fmt.Errorf("invalid template: %w", m.err)
+	}
+
+	var captured []string
+	expected, err := m.expand(m.document, &captured)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+
+	if err := match(st, expected, path, value); err != nil {
+		return err
+	}
+
+	// vars are only written once the whole match run has passed, as CaptureAs does
+	if m.vars != nil {
+		for _, name := range captured {
+			value := st.vars[name].value
+			st.captures = append(st.captures, func() { m.vars[name] = value })
+		}
+	}
+	return nil
+}
+
+// expand substitutes the placeholders of a decoded template, recording the names of captured variables.
+func (m *templateMatcher) expand(v interface{}, captured *[]string) (interface{}, error) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		expected := make(map[string]any, len(t))
+		for key, child := range t {
+			e, err := m.expand(child, captured)
+			if err != nil {
+				return nil, err
+			}
+			expected[key] = e
+		}
+		return StrictObject(expected), nil
+	case []interface{}:
+		elements := make([]interface{}, len(t))
+		for i, child := range t {
+			e, err := m.expand(child, captured)
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = e
+		}
+		return Array(elements...), nil
+	case string:
+		return m.expandString(t, captured)
+	default:
+		return v, nil
+	}
+}
+
+func (m *templateMatcher) expandString(s string, captured *[]string) (interface{}, error) {
+	if loc := placeholderRegex.FindStringSubmatchIndex(s); loc != nil && loc[0] == 0 && loc[1] == len(s) {
+		name := s[loc[2]:loc[3]]
+		if value, ok := m.vars[name]; ok {
+			return value, nil
+		}
+		*captured = append(*captured, name)
+		return Capture(name), nil
+	}
+
+	var err error
+	expanded := placeholderRegex.ReplaceAllStringFunc(s, func(placeholder string) string {
+		name := placeholderRegex.FindStringSubmatch(placeholder)[1]
+		value, ok := m.vars[name]
+		if !ok {
+			err = fmt.Errorf("no value for placeholder ${%s} in %q", name, s)
+			return placeholder
+		}
+		return fmt.Sprint(value)
+	})
+	return expanded, err
+}
@@ -0,0 +1,113 @@
+package bodyguard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTemplate(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected interface{}
+		wantErr  string
+	}{
+		"Literal Vars Pass": {
+			body:     `{"id": 42, "name": "jdoe", "href": "/users/42"}`,
+			expected: Template(`{"id": "${id}", "name": "${name}", "href": "/users/${id}"}`, Vars{"id": 42, "name": "jdoe"}),
+			wantErr:  "",
+		},
+		"Matcher Var Pass": {
+			body:     `{"id": "0b3c2a1e-8f4d-4b6a-9c2e-1a2b3c4d5e6f", "tags": ["a"]}`,
+			expected: Template(`{"id": "${id}", "tags": ["a"]}`, Vars{"id": UUID()}),
+			wantErr:  "",
+		},
+		"Value Mismatch": {
+			body:     `{"user": {"name": "jdoe"}}`,
+			expected: Template(`{"user": {"name": "${name}"}}`, Vars{"name": "other"}),
+			wantErr:  "at $.user.name: expected other (string), got jdoe (string)",
+		},
+		"Strict Objects": {
+			body:     `{"a": 1, "b": 2}`,
+			expected: Template(`{"a": 1}`, nil),
+			wantErr:  "at $: unexpected key \"b\"",
+		},
+		"Inconsistent Capture": {
+			body:     `{"id": 1, "parent_id": 2}`,
+			expected: Template(`{"id": "${id}", "parent_id": "${id}"}`, nil),
+			wantErr:  "expected captured \"id\" value",
+		},
+		"Unbound Embedded Placeholder": {
+			body:     `{"href": "/users/1"}`,
+			expected: Template(`{"href": "/users/${id}"}`, nil),
+			wantErr:  "invalid template: no value for placeholder ${id} in \"/users/${id}\"",
+		},
+		"Invalid Template": {
+			body:     `{}`,
+			expected: Template(`{"a": `, nil),
+			wantErr:  "invalid template",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := isMatch(tt.body, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestTemplateCapture(t *testing.T) {
+	vars := Vars{}
+	if err := isMatch(`{"id": "u-1", "name": "jdoe"}`, Template(`{"id": "${id}", "name": "jdoe"}`, vars)); err != nil {
+		t.Fatal(err)
+	}
+	if vars["id"] != "u-1" {
+		t.Fatalf("Expected id to be captured, got %v", vars)
+	}
+
+	if err := isMatch(`{"href": "/users/u-1"}`, Template(`{"href": "/users/${id}"}`, vars)); err != nil {
+		t.Errorf("Expected captured value to be substituted, got %v", err)
+	}
+
+	failed := Vars{}
+	if err := isMatch(`{"id": "u-2", "name": "x"}`, Template(`{"id": "${id}", "name": "jdoe"}`, failed)); err == nil {
+		t.Fatal("Expected mismatch")
+	}
+	if len(failed) != 0 {
+		t.Errorf("Expected nothing captured from a failed match, got %v", failed)
+	}
+
+	// the template matches, but the assertion fails elsewhere
+	err := isMatch(`{"user": {"id": "u-3"}, "status": "failed"}`, Object(map[string]any{
+		"user":   Template(`{"id": "${id}"}`, failed),
+		"status": "ok",
+	}))
+	if err == nil {
+		t.Fatal("Expected mismatch")
+	}
+	if len(failed) != 0 {
+		t.Errorf("Expected nothing captured from a failed assertion, got %v", failed)
+	}
+
+	// the template matches in a probe of Any that is discarded
+	err = isMatch(`{"id": "u-4"}`, Any(
+		All(Template(`{"id": "${id}"}`, failed), Null()),
+		Object(map[string]any{"id": String()}),
+	))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(failed) != 0 {
+		t.Errorf("Expected nothing captured from a discarded probe, got %v", failed)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/dedalusj/bodyguard/tomlassert
+
+go 1.25.5
+
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/dedalusj/bodyguard v0.0.0
+)
+
+replace github.com/dedalusj/bodyguard => ../
@@ -0,0 +1,71 @@
+// Package tomlassert checks TOML documents, such as configuration payloads, with bodyguard matchers.
+package tomlassert
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/dedalusj/bodyguard"
+)
+
+// Assert checks that the TOML document body (as a string or []byte) matches the expected structure.
+// Tables become objects and the document is matched as for bodyguard.Assert, so every matcher applies.
+// Options adjust the behaviour of this assertion only. It fails the test if there is a mismatch.
+func Assert(t *testing.T, expected interface{}, body interface{}, opts ...bodyguard.Option) {
+	t.Helper()
+	engine := &bodyguard.Engine{Unmarshal: Unmarshal}
+	engine.Assert(t, expected, body, opts...)
+}
+
+// Unmarshal decodes a TOML document into the generic values of encoding/json, for use as the
+// Unmarshal function of a bodyguard.Engine. Tables become objects and numbers float64. Offset date-times
+// become RFC 3339 strings, so Timestamp matches them, and local dates, times and date-times become
+// strings in their TOML form, e.g. "2024-05-01", so Date matches local dates.
+func Unmarshal(data []byte, v interface{}) error {
+	var document map[string]interface{}
+	if err := toml.Unmarshal(data, &document); err != nil {
+		return err
+	}
+	generic, err := json.Marshal(normalize(document))
+	if err != nil {
+		return fmt.Errorf("cannot convert TOML document: %w", err)
+	}
+	return json.Unmarshal(generic, v)
+}
+
+// normalize converts the values decoded by the toml package to values encoding/json renders as intended.
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = normalize(child)
+		}
+		return v
+	case []map[string]interface{}:
+		list := make([]interface{}, len(v))
+		for i, child := range v {
+			list[i] = normalize(child)
+		}
+		return list
+	case []interface{}:
+		for i, child := range v {
+			v[i] = normalize(child)
+		}
+		return v
+	case time.Time:
+		// the toml package marks local values with zones of these names
+		switch v.Location().String() {
+		case "date-local":
+			return v.Format(time.DateOnly)
+		case "time-local":
+			return v.Format("15:04:05.999999999")
+		case "datetime-local":
+			return v.Format("2006-01-02T15:04:05.999999999")
+		}
+		return v.Format(time.RFC3339Nano)
+	}
+	return value
+}
@@ -0,0 +1,80 @@
+package tomlassert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dedalusj/bodyguard"
+)
+
+const config = `
+title = "service"
+replicas = 3
+ratio = 0.5
+debug = false
+tags = ["a", "b"]
+released = 2024-05-01T10:00:00Z
+maintenance = 2024-06-01
+window = 02:30:00
+
+[database]
+host = "db.internal"
+port = 5432
+
+[[endpoints]]
+path = "/users"
+
+[[endpoints]]
+path = "/orders"
+`
+
+func TestAssert(t *testing.T) {
+	Assert(t, bodyguard.Object(map[string]any{
+		"title":       "service",
+		"replicas":    bodyguard.Integer(),
+		"ratio":       0.5,
+		"debug":       false,
+		"tags":        []any{"a", "b"},
+		"released":    bodyguard.Timestamp(),
+		"maintenance": bodyguard.Date(),
+		"window":      "02:30:00",
+		"database":    bodyguard.StrictObject(map[string]any{"host": bodyguard.String(), "port": 5432}),
+		"endpoints":   bodyguard.Each(bodyguard.Object(map[string]any{"path": bodyguard.Regexp(`^/`)})),
+	}), config)
+}
+
+func TestUnmarshalMismatch(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected interface{}
+		wantErr  string
+	}{
+		"Value Mismatch": {
+			body:     config,
+			expected: bodyguard.Object(map[string]any{"database": bodyguard.Object(map[string]any{"port": 5433})}),
+			wantErr:  "at $.database.port: expected 5433 (int), got 5432 (float64)",
+		},
+		"Array Of Tables": {
+			body:     config,
+			expected: bodyguard.Object(map[string]any{"endpoints": bodyguard.ArrayLength(3, 3)}),
+			wantErr:  "at $.endpoints: expected array length between 3 and 3, got 2",
+		},
+		"Invalid TOML": {
+			body:     `title = `,
+			expected: bodyguard.Anything(),
+			wantErr:  "toml:",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			engine := &bodyguard.Engine{Unmarshal: Unmarshal}
+			err := engine.Evaluate(tt.expected, tt.body).Err
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
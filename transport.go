@@ -0,0 +1,289 @@
+package bodyguard
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var (
+	_ http.RoundTripper = (*RecordingTransport)(nil)
+	_ http.RoundTripper = (*AssertingTransport)(nil)
+)
+
+// RecordedRequest is an outbound request captured by a RecordingTransport.
+type RecordedRequest struct {
+	Method string
+	URL    string
+	Body   []byte
+}
+
+// RecordingTransport is an http.RoundTripper that records the body of every outbound request,
+// so client code can be verified to send correctly shaped JSON.
+// The zero value is ready to use and is safe for concurrent use.
+type RecordingTransport struct {
+	// Transport performs the actual round trip. When nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	mu       sync.Mutex
+	requests []RecordedRequest
+}
+
+// RoundTrip records the request body and forwards the request to the underlying transport.
+func (rt *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("cannot read request body: %w", err)
+		}
+
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	rt.mu.Lock()
+	rt.requests = append(rt.requests, RecordedRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Body:   body,
+	})
+	rt.mu.Unlock()
+
+	transport := rt.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return transport.RoundTrip(req)
+}
+
+// Requests returns the requests recorded so far, in the order they were sent.
+func (rt *RecordingTransport) Requests() []RecordedRequest {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return append([]RecordedRequest(nil), rt.requests...)
+}
+
+// AssertRequest checks that the body of the i-th recorded request matches the expected structure.
+func (rt *RecordingTransport) AssertRequest(t *testing.T, i int, expected interface{}) {
+	t.Helper()
+	if err := rt.isRequestMatch(i, expected); err != nil {
+		t.Error(err)
+	}
+}
+
+// AssertRequestCount checks that exactly n requests have been recorded.
+func (rt *RecordingTransport) AssertRequestCount(t *testing.T, n int) {
+	t.Helper()
+	if got := len(rt.Requests()); got != n {
+		t.Errorf("expected %d recorded requests, got %d", n, got)
+	}
+}
+
+// AssertRequests checks that the recorded requests match the expected bodies, in order and in number.
+func (rt *RecordingTransport) AssertRequests(t *testing.T, expected ...interface{}) {
+	t.Helper()
+	if got := len(rt.Requests()); got != len(expected) {
+		t.Errorf("expected %d recorded requests, got %d", len(expected), got)
+		return
+	}
+	for i, e := range expected {
+		if err := rt.isRequestMatch(i, e); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func (rt *RecordingTransport) isRequestMatch(i int, expected interface{}) error {
+	requests := rt.Requests()
+	if i < 0 || i >= len(requests) {
+		return fmt.Errorf("expected request at index %d, got %d recorded requests", i, len(requests))
+	}
+
+	req := requests[i]
+	if err := isMatch(req.Body, expected); err != nil {
+		return fmt.Errorf("request %d (%s %s): %w", i, req.Method, req.URL, err)
+	}
+	return nil
+}
+
+// AssertingTransport is an http.RoundTripper that checks the bodies of requests sent by client code, and of
+// the responses it receives, against expectations registered per method and URL, for lightweight contract
+// verification without a mock server. Requests are forwarded to the underlying transport.
+// Mismatches fail the test when it finishes. It is safe for concurrent use.
+type AssertingTransport struct {
+	// Transport performs the actual round trip. When nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	mu        sync.Mutex
+	routes    []*TransportRoute
+	exchanges []RecordedExchange
+	failures  []error
+}
+
+// RecordedExchange is a request and its response captured by an AssertingTransport.
+type RecordedExchange struct {
+	Method       string
+	URL          string
+	RequestBody  []byte
+	Status       int
+	ResponseBody []byte
+}
+
+// TransportRoute holds the expectations of an AssertingTransport for one method and URL.
+// Expectations may be set while requests are in flight.
+type TransportRoute struct {
+	transport *AssertingTransport
+
+	method   string
+	url      string
+	request  interface{}
+	response interface{}
+}
+
+// NewAssertingTransport returns an AssertingTransport forwarding to transport, or http.DefaultTransport
+// when nil, that reports every mismatch when the test finishes.
+func NewAssertingTransport(t *testing.T, transport http.RoundTripper) *AssertingTransport {
+	t.Helper()
+	rt := &AssertingTransport{Transport: transport}
+	t.Cleanup(func() {
+		t.Helper()
+		if err := rt.verify(); err != nil {
+			t.Error(err)
+		}
+	})
+	return rt
+}
+
+// On registers expectations for requests with the given method and URL. A URL starting with "/" is
+// compared with the request path only, any other URL with the request URL without its query.
+func (rt *AssertingTransport) On(method, url string) *TransportRoute {
+	r := &TransportRoute{transport: rt, method: method, url: url}
+	rt.mu.Lock()
+	rt.routes = append(rt.routes, r)
+	rt.mu.Unlock()
+	return r
+}
+
+// ExpectRequest sets the structure the body of every request sent to the route must match.
+func (r *TransportRoute) ExpectRequest(expected interface{}) *TransportRoute {
+	r.transport.mu.Lock()
+	r.request = expected
+	r.transport.mu.Unlock()
+	return r
+}
+
+// ExpectResponse sets the structure the body of every response received from the route must match.
+func (r *TransportRoute) ExpectResponse(expected interface{}) *TransportRoute {
+	r.transport.mu.Lock()
+	r.response = expected
+	r.transport.mu.Unlock()
+	return r
+}
+
+// RoundTrip records and checks the request body, forwards the request, then records and checks the response body.
+func (rt *AssertingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("cannot read request body: %w", err)
+		}
+
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	label := fmt.Sprintf("%s %s", req.Method, req.URL)
+	expectedRequest, expectedResponse := rt.expectations(req)
+	if expectedRequest != nil {
+		if body, err := Decompress(req.Header.Get("Content-Encoding"), reqBody); err != nil {
+			rt.fail(fmt.Errorf("%s: cannot decode request body: %w", label, err))
+		} else if err := defaultEngine.matchMessage(req.Header.Get("Content-Type"), body, expectedRequest); err != nil {
+			rt.fail(fmt.Errorf("%s: request: %w", label, err))
+		}
+	}
+
+	transport := rt.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	if expectedResponse != nil {
+		if body, err := Decompress(resp.Header.Get("Content-Encoding"), respBody); err != nil {
+			rt.fail(fmt.Errorf("%s: cannot decode response body: %w", label, err))
+		} else if err := defaultEngine.matchMessage(resp.Header.Get("Content-Type"), body, expectedResponse); err != nil {
+			rt.fail(fmt.Errorf("%s: response: %w", label, err))
+		}
+	}
+
+	rt.mu.Lock()
+	rt.exchanges = append(rt.exchanges, RecordedExchange{
+		Method:       req.Method,
+		URL:          req.URL.String(),
+		RequestBody:  reqBody,
+		Status:       resp.StatusCode,
+		ResponseBody: respBody,
+	})
+	rt.mu.Unlock()
+	return resp, nil
+}
+
+// Exchanges returns the requests and responses recorded so far, in the order the requests were sent.
+func (rt *AssertingTransport) Exchanges() []RecordedExchange {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return append([]RecordedExchange(nil), rt.exchanges...)
+}
+
+// expectations returns the request and response expectations of the route of req, nil when unset.
+func (rt *AssertingTransport) expectations(req *http.Request) (request, response interface{}) {
+	u := *req.URL
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	for _, r := range rt.routes {
+		if r.method != req.Method {
+			continue
+		}
+		if (strings.HasPrefix(r.url, "/") && r.url == u.Path) || r.url == u.String() {
+			return r.request, r.response
+		}
+	}
+	return nil, nil
+}
+
+func (rt *AssertingTransport) fail(err error) {
+	rt.mu.Lock()
+	rt.failures = append(rt.failures, err)
+	rt.mu.Unlock()
+}
+
+// verify returns every mismatch recorded so far.
+func (rt *AssertingTransport) verify() error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return errors.Join(rt.failures...)
+}
@@ -0,0 +1,205 @@
+package bodyguard
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRecordingTransport(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		received = append(received, string(b))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	rt := &RecordingTransport{}
+	client := &http.Client{Transport: rt}
+	for _, body := range []string{`{"name": "first"}`, `{"name": "second", "tags": ["a"]}`} {
+		resp, err := client.Post(server.URL+"/items", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	if len(received) != 2 || received[1] != `{"name": "second", "tags": ["a"]}` {
+		t.Fatalf("Expected bodies to be forwarded unchanged, got %q", received)
+	}
+
+	tests := map[string]struct {
+		index    int
+		expected interface{}
+		wantErr  string
+	}{
+		"Pass": {
+			index:    1,
+			expected: Object(map[string]any{"name": "second", "tags": Array("a")}),
+			wantErr:  "",
+		},
+		"Mismatch": {
+			index:    0,
+			expected: Object(map[string]any{"name": "second"}),
+			wantErr:  "request 0 (POST " + server.URL + "/items): at $.name: expected second (string), got first (string)",
+		},
+		"Out Of Range": {
+			index:    2,
+			expected: Object(map[string]any{}),
+			wantErr:  "expected request at index 2, got 2 recorded requests",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := rt.isRequestMatch(tt.index, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+
+	rt.AssertRequestCount(t, 2)
+	rt.AssertRequests(t,
+		Object(map[string]any{"name": "first"}),
+		Object(map[string]any{"name": "second"}),
+	)
+
+	resp, err := client.Get(server.URL + "/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	rt.AssertRequest(t, 2, EmptyBody())
+}
+
+func TestAssertingTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	tests := map[string]struct {
+		url     string
+		body    string
+		wantErr string
+	}{
+		"Pass": {
+			url:     "/users",
+			body:    `{"name": "jdoe"}`,
+			wantErr: "",
+		},
+		"Full URL Pass": {
+			url:     server.URL + "/users",
+			body:    `{"name": "jdoe"}`,
+			wantErr: "",
+		},
+		"Request Mismatch": {
+			url:     "/users",
+			body:    `{"name": 1}`,
+			wantErr: "POST " + server.URL + "/users?page=1: request: at $.name: expected string, got float64",
+		},
+		"Unregistered Route Pass": {
+			url:     "/other",
+			body:    `{"name": 1}`,
+			wantErr: "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rt := &AssertingTransport{}
+			rt.On(http.MethodPost, tt.url).
+				ExpectRequest(Object(map[string]any{"name": String()})).
+				ExpectResponse(Object(map[string]any{"id": Integer()}))
+			client := &http.Client{Transport: rt}
+
+			resp, err := client.Post(server.URL+"/users?page=1", "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if string(body) != `{"id": 1}` {
+				t.Errorf("Expected the response body to reach the client, got %q", body)
+			}
+
+			exchanges := rt.Exchanges()
+			if len(exchanges) != 1 || string(exchanges[0].RequestBody) != tt.body || string(exchanges[0].ResponseBody) != `{"id": 1}` || exchanges[0].Status != http.StatusOK {
+				t.Errorf("Expected the exchange to be recorded, got %+v", exchanges)
+			}
+
+			err = rt.verify()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+
+	rt := &AssertingTransport{}
+	rt.On(http.MethodGet, "/users").ExpectResponse(Object(map[string]any{"id": String()}))
+	resp, err := (&http.Client{Transport: rt}).Get(server.URL + "/users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if err := rt.verify(); err == nil || !strings.Contains(err.Error(), "GET "+server.URL+"/users: response: at $.id: expected string, got float64") {
+		t.Errorf("Expected a response mismatch, got %v", err)
+	}
+}
+
+func TestAssertingTransportConcurrentExpectations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	rt := &AssertingTransport{}
+	route := rt.On(http.MethodGet, "/users")
+	client := &http.Client{Transport: rt}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				resp, err := client.Get(server.URL + "/users")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				resp.Body.Close()
+			}
+		}()
+	}
+	for range 10 {
+		route.ExpectRequest(nil).ExpectResponse(Object(map[string]any{"id": Integer()}))
+	}
+	wg.Wait()
+
+	if err := rt.verify(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
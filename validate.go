@@ -0,0 +1,111 @@
+package bodyguard
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// errNilMatcher is reported for a nil Matcher used as an expectation.
+var errNilMatcher = errors.New("nil Matcher")
+
+// invalidMatcher stands in for an expectation that can never be matched. It fails every match and
+// ValidateExpected reports it before matching.
+type invalidMatcher struct {
+	err error
+}
+
+func (m invalidMatcher) Match(path string, value interface{}) error {
+	return fmt.Errorf("at %s: %w", path, m.err)
+}
+
+// expectation converts a typed expectation to interface{}. A nil Matcher, which would otherwise
+// silently expect null, becomes an invalidMatcher.
+func expectation[T any](v T) interface{} {
+	if _, ok := any(&v).(*Matcher); ok && any(v) == nil {
+		return invalidMatcher{errNilMatcher}
+	}
+	return v
+}
+
+// isNilMatcher reports whether m holds a nil pointer, function or map, such as a shared matcher
+// variable used before it was assigned.
+func isNilMatcher(m Matcher) bool {
+	v := reflect.ValueOf(m)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Func, reflect.Map, reflect.Slice, reflect.Interface, reflect.Chan:
+		return v.IsNil()
+	}
+	return false
+}
+
+// ValidateExpected checks an expectation for construction mistakes, such as invalid regular expressions,
+// ranges whose minimum is greater than their maximum, OneOf or Any without options and nil matchers,
+// so they surface at test setup rather than as confusing match failures. It reports every mistake found.
+func ValidateExpected(expected interface{}) error {
+	var errs []error
+	lintSchema("$", schemaOf(expected), &errs)
+	return errors.Join(errs...)
+}
+
+func lintSchema(path string, s *schema, errs *[]error) {
+	if s.invalid != nil {
+		*errs = append(*errs, fmt.Errorf("at %s: %w", path, s.invalid))
+		return
+	}
+
+	if s.pattern != "" {
+		if _, err := compileRegexp(s.pattern); err != nil {
+			*errs = append(*errs, fmt.Errorf("at %s: invalid pattern %q: %w", path, s.pattern, err))
+		}
+	}
+	lintRange(path, "number", s.minimum, s.maximum, errs)
+	lintRange(path, "string length", s.minLength, s.maxLength, errs)
+	lintRange(path, "object size", s.minProperties, s.maxProperties, errs)
+	lintRange(path, "array length", s.minItems, s.maxItems, errs)
+	if s.enum != nil && len(s.enum) == 0 {
+		*errs = append(*errs, fmt.Errorf("at %s: no options to match", path))
+	}
+	if s.anyOf != nil && len(s.anyOf) == 0 {
+		*errs = append(*errs, fmt.Errorf("at %s: no alternatives to match", path))
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(s.properties)) {
+		lintSchema(path+"."+key, s.properties[key], errs)
+	}
+	for _, pattern := range slices.Sorted(maps.Keys(s.patternProperties)) {
+		if _, err := compileRegexp(pattern); err != nil {
+			*errs = append(*errs, fmt.Errorf("at %s: invalid key pattern %q: %w", path, pattern, err))
+			continue
+		}
+		lintSchema(path+".*", s.patternProperties[pattern], errs)
+	}
+	for i, item := range s.prefixItems {
+		lintSchema(fmt.Sprintf("%s[%d]", path, i), item, errs)
+	}
+	for _, child := range []*schema{s.additional, s.propertyNames} {
+		if child != nil {
+			lintSchema(path+".*", child, errs)
+		}
+	}
+	if s.items != nil {
+		lintSchema(path+"[*]", s.items, errs)
+	}
+	for _, alternative := range s.anyOf {
+		lintSchema(path, alternative, errs)
+	}
+	for _, part := range s.allOf {
+		lintSchema(path, part, errs)
+	}
+	if s.not != nil {
+		lintSchema(path, s.not, errs)
+	}
+}
+
+func lintRange[T int | float64](path, kind string, min, max *T, errs *[]error) {
+	if min != nil && max != nil && *min > *max {
+		*errs = append(*errs, fmt.Errorf("at %s: invalid %s range, minimum %v is greater than maximum %v", path, kind, *min, *max))
+	}
+}
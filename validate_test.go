@@ -0,0 +1,97 @@
+package bodyguard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateExpected(t *testing.T) {
+	var unassigned *ObjectMatcher
+	var missing Matcher
+
+	tests := map[string]struct {
+		expected interface{}
+		wantErr  string
+	}{
+		"Valid Tree": {
+			expected: Object(map[string]any{
+				"id":     UUID(),
+				"name":   Regexp(`^[a-z]+$`),
+				"status": OneOf("active", "disabled"),
+				"tags":   Each(StringLength(1, 10)),
+				"score":  Any(Null(), NumberWithinRange(0, 100)),
+				"raw":    nil,
+			}),
+			wantErr: "",
+		},
+		"Invalid Regexp": {
+			expected: Object(map[string]any{"name": Regexp(`[a-z`)}),
+			wantErr:  "at $.name: invalid pattern \"[a-z\": error parsing regexp: missing closing ]",
+		},
+		"Invalid Key Pattern": {
+			expected: KeysMatching(`(`, String()),
+			wantErr:  "at $: invalid key pattern \"(\"",
+		},
+		"Number Range": {
+			expected: Object(map[string]any{"age": NumberWithinRange(100, 1)}),
+			wantErr:  "at $.age: invalid number range, minimum 100 is greater than maximum 1",
+		},
+		"String Length Range": {
+			expected: Each(StringLength(5, 2)),
+			wantErr:  "at $[*]: invalid string length range, minimum 5 is greater than maximum 2",
+		},
+		"Array Length Range": {
+			expected: Array(ArrayLength(3, 1)),
+			wantErr:  "at $[0]: invalid array length range, minimum 3 is greater than maximum 1",
+		},
+		"Empty OneOf": {
+			expected: Object(map[string]any{"status": OneOf()}),
+			wantErr:  "at $.status: no options to match",
+		},
+		"Empty Any": {
+			expected: Optional(Any()),
+			wantErr:  "at $: no alternatives to match",
+		},
+		"Nil Pointer Matcher": {
+			expected: Object(map[string]any{"user": unassigned}),
+			wantErr:  "at $.user: nil Matcher",
+		},
+		"Nil Matcher In Typed Map": {
+			expected: Object(map[string]Matcher{"id": missing}),
+			wantErr:  "at $.id: nil Matcher",
+		},
+		"Nil Matcher In Array": {
+			expected: ArrayOf(UUID(), missing),
+			wantErr:  "at $[1]: nil Matcher",
+		},
+		"Reports Every Mistake": {
+			expected: Object(map[string]any{"a": Regexp(`(`), "b": OneOf()}),
+			wantErr:  "at $.a: invalid pattern \"(\": error parsing regexp: missing closing ): `(`\nat $.b: no options to match",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateExpected(tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestNilMatcherFailsMatch(t *testing.T) {
+	var missing Matcher
+	err := isMatch(`{"id": null}`, Object(map[string]Matcher{"id": missing}))
+	if err == nil || !strings.Contains(err.Error(), "at $.id: nil Matcher") {
+		t.Errorf("Expected nil Matcher error, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package bodyguard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// defaultRequestTimeout bounds the requests sent by AssertGET and its siblings when neither ReadTimeout
+// nor a Client with a Timeout is set.
+const defaultRequestTimeout = 30 * time.Second
+
+// AssertGET sends a GET request to url, such as an httptest.Server URL, and checks that the response has
+// wantStatus and a body matching expected, as for DoAndAssert. The request accepts application/json and
+// times out after 30 seconds unless ReadTimeout is set. It is sent with http.DefaultClient, or the client
+// given by WithClient, e.g. for an httptest.NewTLSServer. It returns the decoded body for follow-up assertions.
+func AssertGET(t *testing.T, url string, wantStatus int, expected interface{}, opts ...Option) interface{} {
+	t.Helper()
+	return defaultEngine.AssertGET(t, url, wantStatus, expected, opts...)
+}
+
+// AssertGET sends a GET request to url and checks the response. See the package-level AssertGET.
+func (e *Engine) AssertGET(t *testing.T, url string, wantStatus int, expected interface{}, opts ...Option) interface{} {
+	t.Helper()
+	return e.assertSend(t, http.MethodGet, url, nil, wantStatus, expected, opts)
+}
+
+// AssertDELETE sends a DELETE request to url and checks the response, as for AssertGET.
+func AssertDELETE(t *testing.T, url string, wantStatus int, expected interface{}, opts ...Option) interface{} {
+	t.Helper()
+	return defaultEngine.AssertDELETE(t, url, wantStatus, expected, opts...)
+}
+
+// AssertDELETE sends a DELETE request to url and checks the response. See the package-level AssertGET.
+func (e *Engine) AssertDELETE(t *testing.T, url string, wantStatus int, expected interface{}, opts ...Option) interface{} {
+	t.Helper()
+	return e.assertSend(t, http.MethodDelete, url, nil, wantStatus, expected, opts)
+}
+
+// AssertPOST sends a POST request with body to url and checks the response, as for AssertGET.
+// A string or []byte body is sent as is and any other value is encoded as JSON.
+func AssertPOST(t *testing.T, url string, body interface{}, wantStatus int, expected interface{}, opts ...Option) interface{} {
+	t.Helper()
+	return defaultEngine.AssertPOST(t, url, body, wantStatus, expected, opts...)
+}
+
+// AssertPOST sends a POST request with body to url and checks the response. See the package-level AssertPOST.
+func (e *Engine) AssertPOST(t *testing.T, url string, body interface{}, wantStatus int, expected interface{}, opts ...Option) interface{} {
+	t.Helper()
+	return e.assertSend(t, http.MethodPost, url, body, wantStatus, expected, opts)
+}
+
+// AssertPUT sends a PUT request with body to url and checks the response, as for AssertPOST.
+func AssertPUT(t *testing.T, url string, body interface{}, wantStatus int, expected interface{}, opts ...Option) interface{} {
+	t.Helper()
+	return defaultEngine.AssertPUT(t, url, body, wantStatus, expected, opts...)
+}
+
+// AssertPUT sends a PUT request with body to url and checks the response. See the package-level AssertPOST.
+func (e *Engine) AssertPUT(t *testing.T, url string, body interface{}, wantStatus int, expected interface{}, opts ...Option) interface{} {
+	t.Helper()
+	return e.assertSend(t, http.MethodPut, url, body, wantStatus, expected, opts)
+}
+
+// AssertPATCH sends a PATCH request with body to url and checks the response, as for AssertPOST.
+func AssertPATCH(t *testing.T, url string, body interface{}, wantStatus int, expected interface{}, opts ...Option) interface{} {
+	t.Helper()
+	return defaultEngine.AssertPATCH(t, url, body, wantStatus, expected, opts...)
+}
+
+// AssertPATCH sends a PATCH request with body to url and checks the response. See the package-level AssertPOST.
+func (e *Engine) AssertPATCH(t *testing.T, url string, body interface{}, wantStatus int, expected interface{}, opts ...Option) interface{} {
+	t.Helper()
+	return e.assertSend(t, http.MethodPatch, url, body, wantStatus, expected, opts)
+}
+
+func (e *Engine) assertSend(t *testing.T, method, url string, body interface{}, wantStatus int, expected interface{}, opts []Option) interface{} {
+	t.Helper()
+	e = e.with(opts)
+	decoded, err := e.sendAndMatch(method, url, body, wantStatus, expected)
+	e.report(t, method+" "+url, expected, err)
+	return decoded
+}
+
+func (e *Engine) sendAndMatch(method, url string, body interface{}, wantStatus int, expected interface{}) (interface{}, error) {
+	req, err := newJSONRequest(method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, url, err)
+	}
+	if e.ReadTimeout <= 0 && (e.Client == nil || e.Client.Timeout <= 0) {
+		c := *e
+		c.ReadTimeout = defaultRequestTimeout
+		e = &c
+	}
+	return e.doAndMatch(e.Client, req, wantStatus, expected)
+}
+
+// newJSONRequest builds a request accepting JSON. A string or []byte body is sent as is,
+// any other non-nil body is encoded as JSON.
+func newJSONRequest(method, url string, body interface{}) (*http.Request, error) {
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reader = bytes.NewReader([]byte(b))
+	case []byte:
+		reader = bytes.NewReader(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode request body: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
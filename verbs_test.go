@@ -0,0 +1,127 @@
+package bodyguard
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSendAndMatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"method":       r.Method,
+			"accept":       r.Header.Get("Accept"),
+			"content_type": r.Header.Get("Content-Type"),
+			"body":         body,
+		})
+	}))
+	defer server.Close()
+
+	tests := map[string]struct {
+		method     string
+		body       interface{}
+		wantStatus int
+		expected   interface{}
+		wantErr    string
+	}{
+		"GET Pass": {
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			expected:   Object(map[string]any{"method": "GET", "accept": "application/json", "content_type": "", "body": nil}),
+			wantErr:    "",
+		},
+		"POST Encodes Body": {
+			method:     http.MethodPost,
+			body:       map[string]any{"name": "jdoe"},
+			wantStatus: http.StatusCreated,
+			expected:   Object(map[string]any{"content_type": "application/json", "body": map[string]any{"name": "jdoe"}}),
+			wantErr:    "",
+		},
+		"PUT Raw Body": {
+			method:     http.MethodPut,
+			body:       `{"qty": 2}`,
+			wantStatus: http.StatusOK,
+			expected:   Object(map[string]any{"method": "PUT", "body": Object(map[string]any{"qty": 2})}),
+			wantErr:    "",
+		},
+		"Status Mismatch": {
+			method:     http.MethodPatch,
+			body:       []byte(`{}`),
+			wantStatus: http.StatusNoContent,
+			expected:   Anything(),
+			wantErr:    "PATCH " + server.URL + "/items: expected status 204, got 200",
+		},
+		"Body Mismatch": {
+			method:     http.MethodDelete,
+			wantStatus: http.StatusOK,
+			expected:   Object(map[string]any{"method": "GET"}),
+			wantErr:    "DELETE " + server.URL + "/items: at $.method: expected GET (string), got DELETE (string)",
+		},
+		"Unencodable Body": {
+			method:     http.MethodPost,
+			body:       func() {},
+			wantStatus: http.StatusCreated,
+			expected:   Anything(),
+			wantErr:    "POST " + server.URL + "/items: cannot encode request body",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := defaultEngine.sendAndMatch(tt.method, server.URL+"/items", tt.body, tt.wantStatus, tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Expected error containing %q, got nil", tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestAssertGET(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	body := AssertGET(t, server.URL+"/users/1", http.StatusOK, Object(map[string]any{"id": 1}))
+	if !reflect.DeepEqual(body, map[string]any{"id": 1.0}) {
+		t.Errorf("Expected the decoded body, got %v", body)
+	}
+}
+
+func TestAssertGETWithClient(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	if _, err := defaultEngine.sendAndMatch(http.MethodGet, server.URL, nil, http.StatusOK, Anything()); err == nil {
+		t.Error("Expected the default client to reject the test certificate")
+	}
+
+	engine := defaultEngine.with([]Option{WithClient(server.Client())})
+	if _, err := engine.sendAndMatch(http.MethodGet, server.URL, nil, http.StatusOK, Object(map[string]any{"id": 1})); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	AssertGET(t, server.URL, http.StatusOK, Object(map[string]any{"id": 1}), WithClient(server.Client()))
+}